@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sadopc/trackr/internal/store"
+)
+
+const usage = `usage: trackr [--db path] [command]
+
+Without a command, trackr launches the interactive TUI.
+
+The database is --db if given, else $TRACKR_DB, else the default location.
+
+Commands:
+  start <project>   Start a timer on project (created if missing)
+  stop              Stop the running timer
+  status [--json]   Print the running timer, or "stopped"`
+
+var errUsage = errors.New(usage)
+
+// runCommand executes a single non-interactive subcommand and writes its
+// one-line result to w.
+func runCommand(s *store.Store, args []string, w io.Writer) error {
+	switch args[0] {
+	case "start":
+		if len(args) < 2 || args[1] == "" {
+			return errUsage
+		}
+		return cmdStart(s, args[1], w)
+	case "stop":
+		return cmdStop(s, w)
+	case "status":
+		fs := flag.NewFlagSet("status", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		asJSON := fs.Bool("json", false, "")
+		if err := fs.Parse(args[1:]); err != nil {
+			return errUsage
+		}
+		if *asJSON {
+			return cmdStatusJSON(s, w)
+		}
+		return cmdStatus(s, w)
+	case "help", "-h", "--help":
+		fmt.Fprintln(w, usage)
+		return nil
+	}
+	return errUsage
+}
+
+func cmdStart(s *store.Store, name string, w io.Writer) error {
+	running, err := s.GetRunningEntry()
+	if err != nil {
+		return err
+	}
+	if running != nil {
+		p, err := s.GetProject(running.ProjectID)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("timer already running on %s", p.Name)
+	}
+
+	p, err := s.GetOrCreateProject(name)
+	if err != nil {
+		return err
+	}
+	if _, err := s.StartEntry(p.ID, nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "started %s\n", p.Name)
+	return nil
+}
+
+func cmdStop(s *store.Store, w io.Writer) error {
+	running, err := s.GetRunningEntry()
+	if err != nil {
+		return err
+	}
+	if running == nil {
+		return errors.New("no timer running")
+	}
+
+	entry, err := s.StopEntry(running.ID)
+	if err != nil {
+		return err
+	}
+	p, err := s.GetProject(entry.ProjectID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "stopped %s  %s\n", p.Name, formatDuration(time.Duration(entry.Duration)*time.Second))
+	return nil
+}
+
+func cmdStatus(s *store.Store, w io.Writer) error {
+	running, err := s.GetRunningEntry()
+	if err != nil {
+		return err
+	}
+	if running == nil {
+		fmt.Fprintln(w, "stopped")
+		return nil
+	}
+
+	p, err := s.GetProject(running.ProjectID)
+	if err != nil {
+		return err
+	}
+	elapsed, paused, err := runningElapsed(s, running)
+	if err != nil {
+		return err
+	}
+	mark := "●"
+	if paused {
+		mark = "⏸"
+	}
+	fmt.Fprintf(w, "%s  %s  %s\n", mark, p.Name, formatDuration(elapsed))
+	return nil
+}
+
+// runningElapsed is how long a running entry has been timed so far, leaving
+// out paused time as StopEntry will, and whether it is paused now.
+func runningElapsed(s *store.Store, e *store.TimeEntry) (time.Duration, bool, error) {
+	now := time.Now()
+	st, err := s.GetTimerState(e.ID)
+	if err != nil {
+		return 0, false, err
+	}
+	paused, err := s.PausedSeconds(e.ID, now)
+	if err != nil {
+		return 0, false, err
+	}
+	elapsed := max(now.Sub(e.StartTime)-time.Duration(paused)*time.Second, 0)
+	return elapsed, st != nil && st.PausedAt != nil, nil
+}
+
+// statusJSON is the stable machine-readable form of `trackr status --json`
+// for a running timer. Task is null when the entry has no task.
+type statusJSON struct {
+	Running        bool    `json:"running"`
+	Project        string  `json:"project"`
+	Task           *string `json:"task"`
+	ElapsedSeconds int64   `json:"elapsed_seconds"`
+	Paused         bool    `json:"paused"`
+}
+
+// stoppedJSON is printed by `trackr status --json` when nothing is running.
+type stoppedJSON struct {
+	Running bool `json:"running"`
+}
+
+// cmdStatusJSON prints the running timer as a single JSON line. Elapsed is
+// computed from the entry's start_time in the database, less paused time.
+func cmdStatusJSON(s *store.Store, w io.Writer) error {
+	running, err := s.GetRunningEntry()
+	if err != nil {
+		return err
+	}
+	if running == nil {
+		return json.NewEncoder(w).Encode(stoppedJSON{Running: false})
+	}
+
+	p, err := s.GetProject(running.ProjectID)
+	if err != nil {
+		return err
+	}
+	elapsed, paused, err := runningElapsed(s, running)
+	if err != nil {
+		return err
+	}
+	out := statusJSON{
+		Running:        true,
+		Project:        p.Name,
+		ElapsedSeconds: int64(elapsed.Seconds()),
+		Paused:         paused,
+	}
+	if running.TaskID != nil {
+		t, err := s.GetTask(*running.TaskID)
+		if err != nil {
+			return err
+		}
+		out.Task = &t.Name
+	}
+	return json.NewEncoder(w).Encode(out)
+}
+
+func formatDuration(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+}
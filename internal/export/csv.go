@@ -9,7 +9,9 @@ import (
 	"github.com/sadopc/trackr/internal/store"
 )
 
-func ToCSV(entries []store.TimeEntry, projects map[int64]*store.Project, path string) error {
+// ToCSV writes entries to path, with the human-readable duration column
+// in durationFormat, one of the Duration constants.
+func ToCSV(entries []store.TimeEntry, projects map[int64]store.Project, path, durationFormat string) error {
 	f, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("create csv file: %w", err)
@@ -20,7 +22,7 @@ func ToCSV(entries []store.TimeEntry, projects map[int64]*store.Project, path st
 	defer w.Flush()
 
 	// Header
-	if err := w.Write([]string{"ID", "Project", "Start", "End", "Duration (s)", "Duration", "Notes"}); err != nil {
+	if err := w.Write([]string{"ID", "Project", "Start", "End", "Duration (s)", "Duration", "Notes", "Billable", "Revenue", "Tags", "Status"}); err != nil {
 		return err
 	}
 
@@ -33,7 +35,7 @@ func ToCSV(entries []store.TimeEntry, projects map[int64]*store.Project, path st
 		if e.EndTime != nil {
 			endStr = e.EndTime.Local().Format(time.RFC3339)
 		}
-		dur := formatDuration(e.Duration)
+		dur := FormatDuration(e.Duration, durationFormat)
 
 		row := []string{
 			fmt.Sprintf("%d", e.ID),
@@ -43,6 +45,10 @@ func ToCSV(entries []store.TimeEntry, projects map[int64]*store.Project, path st
 			fmt.Sprintf("%d", e.Duration),
 			dur,
 			e.Notes,
+			fmt.Sprintf("%t", e.Billable),
+			fmt.Sprintf("%.2f", entryRevenue(e, projects)),
+			e.Tags,
+			entryStatus(e),
 		}
 		if err := w.Write(row); err != nil {
 			return err
@@ -52,9 +58,20 @@ func ToCSV(entries []store.TimeEntry, projects map[int64]*store.Project, path st
 	return w.Error()
 }
 
-func formatDuration(secs int64) string {
-	h := secs / 3600
-	m := (secs % 3600) / 60
-	s := secs % 60
-	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+// entryRevenue is what a billable entry earns at its project's hourly rate.
+func entryRevenue(e store.TimeEntry, projects map[int64]store.Project) float64 {
+	p, ok := projects[e.ProjectID]
+	if !ok || !e.Billable {
+		return 0
+	}
+	return store.Revenue(p.HourlyRate, e.Duration)
+}
+
+// entryStatus is "running" for an entry without an end time, whose
+// duration is still 0, and "completed" otherwise.
+func entryStatus(e store.TimeEntry) string {
+	if e.EndTime == nil {
+		return "running"
+	}
+	return "completed"
 }
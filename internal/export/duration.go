@@ -0,0 +1,32 @@
+package export
+
+import "fmt"
+
+// Duration formats for the duration_format setting.
+const (
+	DurationHMS     = "hms"     // 01:30:00
+	DurationCompact = "compact" // 1h30m
+	DurationDecimal = "decimal" // 1.5h
+)
+
+// FormatDuration renders secs in format, one of the Duration constants;
+// anything else is treated as DurationHMS. A negative duration, which only
+// a malformed row could have, renders as zero.
+func FormatDuration(secs int64, format string) string {
+	secs = max(secs, 0)
+	h := secs / 3600
+	m := (secs % 3600) / 60
+	switch format {
+	case DurationCompact:
+		if h == 0 {
+			return fmt.Sprintf("%dm", m)
+		}
+		if m == 0 {
+			return fmt.Sprintf("%dh", h)
+		}
+		return fmt.Sprintf("%dh%02dm", h, m)
+	case DurationDecimal:
+		return fmt.Sprintf("%.1fh", float64(secs)/3600)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", h, m, secs%60)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -12,7 +13,7 @@ import (
 	"github.com/sadopc/trackr/internal/store"
 )
 
-func sampleData() ([]store.TimeEntry, map[int64]*store.Project) {
+func sampleData() ([]store.TimeEntry, map[int64]store.Project) {
 	now := time.Now().UTC()
 	end := now
 	tid := int64(10)
@@ -26,6 +27,8 @@ func sampleData() ([]store.TimeEntry, map[int64]*store.Project) {
 			EndTime:   &end,
 			Duration:  3600,
 			Notes:     "worked on feature",
+			Tags:      "interrupt,support",
+			Billable:  true,
 			CreatedAt: now,
 		},
 		{
@@ -49,8 +52,8 @@ func sampleData() ([]store.TimeEntry, map[int64]*store.Project) {
 		},
 	}
 
-	projects := map[int64]*store.Project{
-		1: {ID: 1, Name: "Project Alpha", Color: "#FF0000"},
+	projects := map[int64]store.Project{
+		1: {ID: 1, Name: "Project Alpha", Color: "#FF0000", HourlyRate: 80},
 		2: {ID: 2, Name: "Project Beta", Color: "#00FF00"},
 	}
 
@@ -65,7 +68,7 @@ func TestToCSV(t *testing.T) {
 	entries, projects := sampleData()
 	path := filepath.Join(t.TempDir(), "test.csv")
 
-	err := ToCSV(entries, projects, path)
+	err := ToCSV(entries, projects, path, DurationHMS)
 	if err != nil {
 		t.Fatalf("ToCSV: %v", err)
 	}
@@ -89,7 +92,7 @@ func TestToCSV(t *testing.T) {
 
 	// Check header
 	header := records[0]
-	expectedHeader := []string{"ID", "Project", "Start", "End", "Duration (s)", "Duration", "Notes"}
+	expectedHeader := []string{"ID", "Project", "Start", "End", "Duration (s)", "Duration", "Notes", "Billable", "Revenue", "Tags", "Status"}
 	for i, h := range expectedHeader {
 		if header[i] != h {
 			t.Fatalf("header[%d] = %q, want %q", i, header[i], h)
@@ -113,18 +116,33 @@ func TestToCSV(t *testing.T) {
 	if row[6] != "worked on feature" {
 		t.Fatalf("Notes = %q, want 'worked on feature'", row[6])
 	}
+	if row[9] != "interrupt,support" || records[2][9] != "" {
+		t.Fatalf("Tags = %q/%q", row[9], records[2][9])
+	}
+	if row[7] != "true" || records[2][7] != "false" {
+		t.Fatalf("Billable = %q/%q, want true/false", row[7], records[2][7])
+	}
+	if row[8] != "80.00" || records[2][8] != "0.00" {
+		t.Fatalf("Revenue = %q/%q, want 80.00/0.00", row[8], records[2][8])
+	}
 
 	// Check running entry has empty end time
 	runningRow := records[3]
 	if runningRow[3] != "" {
 		t.Fatalf("running entry should have empty end time, got %q", runningRow[3])
 	}
+	if runningRow[10] != "running" || row[10] != "completed" {
+		t.Fatalf("Status = %q/%q, want running/completed", runningRow[10], row[10])
+	}
+	if runningRow[5] != "00:00:00" {
+		t.Fatalf("running entry duration = %q, want 00:00:00", runningRow[5])
+	}
 }
 
 func TestToCSVEmpty(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "empty.csv")
 
-	err := ToCSV(nil, nil, path)
+	err := ToCSV(nil, nil, path, DurationHMS)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -149,7 +167,7 @@ func TestToCSVUnknownProject(t *testing.T) {
 	}
 	path := filepath.Join(t.TempDir(), "unknown.csv")
 
-	err := ToCSV(entries, map[int64]*store.Project{}, path)
+	err := ToCSV(entries, map[int64]store.Project{}, path, DurationHMS)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -164,7 +182,7 @@ func TestToCSVUnknownProject(t *testing.T) {
 }
 
 func TestToCSVBadPath(t *testing.T) {
-	err := ToCSV(nil, nil, "/nonexistent/dir/file.csv")
+	err := ToCSV(nil, nil, "/nonexistent/dir/file.csv", DurationHMS)
 	if err == nil {
 		t.Fatal("expected error for bad path")
 	}
@@ -183,12 +201,12 @@ func TestToCSVSpecialCharacters(t *testing.T) {
 			Notes:     `notes with "quotes" and, commas`,
 		},
 	}
-	projects := map[int64]*store.Project{
+	projects := map[int64]store.Project{
 		1: {ID: 1, Name: `Project "Special"`},
 	}
 	path := filepath.Join(t.TempDir(), "special.csv")
 
-	err := ToCSV(entries, projects, path)
+	err := ToCSV(entries, projects, path, DurationHMS)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -216,7 +234,7 @@ func TestToJSON(t *testing.T) {
 	entries, projects := sampleData()
 	path := filepath.Join(t.TempDir(), "test.json")
 
-	err := ToJSON(entries, projects, path)
+	err := ToJSON(entries, projects, path, DurationHMS)
 	if err != nil {
 		t.Fatalf("ToJSON: %v", err)
 	}
@@ -258,18 +276,30 @@ func TestToJSON(t *testing.T) {
 	if e.Notes != "worked on feature" {
 		t.Fatalf("Notes = %q", e.Notes)
 	}
+	if !slices.Equal(e.Tags, []string{"interrupt", "support"}) || result.Entries[1].Tags != nil {
+		t.Fatalf("Tags = %v/%v", e.Tags, result.Entries[1].Tags)
+	}
+	if !e.Billable || result.Entries[1].Billable {
+		t.Fatal("billable flag should be exported per entry")
+	}
+	if e.Revenue != 80 || result.Entries[1].Revenue != 0 {
+		t.Fatalf("Revenue = %v/%v, want 80/0", e.Revenue, result.Entries[1].Revenue)
+	}
 
 	// Running entry should have empty end_time
 	running := result.Entries[2]
 	if running.EndTime != "" {
 		t.Fatalf("running entry end_time should be empty, got %q", running.EndTime)
 	}
+	if running.Status != "running" || result.Entries[0].Status != "completed" {
+		t.Fatalf("status = %q/%q, want running/completed", running.Status, result.Entries[0].Status)
+	}
 }
 
 func TestToJSONEmpty(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "empty.json")
 
-	err := ToJSON(nil, nil, path)
+	err := ToJSON(nil, nil, path, DurationHMS)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -292,7 +322,7 @@ func TestToJSONUnknownProject(t *testing.T) {
 	}
 	path := filepath.Join(t.TempDir(), "unknown.json")
 
-	ToJSON(entries, map[int64]*store.Project{}, path)
+	ToJSON(entries, map[int64]store.Project{}, path, DurationHMS)
 
 	data, _ := os.ReadFile(path)
 	var result jsonExport
@@ -303,7 +333,7 @@ func TestToJSONUnknownProject(t *testing.T) {
 }
 
 func TestToJSONBadPath(t *testing.T) {
-	err := ToJSON(nil, nil, "/nonexistent/dir/file.json")
+	err := ToJSON(nil, nil, "/nonexistent/dir/file.json", DurationHMS)
 	if err == nil {
 		t.Fatal("expected error for bad path")
 	}
@@ -311,7 +341,7 @@ func TestToJSONBadPath(t *testing.T) {
 
 func TestToJSONPrettyPrinted(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "pretty.json")
-	ToJSON(nil, nil, path)
+	ToJSON(nil, nil, path, DurationHMS)
 
 	data, _ := os.ReadFile(path)
 	// Pretty-printed JSON should contain newlines and indentation
@@ -326,7 +356,7 @@ func TestToJSONPrettyPrinted(t *testing.T) {
 func TestToJSONValidTimestamps(t *testing.T) {
 	entries, projects := sampleData()
 	path := filepath.Join(t.TempDir(), "ts.json")
-	ToJSON(entries, projects, path)
+	ToJSON(entries, projects, path, DurationHMS)
 
 	data, _ := os.ReadFile(path)
 	var result jsonExport
@@ -348,27 +378,187 @@ func TestToJSONValidTimestamps(t *testing.T) {
 }
 
 // ============================================================
-// formatDuration (internal helper)
+// Summaries
+// ============================================================
+
+func sampleSummaries() []store.DailySummary {
+	return []store.DailySummary{
+		{Date: "2024-01-15", ProjectID: 1, ProjectName: "Project Alpha", TotalSeconds: 5400, EntryCount: 2},
+		{Date: "2024-01-16", ProjectID: 2, ProjectName: "Project Beta", TotalSeconds: 600, EntryCount: 1},
+	}
+}
+
+func TestSummariesToCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	if err := SummariesToCSV(sampleSummaries(), path, DurationHMS); err != nil {
+		t.Fatalf("SummariesToCSV: %v", err)
+	}
+
+	f, _ := os.Open(path)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d", len(records))
+	}
+	want := []string{"Date", "Project", "Duration (s)", "Duration", "Entries"}
+	if strings.Join(records[0], ",") != strings.Join(want, ",") {
+		t.Fatalf("header = %v, want %v", records[0], want)
+	}
+	if got := strings.Join(records[1], ","); got != "2024-01-15,Project Alpha,5400,01:30:00,2" {
+		t.Fatalf("row = %q", got)
+	}
+}
+
+func TestSummariesToJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := SummariesToJSON(sampleSummaries(), path, DurationHMS); err != nil {
+		t.Fatalf("SummariesToJSON: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var result jsonSummaryExport
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result.Count != 2 || len(result.Summaries) != 2 {
+		t.Fatalf("count = %d, summaries = %d, want 2", result.Count, len(result.Summaries))
+	}
+	s := result.Summaries[0]
+	if s.Date != "2024-01-15" || s.Project != "Project Alpha" || s.DurationSec != 5400 || s.Duration != "01:30:00" || s.Entries != 2 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+}
+
+func TestSummariesBadPath(t *testing.T) {
+	if err := SummariesToCSV(nil, "/nonexistent/dir/report.csv", DurationHMS); err == nil {
+		t.Fatal("expected error for bad CSV path")
+	}
+	if err := SummariesToJSON(nil, "/nonexistent/dir/report.json", DurationHMS); err == nil {
+		t.Fatal("expected error for bad JSON path")
+	}
+}
+
+func TestTextToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.txt")
+	if err := TextToFile("\x1b[38;5;212m█ Dev\x1b[0m   \n  legend  \n\n", path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if got, want := string(data), "█ Dev\n  legend\n"; got != want {
+		t.Fatalf("text = %q, want %q", got, want)
+	}
+	if err := TextToFile("x", "/nonexistent/dir/chart.txt"); err == nil {
+		t.Fatal("expected error for bad text path")
+	}
+}
+
+func TestSettingsFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	want := map[string]string{"daily_goal": "28800", "sound": "false", "unknown_key": "v"}
+	if err := SettingsToFile(want, path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := SettingsFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	os.WriteFile(bad, []byte(`{"entries": []}`), 0o644)
+	if _, err := SettingsFromFile(bad); err == nil {
+		t.Fatal("a file without settings should be rejected")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		tmpl, format, want string
+	}{
+		{DefaultFileNameTemplate, "csv", "trackr-export-2024-01-15.csv"},
+		{"timesheet-{range}.csv", "csv", "timesheet-2024-01-08_2024-01-14.csv"},
+		{"timesheet-{range}", "json", "timesheet-2024-01-08_2024-01-14.json"},
+		{"../team/{date}", "csv", "-team-2024-01-15.csv"},
+		{"a:b*c?.{format}", "json", "a-b-c-.json"},
+	}
+	for _, tt := range tests {
+		got, err := FileName(tt.tmpl, "2024-01-15", "2024-01-08_2024-01-14", tt.format)
+		if err != nil || got != tt.want {
+			t.Errorf("FileName(%q) = %q, %v; want %q", tt.tmpl, got, err, tt.want)
+		}
+	}
+	for _, tmpl := range []string{"", " . ", ".."} {
+		if _, err := FileName(tmpl, "2024-01-15", "", "csv"); err == nil {
+			t.Errorf("FileName(%q) should fail", tmpl)
+		}
+	}
+}
+
+// ============================================================
+// FormatDuration
 // ============================================================
 
 func TestFormatDuration(t *testing.T) {
 	tests := []struct {
-		secs int64
-		want string
+		secs   int64
+		format string
+		want   string
 	}{
-		{0, "00:00:00"},
-		{1, "00:00:01"},
-		{60, "00:01:00"},
-		{3600, "01:00:00"},
-		{3661, "01:01:01"},
-		{86400, "24:00:00"},
-		{90061, "25:01:01"},
+		{0, DurationHMS, "00:00:00"},
+		{1, DurationHMS, "00:00:01"},
+		{60, DurationHMS, "00:01:00"},
+		{3600, DurationHMS, "01:00:00"},
+		{3661, DurationHMS, "01:01:01"},
+		{86400, DurationHMS, "24:00:00"},
+		{90061, DurationHMS, "25:01:01"},
+		{-5, DurationHMS, "00:00:00"},
+		{5400, "", "01:30:00"},
+		{5400, "bogus", "01:30:00"},
+
+		{0, DurationCompact, "0m"},
+		{59, DurationCompact, "0m"},
+		{2700, DurationCompact, "45m"},
+		{3600, DurationCompact, "1h"},
+		{3900, DurationCompact, "1h05m"},
+		{5400, DurationCompact, "1h30m"},
+		{90061, DurationCompact, "25h01m"},
+		{-5, DurationCompact, "0m"},
+
+		{0, DurationDecimal, "0.0h"},
+		{900, DurationDecimal, "0.2h"},
+		{5400, DurationDecimal, "1.5h"},
+		{30600, DurationDecimal, "8.5h"},
+		{-5, DurationDecimal, "0.0h"},
 	}
 
 	for _, tt := range tests {
-		got := formatDuration(tt.secs)
+		got := FormatDuration(tt.secs, tt.format)
 		if got != tt.want {
-			t.Errorf("formatDuration(%d) = %q, want %q", tt.secs, got, tt.want)
+			t.Errorf("FormatDuration(%d, %q) = %q, want %q", tt.secs, tt.format, got, tt.want)
 		}
 	}
 }
+
+func TestToCSVDurationFormat(t *testing.T) {
+	entries, projects := sampleData()
+	path := filepath.Join(t.TempDir(), "out.csv")
+	if err := ToCSV(entries, projects, path, DurationCompact); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.Open(path)
+	defer f.Close()
+	records, _ := csv.NewReader(f).ReadAll()
+	if records[1][4] != "3600" || records[1][5] != "1h" {
+		t.Fatalf("duration columns = %q, %q, want seconds and the compact form", records[1][4], records[1][5])
+	}
+}
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultFileNameTemplate names entry exports when export_filename_template
+// is unset, e.g. "trackr-export-2024-01-15.csv".
+const DefaultFileNameTemplate = "trackr-export-{date}.{format}"
+
+// FileName expands the {date}, {range} and {format} tokens in tmpl and
+// makes the result safe to use as a file name: path separators and other
+// reserved characters become "-", and the format's extension is added when
+// the name does not already end with it.
+func FileName(tmpl, date, dateRange, format string) (string, error) {
+	name := strings.NewReplacer("{date}", date, "{range}", dateRange, "{format}", format).Replace(tmpl)
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return "", fmt.Errorf("file name template %q gives an empty name", tmpl)
+	}
+	if ext := "." + format; !strings.HasSuffix(name, ext) {
+		name += ext
+	}
+	return name, nil
+}
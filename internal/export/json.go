@@ -16,17 +16,22 @@ type jsonExport struct {
 }
 
 type jsonEntry struct {
-	ID          int64   `json:"id"`
-	Project     string  `json:"project"`
-	ProjectID   int64   `json:"project_id"`
-	StartTime   string  `json:"start_time"`
-	EndTime     string  `json:"end_time,omitempty"`
-	DurationSec int64   `json:"duration_seconds"`
-	Duration    string  `json:"duration"`
-	Notes       string  `json:"notes,omitempty"`
+	ID          int64    `json:"id"`
+	Project     string   `json:"project"`
+	ProjectID   int64    `json:"project_id"`
+	StartTime   string   `json:"start_time"`
+	EndTime     string   `json:"end_time,omitempty"`
+	DurationSec int64    `json:"duration_seconds"`
+	Duration    string   `json:"duration"`
+	Notes       string   `json:"notes,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Billable    bool     `json:"billable"`
+	Revenue     float64  `json:"revenue"`
+	Status      string   `json:"status"`
 }
 
-func ToJSON(entries []store.TimeEntry, projects map[int64]*store.Project, path string) error {
+// ToJSON is ToCSV as JSON.
+func ToJSON(entries []store.TimeEntry, projects map[int64]store.Project, path, durationFormat string) error {
 	export := jsonExport{
 		ExportedAt: time.Now().UTC().Format(time.RFC3339),
 		Count:      len(entries),
@@ -49,8 +54,12 @@ func ToJSON(entries []store.TimeEntry, projects map[int64]*store.Project, path s
 			StartTime:   e.StartTime.Local().Format(time.RFC3339),
 			EndTime:     endStr,
 			DurationSec: e.Duration,
-			Duration:    formatDuration(e.Duration),
+			Duration:    FormatDuration(e.Duration, durationFormat),
 			Notes:       e.Notes,
+			Tags:        store.ParseTags(e.Tags),
+			Billable:    e.Billable,
+			Revenue:     entryRevenue(e, projects),
+			Status:      entryStatus(e),
 		})
 	}
 
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type settingsFile struct {
+	ExportedAt string            `json:"exported_at"`
+	Settings   map[string]string `json:"settings"`
+}
+
+// SettingsToFile writes settings to path as JSON.
+func SettingsToFile(settings map[string]string, path string) error {
+	data, err := json.MarshalIndent(settingsFile{
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Settings:   settings,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write settings file: %w", err)
+	}
+	return nil
+}
+
+// SettingsFromFile reads settings written by SettingsToFile.
+func SettingsFromFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f settingsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("read settings %s: %w", path, err)
+	}
+	if f.Settings == nil {
+		return nil, fmt.Errorf("read settings %s: no settings found", path)
+	}
+	return f.Settings, nil
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sadopc/trackr/internal/store"
+)
+
+// SummariesToCSV writes per-day, per-project totals, one row per summary,
+// with durations in durationFormat as in ToCSV.
+func SummariesToCSV(summaries []store.DailySummary, path, durationFormat string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create csv file: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	if err := w.Write([]string{"Date", "Project", "Duration (s)", "Duration", "Entries"}); err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		row := []string{
+			s.Date,
+			s.ProjectName,
+			fmt.Sprintf("%d", s.TotalSeconds),
+			FormatDuration(s.TotalSeconds, durationFormat),
+			fmt.Sprintf("%d", s.EntryCount),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return w.Error()
+}
+
+type jsonSummaryExport struct {
+	ExportedAt string        `json:"exported_at"`
+	Count      int           `json:"count"`
+	Summaries  []jsonSummary `json:"summaries"`
+}
+
+type jsonSummary struct {
+	Date        string `json:"date"`
+	Project     string `json:"project"`
+	ProjectID   int64  `json:"project_id"`
+	DurationSec int64  `json:"duration_seconds"`
+	Duration    string `json:"duration"`
+	Entries     int    `json:"entries"`
+}
+
+// SummariesToJSON is SummariesToCSV as JSON.
+func SummariesToJSON(summaries []store.DailySummary, path, durationFormat string) error {
+	export := jsonSummaryExport{
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Count:      len(summaries),
+	}
+	for _, s := range summaries {
+		export.Summaries = append(export.Summaries, jsonSummary{
+			Date:        s.Date,
+			Project:     s.ProjectName,
+			ProjectID:   s.ProjectID,
+			DurationSec: s.TotalSeconds,
+			Duration:    FormatDuration(s.TotalSeconds, durationFormat),
+			Entries:     s.EntryCount,
+		})
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write json file: %w", err)
+	}
+	return nil
+}
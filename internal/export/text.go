@@ -0,0 +1,24 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TextToFile writes a rendered view to path as plain text. ANSI escape codes
+// and trailing spaces are removed so the file pastes cleanly into email or
+// another terminal.
+func TextToFile(text, path string) error {
+	lines := strings.Split(ansi.Strip(text), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	data := strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("write text file: %w", err)
+	}
+	return nil
+}
@@ -3,14 +3,26 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
 func (s *Store) StartEntry(projectID int64, taskID *int64) (*TimeEntry, error) {
-	now := time.Now().UTC().Format(time.RFC3339)
+	return s.StartEntryAt(projectID, taskID, time.Now())
+}
+
+// StartEntryAt starts a running entry with an explicit start time, for
+// timers started after the work began. Start times in the future are
+// rejected.
+func (s *Store) StartEntryAt(projectID int64, taskID *int64, start time.Time) (*TimeEntry, error) {
+	now := time.Now()
+	if start.After(now) {
+		return nil, fmt.Errorf("start entry: start time %s is in the future", start.Format(time.RFC3339))
+	}
 	res, err := s.db.Exec(
 		`INSERT INTO time_entries (project_id, task_id, start_time, created_at) VALUES (?, ?, ?, ?)`,
-		projectID, taskID, now, now,
+		projectID, taskID, start.UTC().Format(time.RFC3339), now.UTC().Format(time.RFC3339),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("start entry: %w", err)
@@ -19,9 +31,17 @@ func (s *Store) StartEntry(projectID int64, taskID *int64) (*TimeEntry, error) {
 	return s.GetEntry(id)
 }
 
+// StopEntry ends a running entry now. Its duration excludes any time the
+// timer recorded as paused.
 func (s *Store) StopEntry(id int64) (*TimeEntry, error) {
-	now := time.Now().UTC()
-	nowStr := now.Format(time.RFC3339)
+	return s.StopEntryAt(id, time.Now())
+}
+
+// StopEntryAt ends a running entry at end, for a timer that should have
+// been stopped earlier. The end must not be before the entry's start. Like
+// StopEntry, the duration excludes time recorded as paused before end.
+func (s *Store) StopEntryAt(id int64, end time.Time) (*TimeEntry, error) {
+	end = end.UTC()
 
 	// Get start_time to compute duration.
 	var startStr string
@@ -30,30 +50,110 @@ func (s *Store) StopEntry(id int64) (*TimeEntry, error) {
 		return nil, fmt.Errorf("get entry start: %w", err)
 	}
 	start, _ := time.Parse(time.RFC3339, startStr)
-	duration := int64(now.Sub(start).Seconds())
+	if end.Truncate(time.Second).Before(start) {
+		return nil, fmt.Errorf("stop entry: end %s is before its start %s",
+			end.Local().Format("15:04"), start.Local().Format("15:04"))
+	}
+	paused, err := s.PausedSeconds(id, end)
+	if err != nil {
+		return nil, err
+	}
+	duration := max(int64(end.Sub(start).Seconds())-paused, 0)
 
 	_, err = s.db.Exec(
 		`UPDATE time_entries SET end_time = ?, duration = ? WHERE id = ?`,
-		nowStr, duration, id,
+		end.Format(time.RFC3339), duration, id,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("stop entry: %w", err)
 	}
+	if _, err := s.db.Exec(`DELETE FROM timer_state WHERE entry_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("clear timer state: %w", err)
+	}
 	return s.GetEntry(id)
 }
 
-func (s *Store) GetEntry(id int64) (*TimeEntry, error) {
+// StopAllRunning ends every running entry now, as StopEntry would, and
+// returns how many it stopped.
+func (s *Store) StopAllRunning() (int64, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("stop all: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT e.id, e.start_time, COALESCE(ts.pause_gap, 0), ts.paused_at
+		FROM time_entries e
+		LEFT JOIN timer_state ts ON ts.entry_id = e.id
+		WHERE e.end_time IS NULL`)
+	if err != nil {
+		return 0, fmt.Errorf("list running entries: %w", err)
+	}
+	durations := make(map[int64]int64)
+	for rows.Next() {
+		var id, paused int64
+		var startStr string
+		var pausedAt sql.NullString
+		if err := rows.Scan(&id, &startStr, &paused, &pausedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		start, _ := time.Parse(time.RFC3339, startStr)
+		if pausedAt.Valid {
+			if t, err := time.Parse(time.RFC3339, pausedAt.String); err == nil && now.After(t) {
+				paused += int64(now.Sub(t).Seconds())
+			}
+		}
+		durations[id] = max(int64(now.Sub(start).Seconds())-paused, 0)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	nowStr := now.Format(time.RFC3339)
+	for id, duration := range durations {
+		if _, err := tx.Exec(
+			`UPDATE time_entries SET end_time = ?, duration = ? WHERE id = ?`, nowStr, duration, id,
+		); err != nil {
+			return 0, fmt.Errorf("stop entry %d: %w", id, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM timer_state WHERE entry_id = ?`, id); err != nil {
+			return 0, fmt.Errorf("clear timer state: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("stop all: %w", err)
+	}
+	return int64(len(durations)), nil
+}
+
+const entryColumns = `id, project_id, task_id, start_time, end_time, duration, notes, tags, billable, archived, created_at`
+
+// qualifiedEntryColumns returns entryColumns with each column prefixed, for
+// queries that join other tables.
+func qualifiedEntryColumns(prefix string) string {
+	cols := strings.Split(entryColumns, ", ")
+	for i, c := range cols {
+		cols[i] = prefix + c
+	}
+	return strings.Join(cols, ", ")
+}
+
+// scanEntry reads a row selected with entryColumns, followed by any extra
+// columns scanned into extra.
+func scanEntry(row interface{ Scan(...any) error }, extra ...any) (*TimeEntry, error) {
 	e := &TimeEntry{}
 	var startTime, createdAt string
 	var endTime sql.NullString
 	var taskID sql.NullInt64
-
-	err := s.db.QueryRow(
-		`SELECT id, project_id, task_id, start_time, end_time, duration, notes, created_at
-		 FROM time_entries WHERE id = ?`, id,
-	).Scan(&e.ID, &e.ProjectID, &taskID, &startTime, &endTime, &e.Duration, &e.Notes, &createdAt)
-	if err != nil {
-		return nil, fmt.Errorf("get entry %d: %w", id, err)
+	var billable, archived int
+	dest := append([]any{&e.ID, &e.ProjectID, &taskID, &startTime, &endTime, &e.Duration, &e.Notes, &e.Tags, &billable, &archived, &createdAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
 	}
 	if taskID.Valid {
 		e.TaskID = &taskID.Int64
@@ -63,60 +163,289 @@ func (s *Store) GetEntry(id int64) (*TimeEntry, error) {
 		t, _ := time.Parse(time.RFC3339, endTime.String)
 		e.EndTime = &t
 	}
+	e.Billable = billable == 1
+	e.Archived = archived == 1
 	e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	return e, nil
 }
 
-func (s *Store) GetRunningEntry() (*TimeEntry, error) {
-	e := &TimeEntry{}
-	var startTime, createdAt string
-	var endTime sql.NullString
-	var taskID sql.NullInt64
+func (s *Store) GetEntry(id int64) (*TimeEntry, error) {
+	e, err := scanEntry(s.db.QueryRow(`SELECT `+entryColumns+` FROM time_entries WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("get entry %d: %w", id, err)
+	}
+	return e, nil
+}
 
-	err := s.db.QueryRow(
-		`SELECT id, project_id, task_id, start_time, end_time, duration, notes, created_at
-		 FROM time_entries WHERE end_time IS NULL ORDER BY id DESC LIMIT 1`,
-	).Scan(&e.ID, &e.ProjectID, &taskID, &startTime, &endTime, &e.Duration, &e.Notes, &createdAt)
+func (s *Store) GetRunningEntry() (*TimeEntry, error) {
+	e, err := scanEntry(s.db.QueryRow(
+		`SELECT ` + entryColumns + ` FROM time_entries WHERE end_time IS NULL ORDER BY id DESC LIMIT 1`,
+	))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get running entry: %w", err)
 	}
-	if taskID.Valid {
-		e.TaskID = &taskID.Int64
+	return e, nil
+}
+
+// GetLastEntryForProject returns the project's most recently started entry,
+// running or completed, or nil when it has none. Archived entries are
+// skipped.
+func (s *Store) GetLastEntryForProject(projectID int64) (*TimeEntry, error) {
+	e, err := scanEntry(s.db.QueryRow(`
+		SELECT `+entryColumns+` FROM time_entries
+		WHERE project_id = ? AND archived = 0
+		ORDER BY start_time DESC, id DESC
+		LIMIT 1`, projectID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("last entry for project %d: %w", projectID, err)
 	}
-	e.StartTime, _ = time.Parse(time.RFC3339, startTime)
-	e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	return e, nil
 }
 
+// ListRunningEntries returns every entry without an end time, oldest
+// first. Normally there is at most one; more means a timer was orphaned.
+func (s *Store) ListRunningEntries() ([]TimeEntry, error) {
+	entries, err := s.queryEntries(
+		`SELECT ` + entryColumns + ` FROM time_entries WHERE end_time IS NULL ORDER BY start_time, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list running entries: %w", err)
+	}
+	return entries, nil
+}
+
+// ListOrphanedEntries returns the running entries that started at least
+// threshold ago, oldest first: timers most likely left running by mistake.
+func (s *Store) ListOrphanedEntries(threshold time.Duration) ([]TimeEntry, error) {
+	cutoff := s.now().Add(-threshold).UTC().Format(time.RFC3339)
+	entries, err := s.queryEntries(`
+		SELECT `+entryColumns+` FROM time_entries
+		WHERE end_time IS NULL AND start_time <= ?
+		ORDER BY start_time, id`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("list orphaned entries: %w", err)
+	}
+	return entries, nil
+}
+
+// FindOverlappingEntries returns the project's entries whose time range
+// intersects [start, end), oldest first. Running entries count as ending
+// now, entries that only touch the window are not overlaps, and excludeID
+// (for the entry being checked) and archived entries are skipped.
+func (s *Store) FindOverlappingEntries(projectID int64, start, end time.Time, excludeID int64) ([]TimeEntry, error) {
+	now := s.now().UTC().Format(time.RFC3339)
+	entries, err := s.queryEntries(`
+		SELECT `+entryColumns+` FROM time_entries
+		WHERE project_id = ? AND id != ? AND archived = 0
+		  AND start_time < ? AND COALESCE(end_time, ?) > ?
+		ORDER BY start_time, id`,
+		projectID, excludeID, end.UTC().Format(time.RFC3339), now, start.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("find overlapping entries: %w", err)
+	}
+	return entries, nil
+}
+
+// queryEntries runs a query selecting entryColumns and scans every row.
+func (s *Store) queryEntries(query string, args ...any) ([]TimeEntry, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []TimeEntry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
 func (s *Store) UpdateEntryNotes(id int64, notes string) error {
 	_, err := s.db.Exec(`UPDATE time_entries SET notes = ? WHERE id = ?`, notes, id)
 	return err
 }
 
-func (s *Store) ListEntries(f EntryFilter) ([]TimeEntry, error) {
-	query := `SELECT id, project_id, task_id, start_time, end_time, duration, notes, created_at FROM time_entries WHERE 1=1`
+// UpdateEntryTags sets an entry's own comma-separated tags, e.g.
+// "interrupt, support". They apply whether or not the entry has a task.
+func (s *Store) UpdateEntryTags(id int64, tags string) error {
+	_, err := s.db.Exec(`UPDATE time_entries SET tags = ? WHERE id = ?`, strings.Join(ParseTags(tags), ","), id)
+	return err
+}
+
+// AppendEntryNote adds line to the end of an entry's notes, prefixed with
+// the current time, e.g. "[14:32] fixed the deadlock". Existing notes are
+// kept and each appended line ends with a newline.
+func (s *Store) AppendEntryNote(id int64, line string) error {
+	line = fmt.Sprintf("[%s] %s\n", s.now().In(s.loc).Format("15:04"), line)
+	res, err := s.db.Exec(`
+		UPDATE time_entries
+		SET notes = CASE WHEN notes = '' OR notes LIKE '%' || char(10) THEN notes ELSE notes || char(10) END || ?
+		WHERE id = ?`, line, id)
+	if err != nil {
+		return fmt.Errorf("append note: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("append note: entry %d not found", id)
+	}
+	return nil
+}
+
+// UpdateEntryProject reassigns an entry to a project and optional task. The
+// project must exist and the task, if any, must belong to it.
+func (s *Store) UpdateEntryProject(id, projectID int64, taskID *int64) error {
+	if _, err := s.GetProject(projectID); err != nil {
+		return err
+	}
+	if taskID != nil {
+		task, err := s.GetTask(*taskID)
+		if err != nil {
+			return err
+		}
+		if task.ProjectID != projectID {
+			return fmt.Errorf("update entry %d: task %q belongs to another project", id, task.Name)
+		}
+	}
+	_, err := s.db.Exec(
+		`UPDATE time_entries SET project_id = ?, task_id = ? WHERE id = ?`, projectID, taskID, id,
+	)
+	return err
+}
+
+// ArchiveEntry moves a completed entry to the trash. Running entries must be
+// stopped first.
+func (s *Store) ArchiveEntry(id int64) error {
+	res, err := s.db.Exec(`UPDATE time_entries SET archived = 1 WHERE id = ? AND end_time IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("archive entry: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("archive entry %d: not found or still running", id)
+	}
+	return nil
+}
+
+// UnarchiveEntry restores an entry from the trash.
+func (s *Store) UnarchiveEntry(id int64) error {
+	_, err := s.db.Exec(`UPDATE time_entries SET archived = 0 WHERE id = ?`, id)
+	return err
+}
+
+// DeleteEntry permanently removes an entry, running or not, along with its
+// timer state. Pomodoro sessions that referenced it are kept but detached.
+func (s *Store) DeleteEntry(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("delete entry: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE pomodoro_sessions SET time_entry_id = NULL WHERE time_entry_id = ?`, id); err != nil {
+		return fmt.Errorf("detach pomodoro sessions: %w", err)
+	}
+	res, err := tx.Exec(`DELETE FROM time_entries WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete entry: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("delete entry %d: not found", id)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("delete entry: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) SetEntryBillable(id int64, billable bool) error {
+	v := 0
+	if billable {
+		v = 1
+	}
+	_, err := s.db.Exec(`UPDATE time_entries SET billable = ? WHERE id = ?`, v, id)
+	return err
+}
+
+// filterSQL builds the WHERE conditions for f against time_entries columns
+// qualified by prefix (e.g. "e."). A Category condition refers to projects
+// as p, so the query must join it; see fromSQL.
+func (f EntryFilter) filterSQL(prefix string) (string, []any) {
+	var where string
 	var args []any
 
 	if f.ProjectID != nil {
-		query += ` AND project_id = ?`
+		where += ` AND ` + prefix + `project_id = ?`
 		args = append(args, *f.ProjectID)
 	}
 	if f.TaskID != nil {
-		query += ` AND task_id = ?`
+		where += ` AND ` + prefix + `task_id = ?`
 		args = append(args, *f.TaskID)
 	}
 	if f.From != nil {
-		query += ` AND start_time >= ?`
-		args = append(args, f.From.Format(time.RFC3339))
+		where += ` AND ` + prefix + `start_time >= ?`
+		args = append(args, f.From.UTC().Format(time.RFC3339))
 	}
 	if f.To != nil {
-		query += ` AND start_time < ?`
-		args = append(args, f.To.Format(time.RFC3339))
+		where += ` AND ` + prefix + `start_time < ?`
+		args = append(args, f.To.UTC().Format(time.RFC3339))
 	}
-	query += ` ORDER BY start_time DESC`
+	if f.Billable != nil {
+		where += ` AND ` + prefix + `billable = ?`
+		if *f.Billable {
+			args = append(args, 1)
+		} else {
+			args = append(args, 0)
+		}
+	}
+	if f.Category != nil {
+		where += ` AND p.category = ?`
+		args = append(args, *f.Category)
+	}
+	if f.EntryTag != nil {
+		where += ` AND ` + prefix + `tags LIKE '%' || ? || '%'`
+		args = append(args, *f.EntryTag)
+	}
+	switch {
+	case f.ArchivedOnly:
+		where += ` AND ` + prefix + `archived = 1`
+	case !f.IncludeArchived:
+		where += ` AND ` + prefix + `archived = 0`
+	}
+	return where, args
+}
+
+// fromSQL is the FROM clause for time_entries aliased as e, joining
+// projects as p only when f needs it.
+func (f EntryFilter) fromSQL() string {
+	if f.Category != nil {
+		return ` FROM time_entries e JOIN projects p ON p.id = e.project_id`
+	}
+	return ` FROM time_entries e`
+}
+
+// CountEntries returns how many entries match f, ignoring f.Limit.
+func (s *Store) CountEntries(f EntryFilter) (int64, error) {
+	where, args := f.filterSQL("e.")
+	var n int64
+	if err := s.db.QueryRow(`SELECT COUNT(*)`+f.fromSQL()+` WHERE 1=1`+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count entries: %w", err)
+	}
+	return n, nil
+}
+
+func (s *Store) ListEntries(f EntryFilter) ([]TimeEntry, error) {
+	where, args := f.filterSQL("e.")
+	query := `SELECT ` + qualifiedEntryColumns("e.") + f.fromSQL() + ` WHERE 1=1` + where
+	query += ` ORDER BY e.start_time DESC`
 	if f.Limit > 0 {
 		query += fmt.Sprintf(` LIMIT %d`, f.Limit)
 	}
@@ -129,65 +458,577 @@ func (s *Store) ListEntries(f EntryFilter) ([]TimeEntry, error) {
 
 	var entries []TimeEntry
 	for rows.Next() {
-		var e TimeEntry
-		var startTime, createdAt string
-		var endTime sql.NullString
-		var taskID sql.NullInt64
-		if err := rows.Scan(&e.ID, &e.ProjectID, &taskID, &startTime, &endTime, &e.Duration, &e.Notes, &createdAt); err != nil {
+		e, err := scanEntry(rows)
+		if err != nil {
 			return nil, err
 		}
-		if taskID.Valid {
-			e.TaskID = &taskID.Int64
-		}
-		e.StartTime, _ = time.Parse(time.RFC3339, startTime)
-		if endTime.Valid {
-			t, _ := time.Parse(time.RFC3339, endTime.String)
-			e.EndTime = &t
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+// ListEntriesDetailed is ListEntries with project and task names resolved in
+// the same query, for views that display entries.
+func (s *Store) ListEntriesDetailed(f EntryFilter) ([]DetailedEntry, error) {
+	where, args := f.filterSQL("e.")
+	query := `
+		SELECT ` + qualifiedEntryColumns("e.") + `,
+		       p.name, p.color, COALESCE(t.name, '')
+		FROM time_entries e
+		JOIN projects p ON p.id = e.project_id
+		LEFT JOIN tasks t ON t.id = e.task_id
+		WHERE 1=1` + where
+	query += ` ORDER BY e.start_time DESC`
+	if f.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, f.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list detailed entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DetailedEntry
+	for rows.Next() {
+		var e DetailedEntry
+		te, err := scanEntry(rows, &e.ProjectName, &e.ProjectColor, &e.TaskName)
+		if err != nil {
+			return nil, err
 		}
-		e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		e.TimeEntry = *te
 		entries = append(entries, e)
 	}
 	return entries, rows.Err()
 }
 
-func (s *Store) GetDailySummary(from, to time.Time) ([]DailySummary, error) {
+// GetEntriesForDay returns the completed entries starting on the calendar
+// day containing day, in the store's location, oldest first, with project
+// and task names resolved as in ListEntriesDetailed.
+func (s *Store) GetEntriesForDay(day time.Time) ([]DetailedEntry, error) {
+	from, to := s.dayBounds(day)
 	rows, err := s.db.Query(`
-		SELECT date(e.start_time) AS day, e.project_id, p.name, p.color,
-		       COALESCE(SUM(e.duration), 0), COUNT(*)
+		SELECT `+qualifiedEntryColumns("e.")+`,
+		       p.name, p.color, COALESCE(t.name, '')
 		FROM time_entries e
 		JOIN projects p ON p.id = e.project_id
-		WHERE e.end_time IS NOT NULL
+		LEFT JOIN tasks t ON t.id = e.task_id
+		WHERE e.end_time IS NOT NULL AND e.archived = 0
 		  AND e.start_time >= ? AND e.start_time < ?
-		GROUP BY day, e.project_id
-		ORDER BY day, p.name`,
-		from.Format(time.RFC3339), to.Format(time.RFC3339),
+		ORDER BY e.start_time`,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339),
 	)
+	if err != nil {
+		return nil, fmt.Errorf("entries for day: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DetailedEntry
+	for rows.Next() {
+		var e DetailedEntry
+		te, err := scanEntry(rows, &e.ProjectName, &e.ProjectColor, &e.TaskName)
+		if err != nil {
+			return nil, err
+		}
+		e.TimeEntry = *te
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetDailySummary returns per-day, per-project totals of completed entries
+// overlapping [from, to). Days are calendar days in the store's location.
+// An entry that crosses midnight is split across the days it spans, and
+// counts once towards EntryCount on each of them.
+func (s *Store) GetDailySummary(from, to time.Time) ([]DailySummary, error) {
+	return s.GetFilteredDailySummary(from, to, EntryFilter{})
+}
+
+// GetFilteredDailySummary is GetDailySummary restricted by f. Only
+// f.ProjectID and f.Category are applied; the range is always [from, to).
+func (s *Store) GetFilteredDailySummary(from, to time.Time, f EntryFilter) ([]DailySummary, error) {
+	query := `
+		SELECT e.start_time, e.end_time, e.project_id, p.name, p.color, e.duration
+		FROM time_entries e
+		JOIN projects p ON p.id = e.project_id
+		WHERE e.end_time IS NOT NULL AND e.archived = 0
+		  AND e.start_time < ? AND e.end_time > ?`
+	args := []any{to.UTC().Format(time.RFC3339), from.UTC().Format(time.RFC3339)}
+	if f.ProjectID != nil {
+		query += ` AND e.project_id = ?`
+		args = append(args, *f.ProjectID)
+	}
+	if f.Category != nil {
+		query += ` AND p.category = ?`
+		args = append(args, *f.Category)
+	}
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("daily summary: %w", err)
 	}
 	defer rows.Close()
 
+	type dayProject struct {
+		date      string
+		projectID int64
+	}
+	index := make(map[dayProject]int)
 	var summaries []DailySummary
 	for rows.Next() {
-		var ds DailySummary
-		if err := rows.Scan(&ds.Date, &ds.ProjectID, &ds.ProjectName, &ds.ProjectColor, &ds.TotalSeconds, &ds.EntryCount); err != nil {
+		var startStr, endStr, name, color string
+		var projectID, duration int64
+		if err := rows.Scan(&startStr, &endStr, &projectID, &name, &color, &duration); err != nil {
 			return nil, err
 		}
-		summaries = append(summaries, ds)
+		start, _ := time.Parse(time.RFC3339, startStr)
+		end, _ := time.Parse(time.RFC3339, endStr)
+		for _, ds := range s.splitByDay(start, end, duration) {
+			if !ds.day.Before(to) || !ds.day.AddDate(0, 0, 1).After(from) {
+				continue
+			}
+			k := dayProject{date: ds.day.Format("2006-01-02"), projectID: projectID}
+			i, ok := index[k]
+			if !ok {
+				i = len(summaries)
+				index[k] = i
+				summaries = append(summaries, DailySummary{
+					Date:         k.date,
+					ProjectID:    projectID,
+					ProjectName:  name,
+					ProjectColor: color,
+				})
+			}
+			summaries[i].TotalSeconds += ds.secs
+			summaries[i].EntryCount++
+		}
 	}
-	return summaries, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Date != summaries[j].Date {
+			return summaries[i].Date < summaries[j].Date
+		}
+		return summaries[i].ProjectName < summaries[j].ProjectName
+	})
+	return summaries, nil
+}
+
+// GetDailyTotals returns seconds tracked per day over [from, to), keyed by
+// YYYY-MM-DD, across all projects. Days with no time are absent.
+func (s *Store) GetDailyTotals(from, to time.Time) (map[string]int64, error) {
+	summaries, err := s.GetDailySummary(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return TotalsByDate(summaries), nil
+}
+
+// TotalsByDate folds per-project summaries into per-day totals, keyed by
+// YYYY-MM-DD.
+func TotalsByDate(summaries []DailySummary) map[string]int64 {
+	totals := make(map[string]int64)
+	for _, ds := range summaries {
+		totals[ds.Date] += ds.TotalSeconds
+	}
+	return totals
 }
 
+type daySlice struct {
+	day  time.Time // midnight in the store's location
+	secs int64
+}
+
+// splitByDay spreads an entry's tracked duration over the calendar days
+// between start and end, in proportion to the wall time on each day. The
+// last day absorbs rounding so the slices always sum to duration.
+func (s *Store) splitByDay(start, end time.Time, duration int64) []daySlice {
+	start, end = start.In(s.loc), end.In(s.loc)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, s.loc)
+	span := end.Sub(start)
+	if span <= 0 || !end.After(day.AddDate(0, 0, 1)) {
+		return []daySlice{{day: day, secs: duration}}
+	}
+
+	var out []daySlice
+	remaining := duration
+	for cur := start; cur.Before(end); {
+		next := day.AddDate(0, 0, 1)
+		if next.After(end) {
+			next = end
+		}
+		secs := int64(float64(duration) * float64(next.Sub(cur)) / float64(span))
+		if !next.Before(end) {
+			secs = remaining
+		}
+		remaining -= secs
+		out = append(out, daySlice{day: day, secs: secs})
+		cur, day = next, next
+	}
+	return out
+}
+
+// GetTodayTotal returns the seconds tracked today in the store's location.
+// Entries crossing midnight count only their part within today, as in
+// GetDailySummary.
 func (s *Store) GetTodayTotal() (int64, error) {
-	today := time.Now().UTC().Format("2006-01-02")
-	var total sql.NullInt64
-	err := s.db.QueryRow(`
-		SELECT COALESCE(SUM(duration), 0)
+	dayStart, dayEnd := s.Today()
+	summaries, err := s.GetDailySummary(dayStart, dayEnd)
+	if err != nil {
+		return 0, fmt.Errorf("today total: %w", err)
+	}
+	return sumSeconds(summaries), nil
+}
+
+// GetProjectTodayTotal is GetTodayTotal for a single project.
+func (s *Store) GetProjectTodayTotal(projectID int64) (int64, error) {
+	dayStart, dayEnd := s.Today()
+	summaries, err := s.GetFilteredDailySummary(dayStart, dayEnd, EntryFilter{ProjectID: &projectID})
+	if err != nil {
+		return 0, fmt.Errorf("project today total: %w", err)
+	}
+	return sumSeconds(summaries), nil
+}
+
+// GetProjectTodayTotals is GetProjectTodayTotal for every project at once,
+// keyed by project ID. Projects with nothing tracked today are absent.
+func (s *Store) GetProjectTodayTotals() (map[int64]int64, error) {
+	dayStart, dayEnd := s.Today()
+	summaries, err := s.GetDailySummary(dayStart, dayEnd)
+	if err != nil {
+		return nil, fmt.Errorf("project today totals: %w", err)
+	}
+	totals := make(map[int64]int64)
+	for _, ds := range summaries {
+		totals[ds.ProjectID] += ds.TotalSeconds
+	}
+	return totals, nil
+}
+
+// sumSeconds adds up the time in summaries.
+func sumSeconds(summaries []DailySummary) int64 {
+	var total int64
+	for _, ds := range summaries {
+		total += ds.TotalSeconds
+	}
+	return total
+}
+
+// GetBillableTotals returns the seconds of completed billable and
+// non-billable entries starting in [from, to).
+func (s *Store) GetBillableTotals(from, to time.Time) (billable, nonBillable int64, err error) {
+	err = s.db.QueryRow(`
+		SELECT COALESCE(SUM(CASE WHEN billable = 1 THEN duration END), 0),
+		       COALESCE(SUM(CASE WHEN billable = 0 THEN duration END), 0)
 		FROM time_entries
-		WHERE date(start_time) = ? AND end_time IS NOT NULL`, today,
-	).Scan(&total)
+		WHERE end_time IS NOT NULL AND archived = 0
+		  AND start_time >= ? AND start_time < ?`,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339),
+	).Scan(&billable, &nonBillable)
 	if err != nil {
-		return 0, err
+		return 0, 0, fmt.Errorf("billable totals: %w", err)
+	}
+	return billable, nonBillable, nil
+}
+
+// GetRevenueSummary returns per-project billable time and revenue for
+// completed entries starting in [from, to), ordered by project name.
+// Projects without a rate earn nothing but are still listed.
+func (s *Store) GetRevenueSummary(from, to time.Time) ([]ProjectRevenue, error) {
+	rows, err := s.db.Query(`
+		SELECT p.id, p.name, p.color, p.hourly_rate, SUM(e.duration)
+		FROM time_entries e
+		JOIN projects p ON p.id = e.project_id
+		WHERE e.end_time IS NOT NULL AND e.billable = 1 AND e.archived = 0
+		  AND e.start_time >= ? AND e.start_time < ?
+		GROUP BY p.id
+		ORDER BY p.name`,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("revenue summary: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ProjectRevenue
+	for rows.Next() {
+		var r ProjectRevenue
+		if err := rows.Scan(&r.ProjectID, &r.ProjectName, &r.ProjectColor, &r.HourlyRate, &r.BillableSeconds); err != nil {
+			return nil, err
+		}
+		r.Revenue = Revenue(r.HourlyRate, r.BillableSeconds)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// Revenue is the earnings for secs of billable time at an hourly rate.
+func Revenue(rate float64, secs int64) float64 {
+	return rate * float64(secs) / 3600
+}
+
+// GetTagSummary returns time per tag for completed entries starting in
+// [from, to), busiest first. An entry's tags are its own plus its task's;
+// one with several tags counts towards each of them, and entries without
+// any are totalled under UntaggedLabel.
+func (s *Store) GetTagSummary(from, to time.Time) ([]TagSummary, error) {
+	return s.GetFilteredTagSummary(from, to, EntryFilter{})
+}
+
+// GetFilteredTagSummary is GetTagSummary restricted by f. Only f.ProjectID
+// and f.Category are applied.
+func (s *Store) GetFilteredTagSummary(from, to time.Time, f EntryFilter) ([]TagSummary, error) {
+	query := `
+		SELECT e.duration, COALESCE(t.tags, ''), e.tags
+		FROM time_entries e
+		JOIN projects p ON p.id = e.project_id
+		LEFT JOIN tasks t ON t.id = e.task_id
+		WHERE e.end_time IS NOT NULL AND e.archived = 0
+		  AND e.start_time >= ? AND e.start_time < ?`
+	args := []any{from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)}
+	if f.ProjectID != nil {
+		query += ` AND e.project_id = ?`
+		args = append(args, *f.ProjectID)
+	}
+	if f.Category != nil {
+		query += ` AND p.category = ?`
+		args = append(args, *f.Category)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("tag summary: %w", err)
+	}
+	defer rows.Close()
+
+	index := make(map[string]int)
+	var out []TagSummary
+	for rows.Next() {
+		var duration int64
+		var taskTags, entryTags string
+		if err := rows.Scan(&duration, &taskTags, &entryTags); err != nil {
+			return nil, err
+		}
+		names := ParseTags(taskTags + "," + entryTags)
+		if len(names) == 0 {
+			names = []string{UntaggedLabel}
+		}
+		for _, name := range names {
+			i, ok := index[name]
+			if !ok {
+				i = len(out)
+				index[name] = i
+				out = append(out, TagSummary{Tag: name})
+			}
+			out[i].TotalSeconds += duration
+			out[i].EntryCount++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalSeconds != out[j].TotalSeconds {
+			return out[i].TotalSeconds > out[j].TotalSeconds
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out, nil
+}
+
+// GetWeekTotal returns the seconds tracked in completed entries since the
+// start of the current week, where weeks begin on weekStart. Entries
+// crossing into the week count only their part within it.
+func (s *Store) GetWeekTotal(weekStart time.Weekday) (int64, error) {
+	from := StartOfWeek(s.now().In(s.loc), weekStart)
+	summaries, err := s.GetDailySummary(from, from.AddDate(0, 0, 7))
+	if err != nil {
+		return 0, fmt.Errorf("week total: %w", err)
+	}
+	return sumSeconds(summaries), nil
+}
+
+// StartOfWeek returns midnight (in t's location) of the most recent
+// weekStart day on or before t.
+func StartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	diff := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -diff)
+}
+
+// GetWeeklySummary returns the time tracked in each week overlapping
+// [from, to), oldest first, with weeks beginning on weekStart. Every week
+// is listed, including those with nothing tracked. Days outside the range
+// are left out of the first and last weeks; an entry crossing midnight is
+// split across its days as in GetDailySummary.
+func (s *Store) GetWeeklySummary(from, to time.Time, weekStart time.Weekday) ([]WeekSummary, error) {
+	return s.GetFilteredWeeklySummary(from, to, weekStart, EntryFilter{})
+}
+
+// GetFilteredWeeklySummary is GetWeeklySummary restricted by f. Only
+// f.ProjectID and f.Category are applied.
+func (s *Store) GetFilteredWeeklySummary(from, to time.Time, weekStart time.Weekday, f EntryFilter) ([]WeekSummary, error) {
+	first := StartOfWeek(from.In(s.loc), weekStart)
+	var weeks []WeekSummary
+	for w := first; w.Before(to); w = w.AddDate(0, 0, 7) {
+		weeks = append(weeks, WeekSummary{WeekStart: w})
+	}
+
+	sub := EntryFilter{ProjectID: f.ProjectID, Category: f.Category}
+	where, args := sub.filterSQL("e.")
+	rows, err := s.db.Query(`
+		SELECT e.start_time, e.end_time, e.duration`+sub.fromSQL()+`
+		WHERE e.end_time IS NOT NULL AND e.start_time < ? AND e.end_time > ?`+where,
+		append([]any{to.UTC().Format(time.RFC3339), from.UTC().Format(time.RFC3339)}, args...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("weekly summary: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var startStr, endStr string
+		var duration int64
+		if err := rows.Scan(&startStr, &endStr, &duration); err != nil {
+			return nil, err
+		}
+		start, _ := time.Parse(time.RFC3339, startStr)
+		end, _ := time.Parse(time.RFC3339, endStr)
+		counted := -1
+		for _, ds := range s.splitByDay(start, end, duration) {
+			if !ds.day.Before(to) || !ds.day.AddDate(0, 0, 1).After(from) {
+				continue
+			}
+			// Round to whole days so a DST change doesn't shift the bucket.
+			i := int((ds.day.Sub(first).Hours()+12)/24) / 7
+			if i < 0 || i >= len(weeks) {
+				continue
+			}
+			weeks[i].TotalSeconds += ds.secs
+			if counted != i {
+				weeks[i].EntryCount++
+				counted = i
+			}
+		}
+	}
+	return weeks, rows.Err()
+}
+
+// SplitEntry splits a completed entry at the given instant: the original is
+// truncated to end at `at`, and a new entry with the same project, task and
+// notes covers the rest. at must fall strictly between start and end.
+func (s *Store) SplitEntry(id int64, at time.Time) (*TimeEntry, *TimeEntry, error) {
+	orig, err := s.GetEntry(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if orig.EndTime == nil {
+		return nil, nil, fmt.Errorf("split entry %d: entry is still running", id)
+	}
+	if !at.After(orig.StartTime) || !at.Before(*orig.EndTime) {
+		return nil, nil, fmt.Errorf("split entry %d: split time must be between start and end", id)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("split entry: %w", err)
+	}
+	defer tx.Rollback()
+
+	atStr := at.UTC().Format(time.RFC3339)
+	// Share the recorded duration by wall time, as splitByDay does, so
+	// paused time left out of it stays out and the total is unchanged.
+	span := orig.EndTime.Sub(orig.StartTime)
+	firstDur := int64(float64(orig.Duration) * float64(at.Sub(orig.StartTime)) / float64(span))
+	secondDur := orig.Duration - firstDur
+
+	if _, err := tx.Exec(
+		`UPDATE time_entries SET end_time = ?, duration = ? WHERE id = ?`,
+		atStr, firstDur, id,
+	); err != nil {
+		return nil, nil, fmt.Errorf("truncate entry: %w", err)
+	}
+	res, err := tx.Exec(
+		`INSERT INTO time_entries (project_id, task_id, start_time, end_time, duration, notes, tags, billable, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		orig.ProjectID, orig.TaskID, atStr, orig.EndTime.UTC().Format(time.RFC3339), secondDur, orig.Notes, orig.Tags,
+		orig.Billable, time.Now().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("insert split entry: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("split entry: %w", err)
+	}
+
+	newID, _ := res.LastInsertId()
+	first, err := s.GetEntry(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := s.GetEntry(newID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return first, second, nil
+}
+
+// DuplicateEntry copies a completed entry's project, task, notes, tags and
+// duration into a new entry starting at newStart. Running entries are
+// rejected since they have no final duration.
+func (s *Store) DuplicateEntry(id int64, newStart time.Time) (*TimeEntry, error) {
+	orig, err := s.GetEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	if orig.EndTime == nil {
+		return nil, fmt.Errorf("duplicate entry %d: entry is still running", id)
+	}
+
+	end := newStart.Add(time.Duration(orig.Duration) * time.Second)
+	res, err := s.db.Exec(
+		`INSERT INTO time_entries (project_id, task_id, start_time, end_time, duration, notes, tags, billable, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		orig.ProjectID, orig.TaskID, newStart.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339),
+		orig.Duration, orig.Notes, orig.Tags, orig.Billable, time.Now().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("duplicate entry: %w", err)
+	}
+	newID, _ := res.LastInsertId()
+	return s.GetEntry(newID)
+}
+
+// PruneOldEntries permanently deletes completed entries, archived or not,
+// that started before the cutoff, and returns how many were removed.
+// Pomodoro sessions that referenced them are kept, detached from the entry.
+func (s *Store) PruneOldEntries(before time.Time) (int64, error) {
+	cutoff := before.UTC().Format(time.RFC3339)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("prune entries: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE pomodoro_sessions SET time_entry_id = NULL
+		WHERE time_entry_id IN (
+			SELECT id FROM time_entries WHERE end_time IS NOT NULL AND start_time < ?
+		)`, cutoff,
+	); err != nil {
+		return 0, fmt.Errorf("detach pomodoro sessions: %w", err)
+	}
+	res, err := tx.Exec(
+		`DELETE FROM time_entries WHERE end_time IS NOT NULL AND start_time < ?`, cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("prune entries: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("prune entries: %w", err)
 	}
-	return total.Int64, nil
+	return n, nil
 }
@@ -1,25 +1,47 @@
 package store
 
-import "time"
+import (
+	"regexp"
+	"time"
+)
+
+// DefaultColor is used for projects without a valid hex color.
+const DefaultColor = "#6C63FF"
+
+var hexColorRe = regexp.MustCompile(`^#(?:[0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// NormalizeColor returns c if it is a #RGB or #RRGGBB hex color, otherwise
+// DefaultColor.
+func NormalizeColor(c string) string {
+	if hexColorRe.MatchString(c) {
+		return c
+	}
+	return DefaultColor
+}
 
 type Project struct {
-	ID        int64
-	Name      string
-	Color     string
-	Category  string
-	Archived  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int64
+	Name        string
+	Color       string
+	Category    string
+	Description string
+	HourlyRate  float64 // per billable hour; zero when unset
+	DailyGoal   int64   // seconds per day; zero when unset
+	Archived    bool
+	Pinned      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type Task struct {
-	ID        int64
-	ProjectID int64
-	Name      string
-	Tags      string
-	Archived  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int64
+	ProjectID   int64
+	Name        string
+	Tags        string
+	Archived    bool
+	CompletedAt *time.Time // nil while the task is open
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type TimeEntry struct {
@@ -30,9 +52,27 @@ type TimeEntry struct {
 	EndTime   *time.Time
 	Duration  int64 // seconds
 	Notes     string
+	Tags      string // comma-separated, independent of the task's tags
+	Billable  bool
+	Archived  bool // in the trash; excluded from lists and totals
 	CreatedAt time.Time
 }
 
+// DetailedEntry is a time entry with its project and task names resolved.
+type DetailedEntry struct {
+	TimeEntry
+	ProjectName  string
+	ProjectColor string
+	TaskName     string // empty when the entry has no task
+}
+
+// TimerState is the pause bookkeeping of a running entry.
+type TimerState struct {
+	EntryID  int64
+	PauseGap int64      // seconds paused before the current pause
+	PausedAt *time.Time // set while paused
+}
+
 type PomodoroSession struct {
 	ID             int64
 	TimeEntryID    *int64
@@ -56,15 +96,64 @@ type EntryFilter struct {
 	TaskID    *int64
 	From      *time.Time
 	To        *time.Time
+	Billable  *bool
+	Category  *string // project category; needs projects joined as p
+	EntryTag  *string // substring of the entry's own tags
 	Limit     int
+
+	IncludeArchived bool // also match archived entries
+	ArchivedOnly    bool // match only archived entries, for the trash
 }
 
 // DailySummary represents aggregated time per project per day.
 type DailySummary struct {
-	Date        string
-	ProjectID   int64
-	ProjectName string
+	Date         string
+	ProjectID    int64
+	ProjectName  string
 	ProjectColor string
 	TotalSeconds int64
-	EntryCount  int
+	EntryCount   int
+}
+
+// ProjectRevenue is a project's billable time and earnings over a range.
+type ProjectRevenue struct {
+	ProjectID       int64
+	ProjectName     string
+	ProjectColor    string
+	HourlyRate      float64
+	BillableSeconds int64
+	Revenue         float64
+}
+
+// WeekSummary is the time tracked in one week.
+type WeekSummary struct {
+	WeekStart    time.Time // midnight on the first day, in the store's location
+	TotalSeconds int64
+	EntryCount   int
+}
+
+// TagSummary is the time tracked under one task tag.
+type TagSummary struct {
+	Tag          string
+	TotalSeconds int64
+	EntryCount   int
+}
+
+// ProjectStats sums up a project's completed entries for its detail view.
+type ProjectStats struct {
+	TotalSeconds   int64
+	WeekSeconds    int64 // since the start of the current week
+	EntryCount     int
+	AverageSeconds int64      // mean entry length, 0 without entries
+	FirstTracked   *time.Time // start of the earliest entry, nil without entries
+	LastTracked    *time.Time // end of the latest entry, nil without entries
+}
+
+// DashboardStats is everything the dashboard shows, loaded in one call.
+type DashboardStats struct {
+	TodayTotal     int64
+	TodaySummary   []DailySummary
+	RecentEntries  []DetailedEntry
+	WeekTotal      int64
+	ActiveProjects int
 }
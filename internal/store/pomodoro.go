@@ -20,18 +20,18 @@ func (s *Store) StartPomodoro(timeEntryID *int64, workDuration, breakDuration, t
 	return s.GetPomodoro(id)
 }
 
-func (s *Store) GetPomodoro(id int64) (*PomodoroSession, error) {
+const pomodoroColumns = `id, time_entry_id, work_duration, break_duration, completed_count, target_count, status, started_at, completed_at`
+
+// scanPomodoro reads a row selected with pomodoroColumns.
+func scanPomodoro(row interface{ Scan(...any) error }) (*PomodoroSession, error) {
 	p := &PomodoroSession{}
 	var startedAt string
 	var completedAt sql.NullString
 	var entryID sql.NullInt64
 
-	err := s.db.QueryRow(
-		`SELECT id, time_entry_id, work_duration, break_duration, completed_count, target_count, status, started_at, completed_at
-		 FROM pomodoro_sessions WHERE id = ?`, id,
-	).Scan(&p.ID, &entryID, &p.WorkDuration, &p.BreakDuration, &p.CompletedCount, &p.TargetCount, &p.Status, &startedAt, &completedAt)
+	err := row.Scan(&p.ID, &entryID, &p.WorkDuration, &p.BreakDuration, &p.CompletedCount, &p.TargetCount, &p.Status, &startedAt, &completedAt)
 	if err != nil {
-		return nil, fmt.Errorf("get pomodoro %d: %w", id, err)
+		return nil, err
 	}
 	if entryID.Valid {
 		p.TimeEntryID = &entryID.Int64
@@ -44,6 +44,52 @@ func (s *Store) GetPomodoro(id int64) (*PomodoroSession, error) {
 	return p, nil
 }
 
+func (s *Store) GetPomodoro(id int64) (*PomodoroSession, error) {
+	p, err := scanPomodoro(s.db.QueryRow(`SELECT `+pomodoroColumns+` FROM pomodoro_sessions WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("get pomodoro %d: %w", id, err)
+	}
+	return p, nil
+}
+
+// ListPomodoroSessions returns the sessions started in [from, to), newest
+// first, whatever their status.
+func (s *Store) ListPomodoroSessions(from, to time.Time) ([]PomodoroSession, error) {
+	rows, err := s.db.Query(`
+		SELECT `+pomodoroColumns+` FROM pomodoro_sessions
+		WHERE started_at >= ? AND started_at < ?
+		ORDER BY started_at DESC, id DESC`,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list pomodoro sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []PomodoroSession
+	for rows.Next() {
+		p, err := scanPomodoro(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *p)
+	}
+	return sessions, rows.Err()
+}
+
+// DeletePomodoroSession permanently removes a session. The time entry it
+// was linked to, if any, is kept.
+func (s *Store) DeletePomodoroSession(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM pomodoro_sessions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete pomodoro %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("delete pomodoro %d: not found", id)
+	}
+	return nil
+}
+
 func (s *Store) CompletePomodoro(id int64) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 	_, err := s.db.Exec(
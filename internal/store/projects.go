@@ -1,16 +1,31 @@
 package store
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
-func (s *Store) CreateProject(name, color, category string) (*Project, error) {
+// ErrProjectExists is returned when a project name is already taken.
+var ErrProjectExists = errors.New("project already exists")
+
+// isUniqueViolation reports whether err is a SQLite UNIQUE constraint failure.
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+func (s *Store) CreateProject(name, color, category, description string) (*Project, error) {
+	color = NormalizeColor(color)
 	now := time.Now().UTC().Format(time.RFC3339)
 	res, err := s.db.Exec(
-		`INSERT INTO projects (name, color, category, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
-		name, color, category, now, now,
+		`INSERT INTO projects (name, color, category, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		name, color, category, description, now, now,
 	)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: %q", ErrProjectExists, name)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("insert project: %w", err)
 	}
@@ -18,28 +33,86 @@ func (s *Store) CreateProject(name, color, category string) (*Project, error) {
 	return s.GetProject(id)
 }
 
-func (s *Store) GetProject(id int64) (*Project, error) {
+const projectColumns = `id, name, color, category, description, hourly_rate, daily_goal, archived, pinned, created_at, updated_at`
+
+// scanProject reads a row selected with projectColumns.
+func scanProject(row interface{ Scan(...any) error }) (*Project, error) {
 	p := &Project{}
 	var createdAt, updatedAt string
-	var archived int
-	err := s.db.QueryRow(
-		`SELECT id, name, color, category, archived, created_at, updated_at FROM projects WHERE id = ?`, id,
-	).Scan(&p.ID, &p.Name, &p.Color, &p.Category, &archived, &createdAt, &updatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("get project %d: %w", id, err)
+	var archived, pinned int
+	if err := row.Scan(&p.ID, &p.Name, &p.Color, &p.Category, &p.Description, &p.HourlyRate, &p.DailyGoal, &archived, &pinned, &createdAt, &updatedAt); err != nil {
+		return nil, err
 	}
 	p.Archived = archived == 1
+	p.Pinned = pinned == 1
 	p.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	p.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 	return p, nil
 }
 
+func (s *Store) GetProject(id int64) (*Project, error) {
+	p, err := scanProject(s.db.QueryRow(`SELECT `+projectColumns+` FROM projects WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("get project %d: %w", id, err)
+	}
+	return p, nil
+}
+
+// GetOrCreateProject returns the project with the given name, creating it
+// with the default color and category if it does not exist yet.
+func (s *Store) GetOrCreateProject(name string) (*Project, error) {
+	var id int64
+	err := s.db.QueryRow(`SELECT id FROM projects WHERE name = ?`, name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return s.CreateProject(name, DefaultColor, "work", "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find project %q: %w", name, err)
+	}
+	return s.GetProject(id)
+}
+
+// ProjectSort selects the order of ListProjectsSorted. Its values are
+// stored as the project_sort setting.
+type ProjectSort string
+
+const (
+	SortByName   ProjectSort = "name"
+	SortByRecent ProjectSort = "recent" // latest entry start first
+	SortByTotal  ProjectSort = "total"  // most tracked time first
+)
+
+// ProjectSorts lists the sort orders in the order a toggle cycles them.
+var ProjectSorts = []ProjectSort{SortByName, SortByRecent, SortByTotal}
+
+// ListProjects returns projects with pinned ones first, then by name.
 func (s *Store) ListProjects(includeArchived bool) ([]Project, error) {
-	query := `SELECT id, name, color, category, archived, created_at, updated_at FROM projects`
+	return s.ListProjectsSorted(includeArchived, SortByName)
+}
+
+// ListProjectsSorted is ListProjects in the given order. Pinned projects
+// still come first; in the time-based orders, projects without entries
+// come last, and ties fall back to name. Unknown orders sort by name.
+func (s *Store) ListProjectsSorted(includeArchived bool, order ProjectSort) ([]Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects`
+	if order == SortByRecent || order == SortByTotal {
+		query += ` LEFT JOIN (
+			SELECT project_id, MAX(start_time) AS last_start, SUM(duration) AS total
+			FROM time_entries WHERE archived = 0
+			GROUP BY project_id
+		) e ON e.project_id = projects.id`
+	}
 	if !includeArchived {
 		query += ` WHERE archived = 0`
 	}
-	query += ` ORDER BY name`
+	switch order {
+	case SortByRecent:
+		query += ` ORDER BY pinned DESC, e.last_start IS NULL, e.last_start DESC, name`
+	case SortByTotal:
+		query += ` ORDER BY pinned DESC, e.total IS NULL, e.total DESC, name`
+	default:
+		query += ` ORDER BY pinned DESC, name`
+	}
 
 	rows, err := s.db.Query(query)
 	if err != nil {
@@ -49,29 +122,161 @@ func (s *Store) ListProjects(includeArchived bool) ([]Project, error) {
 
 	var projects []Project
 	for rows.Next() {
-		var p Project
-		var createdAt, updatedAt string
-		var archived int
-		if err := rows.Scan(&p.ID, &p.Name, &p.Color, &p.Category, &archived, &createdAt, &updatedAt); err != nil {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *p)
+	}
+	return projects, rows.Err()
+}
+
+// GetProjectColorMap returns projects keyed by ID, for looking up the name,
+// color or rate of an entry's project. Archived projects are included only
+// when includeArchived is set.
+func (s *Store) GetProjectColorMap(includeArchived bool) (map[int64]Project, error) {
+	projects, err := s.ListProjects(includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int64]Project, len(projects))
+	for _, p := range projects {
+		m[p.ID] = p
+	}
+	return m, nil
+}
+
+// RecentProjects returns up to limit active projects, most recently tracked
+// first; projects never tracked come last, by name. A limit of zero or less
+// returns them all.
+func (s *Store) RecentProjects(limit int) ([]Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects
+		LEFT JOIN (
+			SELECT project_id, MAX(start_time) AS last_start
+			FROM time_entries WHERE archived = 0
+			GROUP BY project_id
+		) e ON e.project_id = projects.id
+		WHERE archived = 0
+		ORDER BY e.last_start IS NULL, e.last_start DESC, name`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("recent projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
 			return nil, err
 		}
-		p.Archived = archived == 1
-		p.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		p.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
-		projects = append(projects, p)
+		projects = append(projects, *p)
 	}
 	return projects, rows.Err()
 }
 
-func (s *Store) UpdateProject(id int64, name, color, category string) error {
+func (s *Store) UpdateProject(id int64, name, color, category, description string) error {
+	color = NormalizeColor(color)
 	now := time.Now().UTC().Format(time.RFC3339)
 	_, err := s.db.Exec(
-		`UPDATE projects SET name = ?, color = ?, category = ?, updated_at = ? WHERE id = ?`,
-		name, color, category, now, id,
+		`UPDATE projects SET name = ?, color = ?, category = ?, description = ?, updated_at = ? WHERE id = ?`,
+		name, color, category, description, now, id,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %q", ErrProjectExists, name)
+	}
 	return err
 }
 
+// SetProjectRate sets the project's hourly rate; zero clears it.
+func (s *Store) SetProjectRate(id int64, rate float64) error {
+	if rate < 0 {
+		return fmt.Errorf("hourly rate must not be negative")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(
+		`UPDATE projects SET hourly_rate = ?, updated_at = ? WHERE id = ?`, rate, now, id,
+	)
+	return err
+}
+
+// SetProjectDailyGoal sets the seconds per day the project aims for; zero
+// clears it.
+func (s *Store) SetProjectDailyGoal(id, secs int64) error {
+	if secs < 0 {
+		return fmt.Errorf("daily goal must not be negative")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(
+		`UPDATE projects SET daily_goal = ?, updated_at = ? WHERE id = ?`, secs, now, id,
+	)
+	return err
+}
+
+// RenameCategory moves every project in category from to category to,
+// merging the two when to is already in use. It returns the number of
+// projects changed.
+func (s *Store) RenameCategory(from, to string) (int64, error) {
+	to = strings.TrimSpace(to)
+	if to == "" {
+		return 0, fmt.Errorf("category name must not be empty")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(
+		`UPDATE projects SET category = ?, updated_at = ? WHERE category = ?`, to, now, from,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("rename category %q: %w", from, err)
+	}
+	return res.RowsAffected()
+}
+
+// SetProjectCategory moves one project to category, leaving its other
+// fields alone.
+func (s *Store) SetProjectCategory(id int64, category string) error {
+	category = strings.TrimSpace(category)
+	if category == "" {
+		return fmt.Errorf("category name must not be empty")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := s.db.Exec(
+		`UPDATE projects SET category = ?, updated_at = ? WHERE id = ?`, category, now, id,
+	)
+	if err != nil {
+		return fmt.Errorf("set project %d category: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("set project %d category: not found", id)
+	}
+	return nil
+}
+
+// ListCategories returns the distinct categories of all projects, including
+// archived ones, in alphabetical order.
+func (s *Store) ListCategories() ([]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT category FROM projects ORDER BY category`)
+	if err != nil {
+		return nil, fmt.Errorf("list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var cats []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		cats = append(cats, c)
+	}
+	return cats, rows.Err()
+}
+
 func (s *Store) ArchiveProject(id int64) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 	_, err := s.db.Exec(
@@ -79,3 +284,15 @@ func (s *Store) ArchiveProject(id int64) error {
 	)
 	return err
 }
+
+func (s *Store) SetProjectPinned(id int64, pinned bool) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	v := 0
+	if pinned {
+		v = 1
+	}
+	_, err := s.db.Exec(
+		`UPDATE projects SET pinned = ?, updated_at = ? WHERE id = ?`, v, now, id,
+	)
+	return err
+}
@@ -1,6 +1,12 @@
 package store
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
 
 func (s *Store) GetSetting(key string) (string, error) {
 	var value string
@@ -11,6 +17,52 @@ func (s *Store) GetSetting(key string) (string, error) {
 	return value, nil
 }
 
+// GetSettingInt reads key as an integer, returning fallback when it is
+// missing or malformed.
+func (s *Store) GetSettingInt(key string, fallback int) int {
+	v, err := s.GetSetting(key)
+	if err != nil {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// GetSettingBool is GetSettingInt for booleans.
+func (s *Store) GetSettingBool(key string, fallback bool) bool {
+	v, err := s.GetSetting(key)
+	if err != nil {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
+
+// GetSettingDuration reads key as a number of seconds. Non-positive values
+// also return fallback, so a stored 0 can never mean "no time at all".
+func (s *Store) GetSettingDuration(key string, fallback time.Duration) time.Duration {
+	secs := s.GetSettingInt(key, 0)
+	if secs < 1 {
+		return fallback
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// WeekStart reads the week_start setting: Sunday when it says so, Monday
+// otherwise.
+func (s *Store) WeekStart() time.Weekday {
+	if v, _ := s.GetSetting("week_start"); v == "sunday" {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
 func (s *Store) SetSetting(key, value string) error {
 	_, err := s.db.Exec(
 		`INSERT INTO settings (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
@@ -19,6 +71,25 @@ func (s *Store) SetSetting(key, value string) error {
 	return err
 }
 
+// SetSettings upserts every key in one transaction, so either all of them
+// are saved or, on error, none are.
+func (s *Store) SetSettings(kv map[string]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("set settings: %w", err)
+	}
+	defer tx.Rollback()
+	for _, k := range slices.Sorted(maps.Keys(kv)) {
+		if _, err := tx.Exec(
+			`INSERT INTO settings (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+			k, kv[k],
+		); err != nil {
+			return fmt.Errorf("set setting %s: %w", k, err)
+		}
+	}
+	return tx.Commit()
+}
+
 func (s *Store) GetAllSettings() ([]Setting, error) {
 	rows, err := s.db.Query(`SELECT key, value FROM settings ORDER BY key`)
 	if err != nil {
@@ -36,3 +107,26 @@ func (s *Store) GetAllSettings() ([]Setting, error) {
 	}
 	return settings, rows.Err()
 }
+
+// ExportSettings returns every setting, keyed by name.
+func (s *Store) ExportSettings() (map[string]string, error) {
+	settings, err := s.GetAllSettings()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(settings))
+	for _, st := range settings {
+		out[st.Key] = st.Value
+	}
+	return out, nil
+}
+
+// ImportSettings applies exported settings with SetSettings. Keys this
+// version does not know are stored as-is, so settings from a newer trackr
+// survive the round trip.
+func (s *Store) ImportSettings(settings map[string]string) error {
+	if err := s.SetSettings(settings); err != nil {
+		return fmt.Errorf("import settings: %w", err)
+	}
+	return nil
+}
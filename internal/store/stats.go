@@ -0,0 +1,202 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// dashboardRecentLimit is the default number of recent entries in
+// DashboardStats.
+const dashboardRecentLimit = 5
+
+// GetDashboardStats gathers today's summary and total, up to recentLimit
+// recent entries (dashboardRecentLimit when not positive), the current
+// week's total and the number of active projects. Today's total is summed
+// from the summary rather than queried separately.
+func (s *Store) GetDashboardStats(weekStart time.Weekday, recentLimit int) (*DashboardStats, error) {
+	if recentLimit <= 0 {
+		recentLimit = dashboardRecentLimit
+	}
+	dayStart, dayEnd := s.Today()
+
+	summary, err := s.GetDailySummary(dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	stats := &DashboardStats{TodaySummary: summary}
+	for _, ds := range summary {
+		stats.TodayTotal += ds.TotalSeconds
+	}
+
+	if stats.RecentEntries, err = s.ListEntriesDetailed(EntryFilter{Limit: recentLimit}); err != nil {
+		return nil, err
+	}
+	if stats.WeekTotal, err = s.GetWeekTotal(weekStart); err != nil {
+		return nil, err
+	}
+	err = s.db.QueryRow(`SELECT COUNT(*) FROM projects WHERE archived = 0`).Scan(&stats.ActiveProjects)
+	if err != nil {
+		return nil, fmt.Errorf("count projects: %w", err)
+	}
+	return stats, nil
+}
+
+// GetProjectStats sums up a project's completed entries in one query: its
+// total and this week's time, entry count, average entry length, and when
+// it was first and last tracked. Weeks begin on the week_start setting. A
+// project without entries gets zeros and nil dates.
+func (s *Store) GetProjectStats(projectID int64) (*ProjectStats, error) {
+	from := StartOfWeek(s.now().In(s.loc), s.WeekStart())
+	to := from.AddDate(0, 0, 7)
+
+	stats := &ProjectStats{}
+	var first, last sql.NullString
+	err := s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(duration), 0),
+		       COALESCE(SUM(CASE WHEN start_time >= ? AND start_time < ? THEN duration END), 0),
+		       MIN(start_time), MAX(end_time)
+		FROM time_entries
+		WHERE project_id = ? AND end_time IS NOT NULL AND archived = 0`,
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339), projectID,
+	).Scan(&stats.EntryCount, &stats.TotalSeconds, &stats.WeekSeconds, &first, &last)
+	if err != nil {
+		return nil, fmt.Errorf("project stats %d: %w", projectID, err)
+	}
+	if stats.EntryCount > 0 {
+		stats.AverageSeconds = stats.TotalSeconds / int64(stats.EntryCount)
+	}
+	if first.Valid {
+		t, _ := time.Parse(time.RFC3339, first.String)
+		stats.FirstTracked = &t
+	}
+	if last.Valid {
+		t, _ := time.Parse(time.RFC3339, last.String)
+		stats.LastTracked = &t
+	}
+	return stats, nil
+}
+
+// GetLongestSession returns the completed entry with the longest duration,
+// or nil when nothing has been tracked.
+func (s *Store) GetLongestSession() (*TimeEntry, error) {
+	e, err := scanEntry(s.db.QueryRow(`
+		SELECT ` + entryColumns + ` FROM time_entries
+		WHERE end_time IS NOT NULL AND archived = 0
+		ORDER BY duration DESC, start_time
+		LIMIT 1`))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("longest session: %w", err)
+	}
+	return e, nil
+}
+
+// GetMostProductiveDay returns the calendar day, as YYYY-MM-DD in the
+// store's location, with the most tracked time. Entries crossing midnight
+// count towards each day they span. The date is empty when nothing has been
+// tracked.
+func (s *Store) GetMostProductiveDay() (date string, secs int64, err error) {
+	rows, err := s.db.Query(`
+		SELECT start_time, end_time, duration FROM time_entries
+		WHERE end_time IS NOT NULL AND archived = 0`)
+	if err != nil {
+		return "", 0, fmt.Errorf("most productive day: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var startStr, endStr string
+		var duration int64
+		if err := rows.Scan(&startStr, &endStr, &duration); err != nil {
+			return "", 0, err
+		}
+		start, _ := time.Parse(time.RFC3339, startStr)
+		end, _ := time.Parse(time.RFC3339, endStr)
+		for _, ds := range s.splitByDay(start, end, duration) {
+			totals[ds.day.Format("2006-01-02")] += ds.secs
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, err
+	}
+	for d, t := range totals {
+		if t > secs || t == secs && d < date {
+			date, secs = d, t
+		}
+	}
+	return date, secs, nil
+}
+
+// GetTopProject returns the project with the most tracked time overall and
+// that total, or nil when nothing has been tracked.
+func (s *Store) GetTopProject() (*Project, int64, error) {
+	var id, secs int64
+	err := s.db.QueryRow(`
+		SELECT project_id, SUM(duration) AS total FROM time_entries
+		WHERE end_time IS NOT NULL AND archived = 0
+		GROUP BY project_id
+		ORDER BY total DESC, project_id
+		LIMIT 1`).Scan(&id, &secs)
+	if err == sql.ErrNoRows {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("top project: %w", err)
+	}
+	p, err := s.GetProject(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return p, secs, nil
+}
+
+// GetAverageDaily returns the average seconds tracked per day over
+// [from, to). With countZeroDays every calendar day in the range counts,
+// giving the average per calendar day; otherwise only days with tracked
+// time count, giving the average per working day. It is 0 when there are
+// no days to average over.
+func (s *Store) GetAverageDaily(from, to time.Time, countZeroDays bool) (float64, error) {
+	totals, err := s.GetDailyTotals(from, to)
+	if err != nil {
+		return 0, fmt.Errorf("average daily: %w", err)
+	}
+	var sum int64
+	days := 0
+	for _, secs := range totals {
+		if secs > 0 {
+			sum += secs
+			days++
+		}
+	}
+	if countZeroDays {
+		days = 0
+		for day, _ := s.dayBounds(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+			days++
+		}
+	}
+	if days == 0 {
+		return 0, nil
+	}
+	return float64(sum) / float64(days), nil
+}
+
+// CountGoalMetDays counts the local calendar days in [from, to) whose
+// tracked time reaches goalSeconds, and how many days the range spans. A
+// goal of zero or less is never met.
+func (s *Store) CountGoalMetDays(from, to time.Time, goalSeconds int64) (met int, total int, err error) {
+	totals, err := s.GetDailyTotals(from, to)
+	if err != nil {
+		return 0, 0, fmt.Errorf("count goal days: %w", err)
+	}
+	for day, _ := s.dayBounds(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		total++
+		if goalSeconds > 0 && totals[day.Format("2006-01-02")] >= goalSeconds {
+			met++
+		}
+	}
+	return met, total, nil
+}
@@ -2,17 +2,23 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
-const currentVersion = 1
+// ErrSchemaTooNew is returned by New when the database was migrated by a
+// newer trackr than this one.
+var ErrSchemaTooNew = errors.New("database schema is newer than this build supports")
 
 type Store struct {
-	db *sql.DB
+	db  *sql.DB
+	loc *time.Location   // calendar days are bucketed in this zone
+	now func() time.Time // decides which day is today
 }
 
 // New opens (or creates) the SQLite database at dbPath and runs migrations.
@@ -41,9 +47,12 @@ func New(dbPath string) (*Store, error) {
 		}
 	}
 
-	s := &Store{db: db}
+	s := &Store{db: db, loc: time.Local, now: time.Now}
 	if err := s.migrate(); err != nil {
 		db.Close()
+		if errors.Is(err, ErrSchemaTooNew) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("migrate: %w", err)
 	}
 	return s, nil
@@ -54,32 +63,143 @@ func NewMemory() (*Store, error) {
 	return New(":memory:")
 }
 
+// SetLocation sets the time zone used to decide which calendar day an entry
+// belongs to. It defaults to the system's local zone.
+func (s *Store) SetLocation(loc *time.Location) {
+	s.loc = loc
+}
+
+// SetClock replaces the clock used to decide which day and week are
+// current, so tests can pin "now" near a day boundary.
+func (s *Store) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// Today returns the bounds [start, end) of the current calendar day in the
+// store's location.
+func (s *Store) Today() (time.Time, time.Time) {
+	return s.dayBounds(s.now())
+}
+
+// dayBounds returns the bounds [start, end) of the calendar day containing
+// t in the store's location.
+func (s *Store) dayBounds(t time.Time) (time.Time, time.Time) {
+	t = t.In(s.loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Close folds the WAL back into the database file and closes it.
 func (s *Store) Close() error {
+	s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
 	return s.db.Close()
 }
 
+// Compact checkpoints the WAL and rebuilds the database file, returning the
+// space freed by deleted rows to the file system. The store has a single
+// connection, so VACUUM never runs inside another statement's transaction.
+func (s *Store) Compact() error {
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("vacuum: %w", err)
+	}
+	return nil
+}
+
+// SnapshotTo writes a compact, standalone copy of the database to path
+// with VACUUM INTO, so the copy includes anything still in the WAL and
+// needs no -wal or -shm files alongside it. It will not replace an
+// existing file; remove it first to overwrite.
+func (s *Store) SnapshotTo(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("snapshot %s: %w", path, os.ErrExist)
+	}
+	if _, err := s.db.Exec("VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("snapshot %s: %w", path, err)
+	}
+	return nil
+}
+
+// Size returns the size of the database in bytes, not counting the WAL.
+func (s *Store) Size() (int64, error) {
+	var pages, pageSize int64
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pages); err != nil {
+		return 0, fmt.Errorf("page count: %w", err)
+	}
+	if err := s.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("page size: %w", err)
+	}
+	return pages * pageSize, nil
+}
+
+// migration is one schema step. It runs in its own transaction, which
+// also sets user_version to the step's version.
+type migration struct {
+	version int
+	up      func(*sql.Tx) error
+}
+
+// migrations lists every schema step in order. Add new steps at the end;
+// never change a step that has shipped.
+var migrations = []migration{
+	{1, migrateV1},
+	{2, migrateV2},
+	{3, migrateV3},
+	{4, migrateV4},
+	{5, migrateV5},
+	{6, migrateV6},
+	{7, migrateV7},
+	{8, migrateV8},
+	{9, migrateV9},
+	{10, migrateV10},
+}
+
+// currentVersion is the schema version after every migration has run.
+var currentVersion = migrations[len(migrations)-1].version
+
 func (s *Store) migrate() error {
+	return s.applyMigrations(migrations)
+}
+
+// applyMigrations runs the steps newer than the database's user_version.
+// A failed step is rolled back and leaves the version at the last step
+// that succeeded. A database newer than the last step is refused with
+// ErrSchemaTooNew rather than used with a schema this build doesn't know.
+func (s *Store) applyMigrations(steps []migration) error {
 	var version int
-	err := s.db.QueryRow("PRAGMA user_version").Scan(&version)
-	if err != nil {
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
 		return fmt.Errorf("read user_version: %w", err)
 	}
-
-	if version >= currentVersion {
-		return nil
+	if latest := steps[len(steps)-1].version; version > latest {
+		return fmt.Errorf("%w (database v%d, build v%d); please upgrade trackr", ErrSchemaTooNew, version, latest)
 	}
 
-	if version < 1 {
-		if err := s.migrateV1(); err != nil {
+	for _, m := range steps {
+		if m.version <= version {
+			continue
+		}
+		tx, err := s.db.Begin()
+		if err != nil {
 			return err
 		}
+		if err := m.up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate to v%d: %w", m.version, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.version)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("set user_version %d: %w", m.version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migrate to v%d: %w", m.version, err)
+		}
 	}
-
-	_, err = s.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", currentVersion))
-	return err
+	return nil
 }
 
-func (s *Store) migrateV1() error {
+func migrateV1(tx *sql.Tx) error {
 	const ddl = `
 	CREATE TABLE IF NOT EXISTS projects (
 		id          INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -143,10 +263,84 @@ func (s *Store) migrateV1() error {
 		('daily_goal',          '28800'),
 		('week_start',          'monday');
 	`
-	_, err := s.db.Exec(ddl)
+	_, err := tx.Exec(ddl)
+	return err
+}
+
+// migrateV2 adds pinned projects.
+func migrateV2(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE projects ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// migrateV3 adds a freeform project description.
+func migrateV3(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE projects ADD COLUMN description TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// migrateV4 adds the billable flag to entries; existing entries are billable.
+func migrateV4(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_entries ADD COLUMN billable INTEGER NOT NULL DEFAULT 1`)
+	return err
+}
+
+// migrateV5 adds an hourly rate to projects; zero means unset.
+func migrateV5(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE projects ADD COLUMN hourly_rate REAL NOT NULL DEFAULT 0`)
+	return err
+}
+
+// migrateV6 adds pause bookkeeping for the running entry.
+func migrateV6(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS timer_state (
+		entry_id  INTEGER PRIMARY KEY REFERENCES time_entries(id) ON DELETE CASCADE,
+		pause_gap INTEGER NOT NULL DEFAULT 0,
+		paused_at TEXT
+	)`)
 	return err
 }
 
+// migrateV7 adds soft deletion of entries.
+func migrateV7(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_entries ADD COLUMN archived INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// migrateV8 adds a per-project daily goal in seconds; zero means none.
+func migrateV8(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE projects ADD COLUMN daily_goal INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// migrateV9 adds task completion, separate from archiving.
+func migrateV9(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE tasks ADD COLUMN completed_at TEXT`)
+	return err
+}
+
+// migrateV10 adds entry-level tags, for time that has no task.
+func migrateV10(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_entries ADD COLUMN tags TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// DBPathEnv names the environment variable that overrides DefaultDBPath.
+const DBPathEnv = "TRACKR_DB"
+
+// ResolveDBPath picks the database file: flagPath when set, then the
+// TRACKR_DB environment variable, then DefaultDBPath.
+func ResolveDBPath(flagPath string) (string, error) {
+	if flagPath != "" {
+		return flagPath, nil
+	}
+	if p := os.Getenv(DBPathEnv); p != "" {
+		return p, nil
+	}
+	return DefaultDBPath()
+}
+
 // DefaultDBPath returns ~/.config/trackr/trackr.db
 func DefaultDBPath() (string, error) {
 	cfg, err := os.UserConfigDir()
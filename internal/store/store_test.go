@@ -2,6 +2,12 @@ package store
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -44,11 +50,11 @@ func TestNewMemory(t *testing.T) {
 	}
 	defer s.Close()
 
-	// Should have run migration v1
+	// Should have run all migrations
 	var version int
 	s.db.QueryRow("PRAGMA user_version").Scan(&version)
-	if version != 1 {
-		t.Fatalf("expected user_version 1, got %d", version)
+	if version != currentVersion {
+		t.Fatalf("expected user_version %d, got %d", currentVersion, version)
 	}
 }
 
@@ -69,6 +75,60 @@ func TestNewWithPath(t *testing.T) {
 	s2.Close()
 }
 
+func TestCompact(t *testing.T) {
+	s, err := New(t.TempDir() + "/trackr.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	for i := 0; i < 2000; i++ {
+		insertEntry(t, s, p.ID, nil, 3600, 60)
+	}
+	if _, err := s.db.Exec(`DELETE FROM time_entries`); err != nil {
+		t.Fatal(err)
+	}
+
+	before, _ := s.Size()
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after >= before {
+		t.Fatalf("compact should shrink the database, %d -> %d bytes", before, after)
+	}
+}
+
+func TestSnapshotTo(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	insertEntry(t, s, p.ID, nil, 3600, 60)
+
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := s.SnapshotTo(path); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Close()
+	projects, _ := snap.ListProjects(false)
+	if len(projects) != 1 || projects[0].Name != "Dev" {
+		t.Fatalf("snapshot should hold the projects, got %+v", projects)
+	}
+	if n, _ := snap.CountEntries(EntryFilter{}); n != 1 {
+		t.Fatalf("snapshot should hold 1 entry, got %d", n)
+	}
+
+	if err := s.SnapshotTo(path); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("snapshotting over an existing file should fail with ErrExist, got %v", err)
+	}
+}
+
 func TestDefaultDBPath(t *testing.T) {
 	path, err := DefaultDBPath()
 	if err != nil {
@@ -79,6 +139,22 @@ func TestDefaultDBPath(t *testing.T) {
 	}
 }
 
+func TestResolveDBPath(t *testing.T) {
+	def, _ := DefaultDBPath()
+
+	t.Setenv(DBPathEnv, "")
+	if got, _ := ResolveDBPath(""); got != def {
+		t.Fatalf("unset: got %q, want default %q", got, def)
+	}
+	t.Setenv(DBPathEnv, "/tmp/env.db")
+	if got, _ := ResolveDBPath(""); got != "/tmp/env.db" {
+		t.Fatalf("env: got %q", got)
+	}
+	if got, _ := ResolveDBPath("/tmp/flag.db"); got != "/tmp/flag.db" {
+		t.Fatalf("flag should win over env, got %q", got)
+	}
+}
+
 func TestPragmasConfigured(t *testing.T) {
 	s := newTestStore(t)
 
@@ -94,6 +170,25 @@ func TestPragmasConfigured(t *testing.T) {
 	}
 }
 
+func TestNewRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trackr.db")
+	s, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", currentVersion+1))
+	s.Close()
+
+	_, err = New(path)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+	want := fmt.Sprintf("(database v%d, build v%d)", currentVersion+1, currentVersion)
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("error should name both versions, got %q", err)
+	}
+}
+
 func TestMigrationIdempotent(t *testing.T) {
 	s := newTestStore(t)
 	// Running migrate again should be a no-op
@@ -102,13 +197,84 @@ func TestMigrationIdempotent(t *testing.T) {
 	}
 }
 
+func TestApplyMigrations(t *testing.T) {
+	// open returns a store with no schema, at the given user_version.
+	open := func(version int) *Store {
+		t.Helper()
+		db, err := sql.Open("sqlite", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		if version > 0 {
+			db.Exec(`CREATE TABLE notes (body TEXT)`)
+			db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version))
+		}
+		return &Store{db: db}
+	}
+	var ran []int
+	steps := []migration{
+		{1, func(tx *sql.Tx) error {
+			ran = append(ran, 1)
+			_, err := tx.Exec(`CREATE TABLE notes (body TEXT)`)
+			return err
+		}},
+		{2, func(tx *sql.Tx) error {
+			ran = append(ran, 2)
+			_, err := tx.Exec(`ALTER TABLE notes ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0`)
+			return err
+		}},
+	}
+	version := func(s *Store) int {
+		var v int
+		s.db.QueryRow("PRAGMA user_version").Scan(&v)
+		return v
+	}
+
+	fresh := open(0)
+	if err := fresh.applyMigrations(steps); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ran, []int{1, 2}) || version(fresh) != 2 {
+		t.Fatalf("fresh db: ran %v, version %d", ran, version(fresh))
+	}
+
+	ran = nil
+	v1 := open(1)
+	if err := v1.applyMigrations(steps); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ran, []int{2}) || version(v1) != 2 {
+		t.Fatalf("v1 db: ran %v, version %d", ran, version(v1))
+	}
+	if _, err := v1.db.Exec(`INSERT INTO notes (body, pinned) VALUES ('x', 1)`); err != nil {
+		t.Fatalf("step 2 should have added the column: %v", err)
+	}
+
+	// A failing step is rolled back and keeps the last good version.
+	broken := append(steps, migration{3, func(tx *sql.Tx) error {
+		tx.Exec(`CREATE TABLE half (x INTEGER)`)
+		return errors.New("boom")
+	}})
+	if err := v1.applyMigrations(broken); err == nil {
+		t.Fatal("expected the failing step's error")
+	}
+	if version(v1) != 2 {
+		t.Fatalf("version should stay at 2, got %d", version(v1))
+	}
+	if _, err := v1.db.Exec(`INSERT INTO half VALUES (1)`); err == nil {
+		t.Fatal("the failing step's changes should be rolled back")
+	}
+}
+
 // ============================================================
 // Projects
 // ============================================================
 
 func TestCreateAndGetProject(t *testing.T) {
 	s := newTestStore(t)
-	p, err := s.CreateProject("Work", "#FF0000", "work")
+	p, err := s.CreateProject("Work", "#FF0000", "work", "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -128,11 +294,11 @@ func TestCreateAndGetProject(t *testing.T) {
 
 func TestCreateProjectDuplicateName(t *testing.T) {
 	s := newTestStore(t)
-	_, err := s.CreateProject("Dup", "#111", "work")
+	_, err := s.CreateProject("Dup", "#111", "work", "")
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = s.CreateProject("Dup", "#222", "personal")
+	_, err = s.CreateProject("Dup", "#222", "personal", "")
 	if err == nil {
 		t.Fatal("expected error for duplicate project name")
 	}
@@ -148,8 +314,8 @@ func TestGetProjectNotFound(t *testing.T) {
 
 func TestListProjects(t *testing.T) {
 	s := newTestStore(t)
-	s.CreateProject("B", "#222", "personal")
-	s.CreateProject("A", "#111", "work")
+	s.CreateProject("B", "#222", "personal", "")
+	s.CreateProject("A", "#111", "work", "")
 
 	projects, err := s.ListProjects(false)
 	if err != nil {
@@ -164,6 +330,103 @@ func TestListProjects(t *testing.T) {
 	}
 }
 
+func TestListProjectsSorted(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#111", "work", "")
+	b, _ := s.CreateProject("Beta", "#222", "work", "")
+	c, _ := s.CreateProject("Gamma", "#333", "work", "")
+	s.CreateProject("Delta", "#444", "work", "") // no entries
+	insertEntry(t, s, a.ID, nil, 600, 300)       // most recent, least time
+	insertEntry(t, s, b.ID, nil, 7200, 3600)     // oldest, most time
+	insertEntry(t, s, c.ID, nil, 3600, 1800)
+
+	names := func(order ProjectSort) []string {
+		t.Helper()
+		projects, err := s.ListProjectsSorted(false, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, p := range projects {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	for _, tt := range []struct {
+		order ProjectSort
+		want  []string
+	}{
+		{SortByName, []string{"Alpha", "Beta", "Delta", "Gamma"}},
+		{SortByRecent, []string{"Alpha", "Gamma", "Beta", "Delta"}},
+		{SortByTotal, []string{"Beta", "Gamma", "Alpha", "Delta"}},
+	} {
+		if got := names(tt.order); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.order, got, tt.want)
+		}
+	}
+
+	// Pinned projects stay first in every order.
+	s.SetProjectPinned(c.ID, true)
+	if got := names(SortByTotal); got[0] != "Gamma" {
+		t.Fatalf("pinned project should sort first, got %v", got)
+	}
+}
+
+func TestGetProjectColorMap(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#111", "work", "")
+	b, _ := s.CreateProject("Beta", "#222", "work", "")
+	s.ArchiveProject(b.ID)
+
+	m, err := s.GetProjectColorMap(false)
+	if err != nil {
+		t.Fatalf("GetProjectColorMap: %v", err)
+	}
+	if len(m) != 1 || m[a.ID].Color != "#111" {
+		t.Fatalf("expected only Alpha, got %+v", m)
+	}
+
+	m, err = s.GetProjectColorMap(true)
+	if err != nil {
+		t.Fatalf("GetProjectColorMap: %v", err)
+	}
+	if len(m) != 2 || m[b.ID].Name != "Beta" || !m[b.ID].Archived {
+		t.Fatalf("archived projects should be included when requested, got %+v", m)
+	}
+}
+
+func TestRecentProjects(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#111", "work", "")
+	b, _ := s.CreateProject("Beta", "#222", "work", "")
+	s.CreateProject("Delta", "#444", "work", "") // never tracked
+	old, _ := s.CreateProject("Gamma", "#333", "work", "")
+	insertEntry(t, s, old.ID, nil, 86400, 600) // least recently used
+	insertEntry(t, s, a.ID, nil, 3600, 600)
+	insertEntry(t, s, b.ID, nil, 600, 300)
+	s.SetProjectPinned(old.ID, true) // pinning does not affect recency
+
+	projects, err := s.RecentProjects(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range projects {
+		got = append(got, p.Name)
+	}
+	if want := []string{"Beta", "Alpha", "Gamma", "Delta"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	projects, err = s.RecentProjects(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 || projects[1].Name != "Alpha" {
+		t.Fatalf("limit 2: got %+v", projects)
+	}
+}
+
 func TestListProjectsEmpty(t *testing.T) {
 	s := newTestStore(t)
 	projects, err := s.ListProjects(false)
@@ -177,7 +440,7 @@ func TestListProjectsEmpty(t *testing.T) {
 
 func TestArchiveProject(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Old", "#333", "work")
+	p, _ := s.CreateProject("Old", "#333", "work", "")
 	s.ArchiveProject(p.ID)
 
 	projects, _ := s.ListProjects(false)
@@ -193,12 +456,27 @@ func TestArchiveProject(t *testing.T) {
 	}
 }
 
+func TestCreateProjectDescription(t *testing.T) {
+	s := newTestStore(t)
+	p, err := s.CreateProject("Client", "#333", "freelance", "Retainer, invoice monthly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Description != "Retainer, invoice monthly" {
+		t.Fatalf("description not stored: %q", p.Description)
+	}
+	projects, _ := s.ListProjects(false)
+	if projects[0].Description != p.Description {
+		t.Fatalf("ListProjects should scan description: %q", projects[0].Description)
+	}
+}
+
 func TestUpdateProject(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Old", "#333", "work")
-	s.UpdateProject(p.ID, "New", "#444", "personal")
+	p, _ := s.CreateProject("Old", "#333", "work", "")
+	s.UpdateProject(p.ID, "New", "#444", "personal", "client: ACME")
 	updated, _ := s.GetProject(p.ID)
-	if updated.Name != "New" || updated.Color != "#444" || updated.Category != "personal" {
+	if updated.Name != "New" || updated.Color != "#444" || updated.Category != "personal" || updated.Description != "client: ACME" {
 		t.Fatalf("update failed: %+v", updated)
 	}
 	if !updated.UpdatedAt.After(p.CreatedAt) || updated.UpdatedAt.Equal(p.CreatedAt) {
@@ -206,13 +484,207 @@ func TestUpdateProject(t *testing.T) {
 	}
 }
 
+func TestUpdateProjectNameCollision(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("Existing", "#111", "work", "")
+	p, _ := s.CreateProject("Other", "#222", "work", "")
+
+	err := s.UpdateProject(p.ID, "Existing", "#222", "work", "")
+	if !errors.Is(err, ErrProjectExists) {
+		t.Fatalf("expected ErrProjectExists, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Existing") {
+		t.Fatalf("error should name the project: %v", err)
+	}
+	unchanged, _ := s.GetProject(p.ID)
+	if unchanged.Name != "Other" {
+		t.Fatalf("project should keep its name, got %q", unchanged.Name)
+	}
+
+	if _, err := s.CreateProject("Existing", "#333", "work", ""); !errors.Is(err, ErrProjectExists) {
+		t.Fatalf("expected ErrProjectExists on create, got %v", err)
+	}
+}
+
+func TestCreateProjectEmptyColor(t *testing.T) {
+	s := newTestStore(t)
+	p, err := s.CreateProject("NoColor", "", "work", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Color != DefaultColor {
+		t.Fatalf("expected default color, got %q", p.Color)
+	}
+
+	s.UpdateProject(p.ID, "NoColor", "not-a-color", "work", "")
+	updated, _ := s.GetProject(p.ID)
+	if updated.Color != DefaultColor {
+		t.Fatalf("expected invalid color to fall back, got %q", updated.Color)
+	}
+}
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"#FF0000", "#FF0000"},
+		{"#abc", "#abc"},
+		{"", DefaultColor},
+		{"FF0000", DefaultColor},
+		{"#GGGGGG", DefaultColor},
+		{"#12345", DefaultColor},
+	}
+	for _, tt := range tests {
+		if got := NormalizeColor(tt.in); got != tt.want {
+			t.Errorf("NormalizeColor(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenameCategory(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("A", "#111", "work", "")
+	b, _ := s.CreateProject("B", "#222", "Work", "")
+	s.CreateProject("C", "#333", "personal", "")
+	s.ArchiveProject(b.ID)
+
+	cats, err := s.ListCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(cats, ",") != "Work,personal,work" {
+		t.Fatalf("unexpected categories: %v", cats)
+	}
+
+	n, err := s.RenameCategory("Work", "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 project renamed, got %d", n)
+	}
+	cats, _ = s.ListCategories()
+	if strings.Join(cats, ",") != "personal,work" {
+		t.Fatalf("categories should be merged: %v", cats)
+	}
+	if _, err := s.RenameCategory("work", "  "); err == nil {
+		t.Fatal("renaming to an empty category should fail")
+	}
+}
+
+func TestSetProjectPinned(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("A", "#111", "work", "")
+	s.CreateProject("B", "#222", "work", "")
+	c, _ := s.CreateProject("C", "#333", "work", "")
+
+	if err := s.SetProjectPinned(c.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.GetProject(c.ID)
+	if !got.Pinned {
+		t.Fatal("project should be pinned")
+	}
+
+	projects, _ := s.ListProjects(false)
+	if projects[0].Name != "C" || projects[1].Name != "A" || projects[2].Name != "B" {
+		t.Fatalf("expected pinned first then by name: %s, %s, %s", projects[0].Name, projects[1].Name, projects[2].Name)
+	}
+
+	s.SetProjectPinned(c.ID, false)
+	projects, _ = s.ListProjects(false)
+	if projects[2].Name != "C" || projects[2].Pinned {
+		t.Fatal("unpinned project should sort by name again")
+	}
+}
+
+func TestSetProjectCategory(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#111", "work", "desc")
+	s.db.Exec(`UPDATE projects SET updated_at = ? WHERE id = ?`, "2020-01-01T00:00:00Z", p.ID)
+
+	if err := s.SetProjectCategory(p.ID, " personal "); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.GetProject(p.ID)
+	if got.Category != "personal" || got.Name != "Dev" || got.Color != "#111" || got.Description != "desc" {
+		t.Fatalf("only the category should change, got %+v", got)
+	}
+	if got.UpdatedAt.Year() == 2020 {
+		t.Fatal("updated_at should be bumped")
+	}
+
+	if err := s.SetProjectCategory(p.ID, ""); err == nil {
+		t.Fatal("an empty category should be rejected")
+	}
+	if err := s.SetProjectCategory(999, "work"); err == nil {
+		t.Fatal("a missing project should be reported")
+	}
+}
+
+func TestMigrateFromV1(t *testing.T) {
+	path := t.TempDir() + "/v1.db"
+	s, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a database created before v2.
+	s.db.Exec(`ALTER TABLE time_entries DROP COLUMN tags`)
+	s.db.Exec(`ALTER TABLE tasks DROP COLUMN completed_at`)
+	s.db.Exec(`ALTER TABLE projects DROP COLUMN daily_goal`)
+	s.db.Exec(`ALTER TABLE projects DROP COLUMN hourly_rate`)
+	s.db.Exec(`ALTER TABLE time_entries DROP COLUMN archived`)
+	s.db.Exec(`ALTER TABLE time_entries DROP COLUMN billable`)
+	s.db.Exec(`ALTER TABLE projects DROP COLUMN description`)
+	s.db.Exec(`ALTER TABLE projects DROP COLUMN pinned`)
+	s.db.Exec(`PRAGMA user_version = 1`)
+	s.db.Exec(`INSERT INTO projects (name) VALUES ('Old')`)
+	s.Close()
+
+	s, err = New(path)
+	if err != nil {
+		t.Fatalf("reopen v1 db: %v", err)
+	}
+	defer s.Close()
+	projects, err := s.ListProjects(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0].Pinned || projects[0].Description != "" {
+		t.Fatalf("existing project should migrate unpinned with no description: %+v", projects)
+	}
+}
+
+func TestGetOrCreateProject(t *testing.T) {
+	s := newTestStore(t)
+	p, err := s.GetOrCreateProject("CLI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID == 0 || p.Name != "CLI" {
+		t.Fatalf("unexpected project: %+v", p)
+	}
+
+	again, err := s.GetOrCreateProject("CLI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != p.ID {
+		t.Fatalf("expected existing project %d, got %d", p.ID, again.ID)
+	}
+	projects, _ := s.ListProjects(true)
+	if len(projects) != 1 {
+		t.Fatalf("expected 1 project, got %d", len(projects))
+	}
+}
+
 // ============================================================
 // Tasks
 // ============================================================
 
 func TestCreateAndGetTask(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	task, err := s.CreateTask(p.ID, "Bug fix", "backend,urgent")
 	if err != nil {
 		t.Fatal(err)
@@ -238,21 +710,21 @@ func TestCreateAndGetTask(t *testing.T) {
 
 func TestCreateTaskDuplicateNameSameProject(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	_, err := s.CreateTask(p.ID, "Task1", "")
 	if err != nil {
 		t.Fatal(err)
 	}
 	_, err = s.CreateTask(p.ID, "Task1", "other")
-	if err == nil {
-		t.Fatal("expected error for duplicate task name within same project")
+	if !errors.Is(err, ErrTaskExists) {
+		t.Fatalf("expected ErrTaskExists for duplicate task name within same project, got %v", err)
 	}
 }
 
 func TestCreateTaskSameNameDifferentProjects(t *testing.T) {
 	s := newTestStore(t)
-	p1, _ := s.CreateProject("A", "#111", "work")
-	p2, _ := s.CreateProject("B", "#222", "work")
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "work", "")
 	_, err1 := s.CreateTask(p1.ID, "Shared", "")
 	_, err2 := s.CreateTask(p2.ID, "Shared", "")
 	if err1 != nil || err2 != nil {
@@ -270,7 +742,7 @@ func TestCreateTaskInvalidProject(t *testing.T) {
 
 func TestListTasks(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	s.CreateTask(p.ID, "B task", "")
 	s.CreateTask(p.ID, "A task", "")
 
@@ -289,7 +761,7 @@ func TestListTasks(t *testing.T) {
 
 func TestListTasksEmpty(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	tasks, err := s.ListTasks(p.ID, false)
 	if err != nil {
 		t.Fatal(err)
@@ -301,8 +773,8 @@ func TestListTasksEmpty(t *testing.T) {
 
 func TestListTasksIsolation(t *testing.T) {
 	s := newTestStore(t)
-	p1, _ := s.CreateProject("A", "#111", "work")
-	p2, _ := s.CreateProject("B", "#222", "work")
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "work", "")
 	s.CreateTask(p1.ID, "Task A", "")
 	s.CreateTask(p2.ID, "Task B", "")
 
@@ -314,7 +786,7 @@ func TestListTasksIsolation(t *testing.T) {
 
 func TestArchiveTask(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	task, _ := s.CreateTask(p.ID, "Done task", "")
 	s.ArchiveTask(task.ID)
 
@@ -328,9 +800,73 @@ func TestArchiveTask(t *testing.T) {
 	}
 }
 
+func TestArchiveTasksForProject(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	other, _ := s.CreateProject("Ops", "#111", "work", "")
+	done, _ := s.CreateTask(p.ID, "Done", "")
+	s.CreateTask(p.ID, "Open", "")
+	gone, _ := s.CreateTask(p.ID, "Gone", "")
+	s.CreateTask(other.ID, "Elsewhere", "")
+	s.CompleteTask(done.ID)
+	s.CompleteTask(gone.ID)
+	s.ArchiveTask(gone.ID)
+	id := insertEntry(t, s, p.ID, &done.ID, 3600, 600)
+
+	n, err := s.ArchiveTasksForProject(p.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("should archive the one unarchived completed task, got %d", n)
+	}
+	tasks, _ := s.ListTasks(p.ID, false)
+	if len(tasks) != 1 || tasks[0].Name != "Open" {
+		t.Fatalf("only the open task should stay listed: %+v", tasks)
+	}
+	if e, _ := s.GetEntry(id); e == nil || e.TaskID == nil || *e.TaskID != done.ID {
+		t.Fatalf("entry should keep its archived task: %+v", e)
+	}
+	if task, _ := s.GetTask(done.ID); task == nil || !task.Archived {
+		t.Fatalf("archived task should still load: %+v", task)
+	}
+
+	n, _ = s.ArchiveTasksForProject(p.ID, false)
+	if n != 1 {
+		t.Fatalf("archiving all should catch the open task, got %d", n)
+	}
+	if tasks, _ := s.ListTasks(other.ID, false); len(tasks) != 1 {
+		t.Fatal("other projects' tasks should be untouched")
+	}
+}
+
+func TestCompleteTask(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	a, _ := s.CreateTask(p.ID, "Alpha", "")
+	s.CreateTask(p.ID, "Beta", "")
+	if err := s.CompleteTask(a.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, _ := s.ListTasks(p.ID, false)
+	if len(tasks) != 2 {
+		t.Fatalf("completed task should stay listed, got %d tasks", len(tasks))
+	}
+	if tasks[0].Name != "Beta" || tasks[1].Name != "Alpha" || tasks[1].CompletedAt == nil {
+		t.Fatalf("open tasks should sort before completed ones: %+v", tasks)
+	}
+
+	s.ReopenTask(a.ID)
+	got, _ := s.GetTask(a.ID)
+	if got.CompletedAt != nil {
+		t.Fatal("reopened task should have no completion time")
+	}
+}
+
 func TestUpdateTask(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	task, _ := s.CreateTask(p.ID, "Old", "tag1")
 	s.UpdateTask(task.ID, "New", "tag1,tag2")
 	updated, _ := s.GetTask(task.ID)
@@ -339,6 +875,40 @@ func TestUpdateTask(t *testing.T) {
 	}
 }
 
+func TestMoveTask(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("A", "#111", "work", "")
+	b, _ := s.CreateProject("B", "#222", "work", "")
+	task, _ := s.CreateTask(a.ID, "Review", "")
+	id := insertEntry(t, s, a.ID, &task.ID, 3600, 600)
+	other := insertEntry(t, s, a.ID, nil, 1800, 600)
+
+	if err := s.MoveTask(task.ID, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	moved, _ := s.GetTask(task.ID)
+	if moved.ProjectID != b.ID {
+		t.Fatalf("task should belong to B, got project %d", moved.ProjectID)
+	}
+	// Entries follow their task; entries without it stay put.
+	if e, _ := s.GetEntry(id); e.ProjectID != b.ID {
+		t.Fatalf("task entry should move to B, got project %d", e.ProjectID)
+	}
+	if e, _ := s.GetEntry(other); e.ProjectID != a.ID {
+		t.Fatal("unrelated entries should not move")
+	}
+
+	// Moving back into a project with a same-named task collides.
+	s.CreateTask(a.ID, "Review", "")
+	err := s.MoveTask(task.ID, a.ID)
+	if !errors.Is(err, ErrTaskExists) {
+		t.Fatalf("expected ErrTaskExists, got %v", err)
+	}
+	if e, _ := s.GetEntry(id); e.ProjectID != b.ID {
+		t.Fatal("a failed move should leave entries untouched")
+	}
+}
+
 func TestGetTaskNotFound(t *testing.T) {
 	s := newTestStore(t)
 	_, err := s.GetTask(999)
@@ -353,7 +923,7 @@ func TestGetTaskNotFound(t *testing.T) {
 
 func TestStartAndStopEntry(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	entry, err := s.StartEntry(p.ID, nil)
 	if err != nil {
@@ -401,7 +971,7 @@ func TestStartAndStopEntry(t *testing.T) {
 
 func TestStartEntryWithTask(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	task, _ := s.CreateTask(p.ID, "Feature", "")
 
 	tid := task.ID
@@ -415,283 +985,1709 @@ func TestStartEntryWithTask(t *testing.T) {
 	s.StopEntry(entry.ID)
 }
 
-func TestGetRunningEntryReturnsLatest(t *testing.T) {
+func TestStartEntryAt(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
-	e1, _ := s.StartEntry(p.ID, nil)
-	s.StopEntry(e1.ID)
+	start := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	e, err := s.StartEntryAt(p.ID, nil, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.StartTime.Equal(start) || e.EndTime != nil {
+		t.Fatalf("expected running entry from %v, got %+v", start, e)
+	}
 
-	e2, _ := s.StartEntry(p.ID, nil)
+	stopped, _ := s.StopEntry(e.ID)
+	if stopped.Duration < 600 {
+		t.Fatalf("duration should count from the backdated start, got %d", stopped.Duration)
+	}
 
-	running, _ := s.GetRunningEntry()
-	if running == nil || running.ID != e2.ID {
-		t.Fatal("should return the latest running entry")
+	if _, err := s.StartEntryAt(p.ID, nil, time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("expected error for a start time in the future")
 	}
-	s.StopEntry(e2.ID)
 }
 
-func TestGetRunningEntryNone(t *testing.T) {
+func TestStopEntryExcludesPauses(t *testing.T) {
 	s := newTestStore(t)
-	entry, err := s.GetRunningEntry()
-	if err != nil {
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+
+	if st, _ := s.GetTimerState(e.ID); st != nil {
+		t.Fatal("a fresh entry should have no timer state")
+	}
+	pausedAt := time.Now().Add(-10 * time.Minute)
+	if err := s.SaveTimerState(TimerState{EntryID: e.ID, PauseGap: 600, PausedAt: &pausedAt}); err != nil {
 		t.Fatal(err)
 	}
-	if entry != nil {
-		t.Fatal("expected nil when no entries exist")
+	st, _ := s.GetTimerState(e.ID)
+	if st == nil || st.PauseGap != 600 || st.PausedAt == nil {
+		t.Fatalf("timer state should round-trip, got %+v", st)
+	}
+
+	stopped, _ := s.StopEntry(e.ID)
+	if stopped.Duration < 2395 || stopped.Duration > 2405 {
+		t.Fatalf("duration should exclude 20m of pauses, got %d", stopped.Duration)
+	}
+	if st, _ := s.GetTimerState(e.ID); st != nil {
+		t.Fatal("stopping should clear the timer state")
+	}
+}
+
+func TestStopEntryAt(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+
+	if _, err := s.StopEntryAt(e.ID, e.StartTime.Add(-time.Minute)); err == nil {
+		t.Fatal("stopping before the start should fail")
+	}
+	if got, _ := s.GetEntry(e.ID); got.EndTime != nil {
+		t.Fatal("a rejected stop should leave the entry running")
+	}
+
+	end := time.Now().Add(-20 * time.Minute)
+	stopped, err := s.StopEntryAt(e.ID, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped.EndTime == nil || !stopped.EndTime.Equal(end.Truncate(time.Second)) {
+		t.Fatalf("end should be the given time, got %v", stopped.EndTime)
+	}
+	if stopped.Duration < 2395 || stopped.Duration > 2405 {
+		t.Fatalf("duration should run to the given end, got %d", stopped.Duration)
+	}
+}
+
+func TestArchiveEntry(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	insertEntry(t, s, p.ID, nil, 3600, 1200)
+	gone := insertEntry(t, s, p.ID, nil, 1800, 600)
+	running, _ := s.StartEntry(p.ID, nil)
+
+	if err := s.ArchiveEntry(running.ID); err == nil {
+		t.Fatal("archiving a running entry should fail")
+	}
+	if err := s.ArchiveEntry(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := s.ListEntries(EntryFilter{})
+	for _, e := range entries {
+		if e.ID == gone {
+			t.Fatal("archived entries should be hidden by default")
+		}
+	}
+	all, _ := s.ListEntries(EntryFilter{IncludeArchived: true})
+	if len(all) != 3 {
+		t.Fatalf("IncludeArchived should list all 3 entries, got %d", len(all))
+	}
+	trash, _ := s.ListEntriesDetailed(EntryFilter{ArchivedOnly: true})
+	if len(trash) != 1 || trash[0].ID != gone || !trash[0].Archived {
+		t.Fatalf("trash should hold only the archived entry, got %+v", trash)
+	}
+
+	from, to := time.Now().Add(-24*time.Hour), time.Now().Add(time.Hour)
+	summaries, _ := s.GetDailySummary(from, to)
+	if len(summaries) != 1 || summaries[0].TotalSeconds != 1200 {
+		t.Fatalf("summaries should exclude archived time: %+v", summaries)
+	}
+	if b, _, _ := s.GetBillableTotals(from, to); b != 1200 {
+		t.Fatalf("billable total should exclude archived time, got %d", b)
+	}
+
+	if err := s.UnarchiveEntry(gone); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.CountEntries(EntryFilter{}); n != 3 {
+		t.Fatalf("restored entry should be listed again, got %d", n)
+	}
+}
+
+func TestAppendEntryNote(t *testing.T) {
+	s := newTestStore(t)
+	s.SetLocation(time.UTC)
+	s.SetClock(func() time.Time { return time.Date(2024, 3, 1, 14, 32, 0, 0, time.UTC) })
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	entry, _ := s.StartEntry(p.ID, nil)
+	s.UpdateEntryNotes(entry.ID, "planning")
+
+	if err := s.AppendEntryNote(entry.ID, "fixed the deadlock"); err != nil {
+		t.Fatal(err)
+	}
+	s.SetClock(func() time.Time { return time.Date(2024, 3, 1, 15, 5, 0, 0, time.UTC) })
+	s.AppendEntryNote(entry.ID, "wrote tests")
+
+	got, _ := s.GetEntry(entry.ID)
+	want := "planning\n[14:32] fixed the deadlock\n[15:05] wrote tests\n"
+	if got.Notes != want {
+		t.Fatalf("notes = %q, want %q", got.Notes, want)
+	}
+	if err := s.AppendEntryNote(9999, "nowhere"); err == nil {
+		t.Fatal("expected an error for a missing entry")
+	}
+}
+
+func TestUpdateEntryProject(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("A", "#111", "work", "")
+	b, _ := s.CreateProject("B", "#222", "work", "")
+	taskA, _ := s.CreateTask(a.ID, "Design", "")
+	taskB, _ := s.CreateTask(b.ID, "Build", "")
+	id := insertEntry(t, s, a.ID, &taskA.ID, 3600, 600)
+
+	if err := s.UpdateEntryProject(id, b.ID, &taskA.ID); err == nil {
+		t.Fatal("a task from another project should be rejected")
+	}
+	if e, _ := s.GetEntry(id); e.ProjectID != a.ID || *e.TaskID != taskA.ID {
+		t.Fatal("a rejected update should leave the entry alone")
+	}
+	if err := s.UpdateEntryProject(id, 999, nil); err == nil {
+		t.Fatal("a missing project should be rejected")
+	}
+
+	if err := s.UpdateEntryProject(id, b.ID, &taskB.ID); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := s.GetEntry(id); e.ProjectID != b.ID || *e.TaskID != taskB.ID {
+		t.Fatalf("entry should move to B/Build, got %+v", e)
+	}
+	if err := s.UpdateEntryProject(id, a.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := s.GetEntry(id); e.ProjectID != a.ID || e.TaskID != nil {
+		t.Fatal("moving without a task should clear task_id")
+	}
+}
+
+func TestEntryBillable(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	id := insertEntry(t, s, p.ID, nil, 3600, 1200)
+	other := insertEntry(t, s, p.ID, nil, 1800, 600)
+
+	e, _ := s.GetEntry(id)
+	if !e.Billable {
+		t.Fatal("entries should default to billable")
+	}
+	if err := s.SetEntryBillable(other, false); err != nil {
+		t.Fatal(err)
+	}
+
+	no := false
+	entries, _ := s.ListEntries(EntryFilter{Billable: &no})
+	if len(entries) != 1 || entries[0].ID != other || entries[0].Billable {
+		t.Fatalf("expected only the non-billable entry, got %+v", entries)
+	}
+	if n, _ := s.CountEntries(EntryFilter{Billable: &no}); n != 1 {
+		t.Fatalf("CountEntries should honor Billable, got %d", n)
+	}
+
+	b, nb, err := s.GetBillableTotals(time.Now().Add(-24*time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b != 1200 || nb != 600 {
+		t.Fatalf("billable totals = %d/%d, want 1200/600", b, nb)
+	}
+
+	dup, _ := s.DuplicateEntry(other, time.Now().Add(-time.Hour))
+	if dup.Billable {
+		t.Fatal("duplicate should keep the billable flag")
+	}
+}
+
+func TestMigrateBillableDefault(t *testing.T) {
+	path := t.TempDir() + "/v3.db"
+	s, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.db.Exec(`ALTER TABLE time_entries DROP COLUMN tags`)
+	s.db.Exec(`ALTER TABLE tasks DROP COLUMN completed_at`)
+	s.db.Exec(`ALTER TABLE projects DROP COLUMN daily_goal`)
+	s.db.Exec(`ALTER TABLE projects DROP COLUMN hourly_rate`)
+	s.db.Exec(`ALTER TABLE time_entries DROP COLUMN archived`)
+	s.db.Exec(`ALTER TABLE time_entries DROP COLUMN billable`)
+	s.db.Exec(`PRAGMA user_version = 3`)
+	s.db.Exec(`INSERT INTO projects (name) VALUES ('Old')`)
+	s.db.Exec(`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (1, '2025-01-01T09:00:00Z', '2025-01-01T10:00:00Z', 3600)`)
+	s.Close()
+
+	s, err = New(path)
+	if err != nil {
+		t.Fatalf("reopen v3 db: %v", err)
+	}
+	defer s.Close()
+	entries, _ := s.ListEntries(EntryFilter{})
+	if len(entries) != 1 || !entries[0].Billable {
+		t.Fatalf("existing entries should migrate as billable: %+v", entries)
+	}
+}
+
+func TestGetRevenueSummary(t *testing.T) {
+	s := newTestStore(t)
+	paid, _ := s.CreateProject("Paid", "#111", "work", "")
+	free, _ := s.CreateProject("Free", "#222", "work", "")
+	if err := s.SetProjectRate(paid.ID, 90); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetProjectRate(paid.ID, -1); err == nil {
+		t.Fatal("negative rate should be rejected")
+	}
+	insertEntry(t, s, paid.ID, nil, 7200, 3600)
+	unbilled := insertEntry(t, s, paid.ID, nil, 3600, 1800)
+	s.SetEntryBillable(unbilled, false)
+	insertEntry(t, s, free.ID, nil, 1800, 1800)
+
+	got, _ := s.GetProject(paid.ID)
+	if got.HourlyRate != 90 {
+		t.Fatalf("HourlyRate = %v, want 90", got.HourlyRate)
+	}
+
+	rev, err := s.GetRevenueSummary(time.Now().Add(-24*time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rev) != 2 {
+		t.Fatalf("expected 2 projects, got %+v", rev)
+	}
+	if rev[0].ProjectName != "Free" || rev[0].Revenue != 0 || rev[0].BillableSeconds != 1800 {
+		t.Fatalf("project without a rate should earn nothing: %+v", rev[0])
+	}
+	if rev[1].BillableSeconds != 3600 || rev[1].Revenue != 90 {
+		t.Fatalf("only billable time should earn: %+v", rev[1])
+	}
+}
+
+func TestCountEntries(t *testing.T) {
+	s := newTestStore(t)
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "work", "")
+	insertEntry(t, s, p1.ID, nil, 3*24*3600, 600)
+	insertEntry(t, s, p1.ID, nil, 3600, 600)
+	insertEntry(t, s, p2.ID, nil, 1800, 600)
+
+	recent := time.Now().Add(-2 * time.Hour)
+	from, to := time.Now().Add(-4*24*time.Hour), time.Now().Add(-2*24*time.Hour)
+	cases := []struct {
+		name string
+		f    EntryFilter
+	}{
+		{"all", EntryFilter{}},
+		{"project", EntryFilter{ProjectID: &p1.ID}},
+		{"from", EntryFilter{From: &recent}},
+		{"project and range", EntryFilter{ProjectID: &p1.ID, From: &from, To: &to}},
+		{"limit ignored", EntryFilter{Limit: 1}},
+	}
+	for _, c := range cases {
+		n, err := s.CountEntries(c.f)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		f := c.f
+		f.Limit = 0
+		entries, _ := s.ListEntries(f)
+		if n != int64(len(entries)) {
+			t.Errorf("%s: count = %d, ListEntries returned %d", c.name, n, len(entries))
+		}
+	}
+	if n, _ := s.CountEntries(EntryFilter{ProjectID: &p2.ID}); n != 1 {
+		t.Fatalf("expected 1 entry for B, got %d", n)
+	}
+}
+
+func TestGetRunningEntryReturnsLatest(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	e1, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e1.ID)
+
+	e2, _ := s.StartEntry(p.ID, nil)
+
+	running, _ := s.GetRunningEntry()
+	if running == nil || running.ID != e2.ID {
+		t.Fatal("should return the latest running entry")
+	}
+	s.StopEntry(e2.ID)
+}
+
+func TestGetLastEntryForProject(t *testing.T) {
+	s := newTestStore(t)
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	empty, _ := s.CreateProject("Empty", "#111", "work", "")
+	task, _ := s.CreateTask(dev.ID, "Review", "")
+
+	if e, err := s.GetLastEntryForProject(empty.ID); err != nil || e != nil {
+		t.Fatalf("project without entries: got %v, %v", e, err)
+	}
+
+	insertEntry(t, s, dev.ID, nil, 7200, 600)
+	latest := insertEntry(t, s, dev.ID, &task.ID, 3600, 600)
+	e, err := s.GetLastEntryForProject(dev.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil || e.ID != latest || e.TaskID == nil || *e.TaskID != task.ID {
+		t.Fatalf("expected entry %d with its task, got %+v", latest, e)
+	}
+
+	running, _ := s.StartEntry(dev.ID, nil)
+	if e, _ := s.GetLastEntryForProject(dev.ID); e == nil || e.ID != running.ID {
+		t.Fatal("a running entry should count as the latest")
+	}
+}
+
+func TestGetRunningEntryNone(t *testing.T) {
+	s := newTestStore(t)
+	entry, err := s.GetRunningEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry != nil {
+		t.Fatal("expected nil when no entries exist")
+	}
+}
+
+func TestGetEntry(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	entry, _ := s.StartEntry(p.ID, nil)
+
+	fetched, err := s.GetEntry(entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.ID != entry.ID {
+		t.Fatal("ID mismatch")
+	}
+	s.StopEntry(entry.ID)
+}
+
+func TestGetEntryNotFound(t *testing.T) {
+	s := newTestStore(t)
+	_, err := s.GetEntry(999)
+	if err == nil {
+		t.Fatal("expected error for missing entry")
+	}
+}
+
+func TestUpdateEntryNotes(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	entry, _ := s.StartEntry(p.ID, nil)
+
+	s.UpdateEntryNotes(entry.ID, "some notes")
+	fetched, _ := s.GetEntry(entry.ID)
+	if fetched.Notes != "some notes" {
+		t.Fatalf("expected 'some notes', got %q", fetched.Notes)
+	}
+	s.StopEntry(entry.ID)
+}
+
+func TestSplitEntry(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	task, _ := s.CreateTask(p.ID, "Bug", "")
+	id := insertEntry(t, s, p.ID, &task.ID, 3*3600, 3*3600) // 3h entry ending now
+	s.UpdateEntryNotes(id, "session")
+
+	orig, _ := s.GetEntry(id)
+	at := orig.StartTime.Add(time.Hour)
+	first, second, err := s.SplitEntry(id, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != id || !first.EndTime.Equal(at) || first.Duration != 3600 {
+		t.Fatalf("unexpected first half: %+v", first)
+	}
+	if !second.StartTime.Equal(at) || !second.EndTime.Equal(*orig.EndTime) || second.Duration != 7200 {
+		t.Fatalf("unexpected second half: %+v", second)
+	}
+	if first.Duration+second.Duration != orig.Duration {
+		t.Fatalf("total duration not preserved: %d + %d != %d", first.Duration, second.Duration, orig.Duration)
+	}
+	if second.ProjectID != p.ID || second.TaskID == nil || *second.TaskID != task.ID || second.Notes != "session" {
+		t.Fatalf("second half should keep project/task/notes: %+v", second)
+	}
+}
+
+func TestSplitEntryKeepsPausedTimeOut(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	// 4h on the clock, 1h of it paused.
+	id := insertEntry(t, s, p.ID, nil, 4*3600, 3*3600)
+	s.db.Exec(`UPDATE time_entries SET end_time = ? WHERE id = ?`,
+		time.Now().UTC().Format(time.RFC3339), id)
+	orig, _ := s.GetEntry(id)
+
+	first, second, err := s.SplitEntry(id, orig.StartTime.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Duration+second.Duration != orig.Duration {
+		t.Fatalf("split should keep the paused hour out: %d + %d != %d", first.Duration, second.Duration, orig.Duration)
+	}
+	if first.Duration != 2700 || second.Duration != 8100 {
+		t.Fatalf("halves should share the duration by wall time, got %d and %d", first.Duration, second.Duration)
+	}
+}
+
+func TestSplitEntryInvalidTime(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	id := insertEntry(t, s, p.ID, nil, 3600, 3600)
+	orig, _ := s.GetEntry(id)
+
+	for _, at := range []time.Time{orig.StartTime, *orig.EndTime, orig.StartTime.Add(-time.Minute), orig.EndTime.Add(time.Minute)} {
+		if _, _, err := s.SplitEntry(id, at); err == nil {
+			t.Fatalf("expected error splitting at %v", at)
+		}
+	}
+	entries, _ := s.ListEntries(EntryFilter{})
+	if len(entries) != 1 {
+		t.Fatalf("failed splits should not create entries, got %d", len(entries))
+	}
+}
+
+func TestSplitEntryRunning(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	if _, _, err := s.SplitEntry(e.ID, time.Now()); err == nil {
+		t.Fatal("expected error splitting a running entry")
+	}
+}
+
+func TestDuplicateEntry(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	task, _ := s.CreateTask(p.ID, "Standup", "")
+	id := insertEntry(t, s, p.ID, &task.ID, 24*3600, 1800)
+	s.UpdateEntryNotes(id, "daily standup")
+
+	newStart := time.Now().UTC().Add(-30 * time.Minute).Truncate(time.Second)
+	dup, err := s.DuplicateEntry(id, newStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dup.ID == id {
+		t.Fatal("duplicate should be a new entry")
+	}
+	if !dup.StartTime.Equal(newStart) || !dup.EndTime.Equal(newStart.Add(30*time.Minute)) {
+		t.Fatalf("unexpected times: %v - %v", dup.StartTime, dup.EndTime)
+	}
+	if dup.Duration != 1800 || dup.ProjectID != p.ID || dup.TaskID == nil || *dup.TaskID != task.ID || dup.Notes != "daily standup" {
+		t.Fatalf("duplicate should copy project/task/notes/duration: %+v", dup)
+	}
+}
+
+func TestDuplicateEntryRunning(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	if _, err := s.DuplicateEntry(e.ID, time.Now()); err == nil {
+		t.Fatal("expected error duplicating a running entry")
+	}
+}
+
+func TestListRunningEntries(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	first, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+	second, _ := s.StartEntry(p.ID, nil)
+	insertEntry(t, s, p.ID, nil, 7200, 600) // completed
+
+	running, err := s.ListRunningEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(running) != 2 || running[0].ID != first.ID || running[1].ID != second.ID {
+		t.Fatalf("expected both running entries oldest first, got %+v", running)
+	}
+
+	s.StopAllRunning()
+	if running, _ := s.ListRunningEntries(); len(running) != 0 {
+		t.Fatalf("expected no running entries, got %d", len(running))
+	}
+}
+
+func TestListOrphanedEntries(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	old, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-18*time.Hour))
+	s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+	insertEntry(t, s, p.ID, nil, 86400, 600) // completed long ago
+
+	orphans, err := s.ListOrphanedEntries(12 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 1 || orphans[0].ID != old.ID {
+		t.Fatalf("only the 18h timer is orphaned, got %+v", orphans)
+	}
+
+	if err := s.DeleteEntry(old.ID); err != nil {
+		t.Fatal(err)
+	}
+	if orphans, _ := s.ListOrphanedEntries(12 * time.Hour); len(orphans) != 0 {
+		t.Fatalf("discarded timer still listed: %+v", orphans)
+	}
+	if err := s.DeleteEntry(old.ID); err == nil {
+		t.Fatal("deleting a missing entry should fail")
+	}
+}
+
+func TestFindOverlappingEntries(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	other, _ := s.CreateProject("Ops", "#111", "work", "")
+	id := insertEntry(t, s, p.ID, nil, 7200, 3600)
+	insertEntry(t, s, other.ID, nil, 7200, 3600) // other projects never conflict
+	e, _ := s.GetEntry(id)
+	start, end := e.StartTime, *e.EndTime
+
+	for _, tt := range []struct {
+		name        string
+		from, to    time.Time
+		excludeID   int64
+		wantOverlap bool
+	}{
+		{"fully contained", start.Add(10 * time.Minute), start.Add(20 * time.Minute), 0, true},
+		{"containing", start.Add(-time.Minute), end.Add(time.Minute), 0, true},
+		{"partial start", start.Add(-30 * time.Minute), start.Add(10 * time.Minute), 0, true},
+		{"partial end", end.Add(-10 * time.Minute), end.Add(30 * time.Minute), 0, true},
+		{"adjacent after", end, end.Add(30 * time.Minute), 0, false},
+		{"adjacent before", start.Add(-30 * time.Minute), start, 0, false},
+		{"excluded", start, end, id, false},
+	} {
+		got, err := s.FindOverlappingEntries(p.ID, tt.from, tt.to, tt.excludeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (len(got) > 0) != tt.wantOverlap {
+			t.Errorf("%s: got %+v", tt.name, got)
+		}
+	}
+
+	// A running entry overlaps anything up to now.
+	running, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-10*time.Minute))
+	got, err := s.FindOverlappingEntries(p.ID, time.Now().Add(-5*time.Minute), time.Now().Add(5*time.Minute), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != running.ID {
+		t.Fatalf("running entry should overlap, got %+v", got)
+	}
+}
+
+func TestStopAllRunning(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	var ids []int64
+	for _, ago := range []time.Duration{3 * time.Hour, 2 * time.Hour, time.Hour} {
+		e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-ago))
+		ids = append(ids, e.ID)
+	}
+	pausedAt := time.Now().Add(-30 * time.Minute)
+	s.SaveTimerState(TimerState{EntryID: ids[2], PausedAt: &pausedAt})
+	done := insertEntry(t, s, p.ID, nil, 600, 300)
+
+	n, err := s.StopAllRunning()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 stopped, got %d", n)
+	}
+	for _, id := range ids {
+		e, _ := s.GetEntry(id)
+		if e.EndTime == nil {
+			t.Fatalf("entry %d should have an end time", id)
+		}
+	}
+	if e, _ := s.GetEntry(ids[0]); e.Duration < 3*3600-5 || e.Duration > 3*3600+5 {
+		t.Fatalf("expected ~3h, got %d", e.Duration)
+	}
+	if e, _ := s.GetEntry(ids[2]); e.Duration < 1800-5 || e.Duration > 1800+5 {
+		t.Fatalf("paused time should be excluded, got %d", e.Duration)
+	}
+	if e, _ := s.GetEntry(done); e.Duration != 300 {
+		t.Fatalf("completed entries should be untouched, got %d", e.Duration)
+	}
+	if running, _ := s.GetRunningEntry(); running != nil {
+		t.Fatal("nothing should be running")
+	}
+}
+
+func TestPruneOldEntries(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	const day = 24 * 3600
+
+	old := insertEntry(t, s, p.ID, nil, 400*day, 3600)
+	oldArchived := insertEntry(t, s, p.ID, nil, 300*day, 3600)
+	s.ArchiveEntry(oldArchived)
+	recent := insertEntry(t, s, p.ID, nil, 10*day, 3600)
+	running, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-200*day*time.Second))
+
+	pomo, err := s.StartPomodoro(&old, 1500, 300, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.PruneOldEntries(time.Now().AddDate(0, 0, -100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 pruned entries, got %d", n)
+	}
+	for _, id := range []int64{old, oldArchived} {
+		if _, err := s.GetEntry(id); err == nil {
+			t.Fatalf("entry %d should be deleted", id)
+		}
+	}
+	for _, id := range []int64{recent, running.ID} {
+		if _, err := s.GetEntry(id); err != nil {
+			t.Fatalf("entry %d should be kept: %v", id, err)
+		}
+	}
+	got, err := s.GetPomodoro(pomo.ID)
+	if err != nil {
+		t.Fatalf("pomodoro session should survive: %v", err)
+	}
+	if got.TimeEntryID != nil {
+		t.Fatalf("pomodoro session should be detached, got entry %d", *got.TimeEntryID)
+	}
+}
+
+func TestListEntries(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	e1, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e1.ID)
+	e2, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e2.ID)
+
+	entries, err := s.ListEntries(EntryFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	// Should be ordered by start_time DESC (most recent first)
+	if entries[0].ID < entries[1].ID {
+		t.Fatal("entries should be newest first")
+	}
+}
+
+func TestListEntriesWithProjectFilter(t *testing.T) {
+	s := newTestStore(t)
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "personal", "")
+
+	e1, _ := s.StartEntry(p1.ID, nil)
+	s.StopEntry(e1.ID)
+	e2, _ := s.StartEntry(p2.ID, nil)
+	s.StopEntry(e2.ID)
+
+	pid := p1.ID
+	entries, _ := s.ListEntries(EntryFilter{ProjectID: &pid})
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry for project A, got %d", len(entries))
+	}
+	if entries[0].ProjectID != p1.ID {
+		t.Fatal("wrong project in filtered result")
+	}
+}
+
+func TestListEntriesWithTaskFilter(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	task, _ := s.CreateTask(p.ID, "Feature", "")
+
+	tid := task.ID
+	e1, _ := s.StartEntry(p.ID, &tid)
+	s.StopEntry(e1.ID)
+	e2, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e2.ID)
+
+	entries, _ := s.ListEntries(EntryFilter{TaskID: &tid})
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry for task, got %d", len(entries))
+	}
+}
+
+func TestListEntriesWithDateFilter(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	insertEntry(t, s, p.ID, nil, 7200, 3600) // 2h ago, 1h duration
+	insertEntry(t, s, p.ID, nil, 600, 300)   // 10min ago, 5min duration
+
+	now := time.Now().UTC()
+	from := now.Add(-1 * time.Hour)
+	to := now.Add(1 * time.Hour)
+	entries, _ := s.ListEntries(EntryFilter{From: &from, To: &to})
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry in last hour, got %d", len(entries))
+	}
+}
+
+func TestListEntriesWithLimit(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	for i := 0; i < 5; i++ {
+		insertEntry(t, s, p.ID, nil, i*100, 60)
+	}
+
+	entries, _ := s.ListEntries(EntryFilter{Limit: 3})
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries with limit, got %d", len(entries))
+	}
+}
+
+func TestListEntriesNoFilter(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	for i := 0; i < 5; i++ {
+		insertEntry(t, s, p.ID, nil, i*100, 60)
+	}
+
+	entries, _ := s.ListEntries(EntryFilter{})
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries without limit, got %d", len(entries))
+	}
+}
+
+func TestListEntriesDetailed(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#FF0000", "work", "")
+	task, _ := s.CreateTask(p.ID, "Bug fix", "")
+
+	insertEntry(t, s, p.ID, &task.ID, 600, 300)
+	insertEntry(t, s, p.ID, nil, 300, 60)
+
+	entries, err := s.ListEntriesDetailed(EntryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	// Newest first: the taskless entry
+	if entries[0].ProjectName != "Dev" || entries[0].ProjectColor != "#FF0000" || entries[0].TaskName != "" {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].TaskName != "Bug fix" || entries[1].Duration != 300 {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestListEntriesDetailedFilter(t *testing.T) {
+	s := newTestStore(t)
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "work", "")
+	insertEntry(t, s, p1.ID, nil, 600, 60)
+	insertEntry(t, s, p2.ID, nil, 300, 60)
+	insertEntry(t, s, p2.ID, nil, 100, 60)
+
+	entries, _ := s.ListEntriesDetailed(EntryFilter{ProjectID: &p2.ID, Limit: 1})
+	if len(entries) != 1 || entries[0].ProjectName != "B" {
+		t.Fatalf("expected 1 entry for B, got %+v", entries)
+	}
+}
+
+func TestGetDailySummary(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	now := time.Now().UTC()
+	start := now.Add(-1 * time.Hour)
+	s.db.Exec(
+		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+		p.ID, start.Format(time.RFC3339), now.Format(time.RFC3339), 3600,
+	)
+
+	from := now.Add(-24 * time.Hour)
+	to := now.Add(24 * time.Hour)
+	summaries, err := s.GetDailySummary(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	}
+	if summaries[0].TotalSeconds != 3600 {
+		t.Fatalf("expected 3600s, got %d", summaries[0].TotalSeconds)
+	}
+	if summaries[0].ProjectName != "Dev" {
+		t.Fatalf("expected project name Dev, got %s", summaries[0].ProjectName)
+	}
+	if summaries[0].EntryCount != 1 {
+		t.Fatalf("expected 1 entry, got %d", summaries[0].EntryCount)
+	}
+}
+
+func TestGetDailySummaryMultipleProjects(t *testing.T) {
+	s := newTestStore(t)
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "personal", "")
+
+	now := time.Now().UTC()
+	start := now.Add(-1 * time.Hour)
+	s.db.Exec(
+		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+		p1.ID, start.Format(time.RFC3339), now.Format(time.RFC3339), 3600,
+	)
+	s.db.Exec(
+		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+		p2.ID, start.Format(time.RFC3339), now.Format(time.RFC3339), 1800,
+	)
+
+	from := now.Add(-24 * time.Hour)
+	to := now.Add(24 * time.Hour)
+	summaries, _ := s.GetDailySummary(from, to)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries (one per project), got %d", len(summaries))
+	}
+}
+
+func TestGetFilteredDailySummary(t *testing.T) {
+	s := newTestStore(t)
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "personal", "")
+	insertEntry(t, s, p1.ID, nil, 3600, 3600)
+	insertEntry(t, s, p2.ID, nil, 3600, 1800)
+
+	now := time.Now()
+	summaries, err := s.GetFilteredDailySummary(now.Add(-24*time.Hour), now.Add(24*time.Hour), EntryFilter{ProjectID: &p2.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[0].ProjectID != p2.ID || summaries[0].TotalSeconds != 1800 {
+		t.Fatalf("expected only B's 1800s, got %+v", summaries)
+	}
+}
+
+func TestEntryFilterEntryTag(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	task, _ := s.CreateTask(p.ID, "Review", "interrupt")
+	a := insertEntry(t, s, p.ID, nil, 7200, 600)
+	b := insertEntry(t, s, p.ID, nil, 3600, 600)
+	insertEntry(t, s, p.ID, &task.ID, 1800, 600) // only the task is tagged
+
+	if err := s.UpdateEntryTags(a, " #interrupt, support ,"); err != nil {
+		t.Fatal(err)
+	}
+	s.UpdateEntryTags(b, "support")
+	if e, _ := s.GetEntry(a); e.Tags != "interrupt,support" {
+		t.Fatalf("tags should be normalized, got %q", e.Tags)
+	}
+
+	for _, tc := range []struct {
+		tag  string
+		want int
+	}{
+		{"interrupt", 1},
+		{"supp", 2},
+		{"meeting", 0},
+	} {
+		entries, err := s.ListEntries(EntryFilter{EntryTag: &tc.tag})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != tc.want {
+			t.Errorf("tag %q matched %d entries, want %d", tc.tag, len(entries), tc.want)
+		}
+	}
+	tag := "interrupt"
+	if d, _ := s.ListEntriesDetailed(EntryFilter{EntryTag: &tag}); len(d) != 1 || d[0].ID != a {
+		t.Fatalf("detailed list should filter on the entry's own tags, got %+v", d)
+	}
+
+	// Entry tags add to task tags in the tag summary.
+	tags, _ := s.GetTagSummary(time.Now().Add(-3*time.Hour), time.Now())
+	want := []TagSummary{
+		{Tag: "interrupt", TotalSeconds: 1200, EntryCount: 2},
+		{Tag: "support", TotalSeconds: 1200, EntryCount: 2},
+	}
+	if !slices.Equal(tags, want) {
+		t.Fatalf("got %+v, want %+v", tags, want)
+	}
+}
+
+func TestEntryFilterCategory(t *testing.T) {
+	s := newTestStore(t)
+	work, _ := s.CreateProject("Client", "#111", "freelance", "")
+	home, _ := s.CreateProject("Garden", "#222", "personal", "")
+	insertEntry(t, s, work.ID, nil, 7200, 3600)
+	insertEntry(t, s, work.ID, nil, 3600, 1800)
+	insertEntry(t, s, home.ID, nil, 3600, 600)
+
+	freelance := "freelance"
+	entries, err := s.ListEntries(EntryFilter{Category: &freelance})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 freelance entries, got %d", len(entries))
+	}
+	for _, e := range entries {
+		if e.ProjectID != work.ID {
+			t.Fatalf("entry %d belongs to another category", e.ID)
+		}
+	}
+
+	// Combined with a date bound, only the later freelance entry matches.
+	from := time.Now().Add(-90 * time.Minute)
+	if n, _ := s.CountEntries(EntryFilter{Category: &freelance, From: &from}); n != 1 {
+		t.Fatalf("expected 1 freelance entry after %v, got %d", from, n)
+	}
+	if d, _ := s.ListEntriesDetailed(EntryFilter{Category: &freelance, ProjectID: &home.ID}); len(d) != 0 {
+		t.Fatalf("category and project filters should combine, got %d entries", len(d))
+	}
+
+	now := time.Now()
+	summaries, _ := s.GetFilteredDailySummary(now.Add(-24*time.Hour), now.Add(24*time.Hour), EntryFilter{Category: &freelance})
+	if len(summaries) == 0 {
+		t.Fatal("expected freelance summaries")
+	}
+	for _, ds := range summaries {
+		if ds.ProjectID != work.ID {
+			t.Fatalf("summary should only include freelance projects, got %+v", summaries)
+		}
+	}
+}
+
+func TestGetDailySummaryExcludesRunning(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	// Running entry (no end_time)
+	s.StartEntry(p.ID, nil)
+
+	now := time.Now().UTC()
+	from := now.Add(-24 * time.Hour)
+	to := now.Add(24 * time.Hour)
+	summaries, _ := s.GetDailySummary(from, to)
+	if len(summaries) != 0 {
+		t.Fatal("running entries should be excluded from daily summary")
+	}
+}
+
+func TestGetDailySummaryEmpty(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UTC()
+	summaries, err := s.GetDailySummary(now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summaries != nil {
+		t.Fatal("expected nil for empty summary")
+	}
+}
+
+func TestGetDailySummaryLocalDay(t *testing.T) {
+	s := newTestStore(t)
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s.SetLocation(loc)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	// 23:00 local on Jan 15 is 04:00 UTC on Jan 16.
+	start := time.Date(2024, 1, 15, 23, 0, 0, 0, loc)
+	end := start.Add(30 * time.Minute)
+	s.db.Exec(
+		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+		p.ID, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339), 1800,
+	)
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
+	summaries, err := s.GetDailySummary(from, from.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	}
+	if summaries[0].Date != "2024-01-15" {
+		t.Fatalf("expected local day 2024-01-15, got %s", summaries[0].Date)
+	}
+
+	// A range covering only the local Jan 15 must include the entry.
+	summaries, _ = s.GetDailySummary(from, from.AddDate(0, 0, 1))
+	if len(summaries) != 1 || summaries[0].TotalSeconds != 1800 {
+		t.Fatalf("entry should count toward local Jan 15: %+v", summaries)
+	}
+}
+
+func TestGetDailySummaryAcrossMidnight(t *testing.T) {
+	s := newTestStore(t)
+	loc := time.FixedZone("UTC+2", 2*3600)
+	s.SetLocation(loc)
+	p, _ := s.CreateProject("Night", "#000", "work", "")
+
+	start := time.Date(2024, 1, 15, 23, 0, 0, 0, loc)
+	end := start.Add(3 * time.Hour)
+	s.db.Exec(
+		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+		p.ID, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339), 3*3600,
+	)
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
+	summaries, err := s.GetDailySummary(from, from.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected the entry split over 2 days, got %+v", summaries)
+	}
+	if summaries[0].Date != "2024-01-15" || summaries[0].TotalSeconds != 3600 {
+		t.Fatalf("Jan 15 should get 1h, got %+v", summaries[0])
+	}
+	if summaries[1].Date != "2024-01-16" || summaries[1].TotalSeconds != 2*3600 {
+		t.Fatalf("Jan 16 should get 2h, got %+v", summaries[1])
+	}
+
+	// A range covering only the second day still sees its share.
+	summaries, _ = s.GetDailySummary(from.AddDate(0, 0, 1), from.AddDate(0, 0, 2))
+	if len(summaries) != 1 || summaries[0].TotalSeconds != 2*3600 {
+		t.Fatalf("Jan 16 alone should get 2h, got %+v", summaries)
+	}
+}
+
+func TestSplitByDayKeepsPausedTotal(t *testing.T) {
+	s := newTestStore(t)
+	s.SetLocation(time.UTC)
+	start := time.Date(2024, 1, 15, 22, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 17, 1, 0, 0, 0, time.UTC)
+
+	// 27h of wall time with only 1000s tracked, e.g. a long pause.
+	slices := s.splitByDay(start, end, 1000)
+	if len(slices) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(slices))
+	}
+	var sum int64
+	for _, ds := range slices {
+		sum += ds.secs
+	}
+	if sum != 1000 {
+		t.Fatalf("slices should sum to the tracked duration, got %d", sum)
+	}
+}
+
+func TestGetDailySummaryOrdering(t *testing.T) {
+	s := newTestStore(t)
+	s.SetLocation(time.UTC)
+	pb, _ := s.CreateProject("B", "#111", "work", "")
+	pa, _ := s.CreateProject("A", "#222", "work", "")
+
+	day1 := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	for _, e := range []struct {
+		pid   int64
+		start time.Time
+	}{{pb.ID, day2}, {pa.ID, day2}, {pb.ID, day1}, {pb.ID, day1.Add(time.Hour)}} {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			e.pid, e.start.Format(time.RFC3339), e.start.Add(time.Minute).Format(time.RFC3339), 60,
+		)
+	}
+
+	summaries, _ := s.GetDailySummary(day1.AddDate(0, 0, -1), day2.AddDate(0, 0, 1))
+	if len(summaries) != 3 {
+		t.Fatalf("expected 3 summaries, got %d", len(summaries))
+	}
+	if summaries[0].Date != "2024-03-01" || summaries[0].EntryCount != 2 || summaries[0].TotalSeconds != 120 {
+		t.Fatalf("unexpected first summary: %+v", summaries[0])
+	}
+	if summaries[1].ProjectName != "A" || summaries[2].ProjectName != "B" {
+		t.Fatalf("expected day 2 sorted by project name: %+v", summaries[1:])
+	}
+}
+
+func TestGetTodayTotalLocalDay(t *testing.T) {
+	s := newTestStore(t)
+	// Just after local midnight in UTC+14 is still the previous day in UTC.
+	loc := time.FixedZone("UTC+14", 14*3600)
+	s.SetLocation(loc)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	now := time.Now().In(loc)
+	localMidnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	start := localMidnight.Add(time.Minute)
+	s.db.Exec(
+		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+		p.ID, start.UTC().Format(time.RFC3339), start.Add(time.Minute).UTC().Format(time.RFC3339), 60,
+	)
+
+	total, err := s.GetTodayTotal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 60 {
+		t.Fatalf("entry after local midnight should count today, got %d", total)
+	}
+}
+
+func TestGetEntriesForDay(t *testing.T) {
+	s := newTestStore(t)
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s.SetLocation(loc)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	insert := func(start time.Time, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			p.ID, start.UTC().Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).UTC().Format(time.RFC3339), secs,
+		)
+	}
+	insert(time.Date(2024, 1, 15, 23, 59, 59, 0, loc), 60) // previous day
+	insert(time.Date(2024, 1, 16, 18, 0, 0, 0, loc), 600)
+	insert(time.Date(2024, 1, 16, 0, 0, 0, 0, loc), 300) // first second of the day
+	insert(time.Date(2024, 1, 17, 0, 0, 0, 0, loc), 60)  // next day
+	s.StartEntryAt(p.ID, nil, time.Date(2024, 1, 16, 20, 0, 0, 0, loc))
+
+	// Any time on the day selects it, whatever its own zone.
+	entries, err := s.GetEntriesForDay(time.Date(2024, 1, 16, 23, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 completed entries on the day, got %d", len(entries))
+	}
+	if entries[0].Duration != 300 || entries[1].Duration != 600 {
+		t.Fatalf("entries should be ordered by start time: %+v", entries)
+	}
+	if entries[0].ProjectName != "Dev" {
+		t.Fatalf("project name should be resolved, got %q", entries[0].ProjectName)
+	}
+}
+
+func TestGetTagSummary(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	both, _ := s.CreateTask(p.ID, "Standup", "meetings, #team")
+	one, _ := s.CreateTask(p.ID, "Sync", "meetings,meetings")
+	none, _ := s.CreateTask(p.ID, "Code", "")
+
+	start := time.Now().Add(-time.Hour)
+	insert := func(taskID *int64, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, task_id, start_time, end_time, duration) VALUES (?, ?, ?, ?, ?)`,
+			p.ID, taskID, start.UTC().Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).UTC().Format(time.RFC3339), secs,
+		)
+	}
+	insert(&both.ID, 600)
+	insert(&one.ID, 300)
+	insert(&none.ID, 60)
+	insert(nil, 120)
+
+	tags, err := s.GetTagSummary(start.Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TagSummary{
+		{Tag: "meetings", TotalSeconds: 900, EntryCount: 2},
+		{Tag: "team", TotalSeconds: 600, EntryCount: 1},
+		{Tag: UntaggedLabel, TotalSeconds: 180, EntryCount: 2},
+	}
+	if !slices.Equal(tags, want) {
+		t.Fatalf("got %+v, want %+v", tags, want)
+	}
+}
+
+func TestGetWeeklySummary(t *testing.T) {
+	s := newTestStore(t)
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s.SetLocation(loc)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	insert := func(start time.Time, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			p.ID, start.UTC().Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).UTC().Format(time.RFC3339), secs,
+		)
+	}
+	insert(time.Date(2024, 1, 15, 10, 0, 0, 0, loc), 3600)  // Monday
+	insert(time.Date(2024, 1, 21, 23, 30, 0, 0, loc), 3600) // Sunday night into Monday
+	insert(time.Date(2024, 1, 31, 9, 0, 0, 0, loc), 3600)   // Wednesday
+	insert(time.Date(2024, 2, 5, 9, 0, 0, 0, loc), 3600)    // after the range
+
+	from, to := time.Date(2024, 1, 15, 0, 0, 0, 0, loc), time.Date(2024, 2, 5, 0, 0, 0, 0, loc)
+	type week struct {
+		start   string
+		secs    int64
+		entries int
+	}
+	check := func(weekStart time.Weekday, want []week) {
+		t.Helper()
+		weeks, err := s.GetWeeklySummary(from, to, weekStart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []week
+		for _, w := range weeks {
+			got = append(got, week{w.WeekStart.Format("2006-01-02"), w.TotalSeconds, w.EntryCount})
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("week start %s: got %v, want %v", weekStart, got, want)
+		}
+	}
+
+	check(time.Monday, []week{
+		{"2024-01-15", 5400, 2},
+		{"2024-01-22", 1800, 1},
+		{"2024-01-29", 3600, 1},
+	})
+	// Weeks starting Sunday keep the midnight entry in one week, and the
+	// range's last day opens a fourth, empty one.
+	check(time.Sunday, []week{
+		{"2024-01-14", 3600, 1},
+		{"2024-01-21", 3600, 1},
+		{"2024-01-28", 3600, 1},
+		{"2024-02-04", 0, 0},
+	})
+}
+
+func TestGetDailyTotals(t *testing.T) {
+	s := newTestStore(t)
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s.SetLocation(loc)
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "work", "")
+
+	insert := func(pid int64, start time.Time, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			pid, start.UTC().Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).UTC().Format(time.RFC3339), secs,
+		)
+	}
+	insert(p1.ID, time.Date(2024, 1, 15, 9, 0, 0, 0, loc), 3600)
+	insert(p2.ID, time.Date(2024, 1, 15, 14, 0, 0, 0, loc), 1800)
+	insert(p1.ID, time.Date(2024, 1, 17, 10, 0, 0, 0, loc), 600)
+
+	totals, err := s.GetDailyTotals(time.Date(2024, 1, 15, 0, 0, 0, 0, loc), time.Date(2024, 1, 18, 0, 0, 0, 0, loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(totals) != 2 {
+		t.Fatalf("days without time should be absent, got %v", totals)
+	}
+	if totals["2024-01-15"] != 5400 || totals["2024-01-17"] != 600 {
+		t.Fatalf("unexpected totals: %v", totals)
+	}
+}
+
+func TestGetAverageDaily(t *testing.T) {
+	s := newTestStore(t)
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s.SetLocation(loc)
+	p, _ := s.CreateProject("A", "#111", "work", "")
+
+	insert := func(start time.Time, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			p.ID, start.UTC().Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).UTC().Format(time.RFC3339), secs,
+		)
+	}
+	// 3h and 1h on two of four days.
+	insert(time.Date(2024, 1, 15, 9, 0, 0, 0, loc), 3*3600)
+	insert(time.Date(2024, 1, 17, 22, 0, 0, 0, loc), 3600)
+	from, to := time.Date(2024, 1, 15, 0, 0, 0, 0, loc), time.Date(2024, 1, 19, 0, 0, 0, 0, loc)
+
+	for _, tt := range []struct {
+		countZeroDays bool
+		want          float64
+	}{
+		{false, 2 * 3600},
+		{true, 3600},
+	} {
+		got, err := s.GetAverageDaily(from, to, tt.countZeroDays)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("countZeroDays=%v: got %v, want %v", tt.countZeroDays, got, tt.want)
+		}
+	}
+
+	if got, _ := s.GetAverageDaily(to, to.AddDate(0, 0, 3), false); got != 0 {
+		t.Fatalf("no tracked days should average 0, got %v", got)
+	}
+	if got, _ := s.GetAverageDaily(to, to, true); got != 0 {
+		t.Fatalf("an empty range should average 0, got %v", got)
 	}
 }
 
-func TestGetEntry(t *testing.T) {
+func TestGetProjectStats(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
-	entry, _ := s.StartEntry(p.ID, nil)
+	s.SetLocation(time.UTC)
+	s.SetClock(func() time.Time { return time.Date(2024, 1, 17, 12, 0, 0, 0, time.UTC) })
+	p, _ := s.CreateProject("Dev", "#111", "work", "")
+	other, _ := s.CreateProject("Ops", "#222", "work", "")
 
-	fetched, err := s.GetEntry(entry.ID)
+	insert := func(projectID int64, start time.Time, secs int) int64 {
+		res, _ := s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			projectID, start.Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).Format(time.RFC3339), secs,
+		)
+		id, _ := res.LastInsertId()
+		return id
+	}
+
+	stats, err := s.GetProjectStats(p.ID)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if fetched.ID != entry.ID {
-		t.Fatal("ID mismatch")
+	if stats.EntryCount != 0 || stats.TotalSeconds != 0 || stats.AverageSeconds != 0 || stats.FirstTracked != nil || stats.LastTracked != nil {
+		t.Fatalf("a project without entries should have zero stats: %+v", stats)
 	}
-	s.StopEntry(entry.ID)
-}
 
-func TestGetEntryNotFound(t *testing.T) {
-	s := newTestStore(t)
-	_, err := s.GetEntry(999)
-	if err == nil {
-		t.Fatal("expected error for missing entry")
+	insert(p.ID, time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC), 3600)   // last week
+	insert(p.ID, time.Date(2024, 1, 14, 10, 0, 0, 0, time.UTC), 1800) // Sunday
+	insert(p.ID, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), 7200)
+	insert(p.ID, time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), 2400)
+	s.ArchiveEntry(insert(p.ID, time.Date(2024, 1, 16, 14, 0, 0, 0, time.UTC), 9000))
+	insert(other.ID, time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), 600)
+	s.StartEntry(p.ID, nil) // running, excluded
+
+	stats, err = s.GetProjectStats(p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.EntryCount != 4 || stats.TotalSeconds != 15000 || stats.AverageSeconds != 3750 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+	if stats.WeekSeconds != 9600 {
+		t.Fatalf("week should start on Monday by default, got %ds", stats.WeekSeconds)
+	}
+	if !stats.FirstTracked.Equal(time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)) ||
+		!stats.LastTracked.Equal(time.Date(2024, 1, 16, 9, 40, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected first/last dates: %v, %v", stats.FirstTracked, stats.LastTracked)
+	}
+
+	s.SetSetting("week_start", "sunday")
+	if stats, _ = s.GetProjectStats(p.ID); stats.WeekSeconds != 11400 {
+		t.Fatalf("a Sunday week should include Sunday's entry, got %ds", stats.WeekSeconds)
 	}
 }
 
-func TestUpdateEntryNotes(t *testing.T) {
+func TestCountGoalMetDays(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
-	entry, _ := s.StartEntry(p.ID, nil)
-
-	s.UpdateEntryNotes(entry.ID, "some notes")
-	fetched, _ := s.GetEntry(entry.ID)
-	if fetched.Notes != "some notes" {
-		t.Fatalf("expected 'some notes', got %q", fetched.Notes)
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s.SetLocation(loc)
+	p, _ := s.CreateProject("A", "#111", "work", "")
+
+	insert := func(start time.Time, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			p.ID, start.UTC().Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).UTC().Format(time.RFC3339), secs,
+		)
+	}
+	// Over the week of Jan 15: 8h, 4h+4h, 7h59m, then 8h on Friday that
+	// ends on Saturday in UTC but within Friday locally.
+	insert(time.Date(2024, 1, 15, 9, 0, 0, 0, loc), 8*3600)
+	insert(time.Date(2024, 1, 16, 9, 0, 0, 0, loc), 4*3600)
+	insert(time.Date(2024, 1, 16, 14, 0, 0, 0, loc), 4*3600)
+	insert(time.Date(2024, 1, 17, 9, 0, 0, 0, loc), 8*3600-60)
+	insert(time.Date(2024, 1, 19, 14, 0, 0, 0, loc), 8*3600)
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 7)
+
+	met, total, err := s.CountGoalMetDays(from, to, 8*3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if met != 3 || total != 7 {
+		t.Fatalf("got %d of %d days, want 3 of 7", met, total)
+	}
+	if met, total, _ := s.CountGoalMetDays(from, to, 0); met != 0 || total != 7 {
+		t.Fatalf("a zero goal should never be met, got %d of %d", met, total)
 	}
-	s.StopEntry(entry.ID)
 }
 
-func TestListEntries(t *testing.T) {
+func TestTodayNearLocalMidnight(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
-
-	e1, _ := s.StartEntry(p.ID, nil)
-	s.StopEntry(e1.ID)
-	e2, _ := s.StartEntry(p.ID, nil)
-	s.StopEntry(e2.ID)
-
-	entries, err := s.ListEntries(EntryFilter{Limit: 10})
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s.SetLocation(loc)
+	// 23:40 on Jan 15 and 00:10 on Jan 16 local are both Jan 16 in UTC, so
+	// only local days tell them apart.
+	s.SetClock(func() time.Time { return time.Date(2024, 1, 16, 0, 30, 0, 0, loc) })
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	insert := func(start time.Time, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			p.ID, start.UTC().Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).UTC().Format(time.RFC3339), secs,
+		)
+	}
+	insert(time.Date(2024, 1, 15, 23, 40, 0, 0, loc), 300) // yesterday, same UTC day
+	insert(time.Date(2024, 1, 16, 0, 10, 0, 0, loc), 600)  // today
+
+	from, to := s.Today()
+	if !from.Equal(time.Date(2024, 1, 16, 0, 0, 0, 0, loc)) || !to.Equal(from.AddDate(0, 0, 1)) {
+		t.Fatalf("Today() = %v – %v", from, to)
+	}
+	total, err := s.GetTodayTotal()
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(entries) != 2 {
-		t.Fatalf("expected 2 entries, got %d", len(entries))
+	if total != 600 {
+		t.Fatalf("only the entry after local midnight is today, got %d", total)
 	}
-	// Should be ordered by start_time DESC (most recent first)
-	if entries[0].ID < entries[1].ID {
-		t.Fatal("entries should be newest first")
+	stats, err := s.GetDashboardStats(time.Monday, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TodayTotal != 600 {
+		t.Fatalf("dashboard today should agree with GetTodayTotal, got %d", stats.TodayTotal)
 	}
 }
 
-func TestListEntriesWithProjectFilter(t *testing.T) {
+func TestGetTodayTotal(t *testing.T) {
 	s := newTestStore(t)
-	p1, _ := s.CreateProject("A", "#111", "work")
-	p2, _ := s.CreateProject("B", "#222", "personal")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
-	e1, _ := s.StartEntry(p1.ID, nil)
-	s.StopEntry(e1.ID)
-	e2, _ := s.StartEntry(p2.ID, nil)
-	s.StopEntry(e2.ID)
+	insertEntry(t, s, p.ID, nil, 600, 3600)
+	insertEntry(t, s, p.ID, nil, 300, 1800)
 
-	pid := p1.ID
-	entries, _ := s.ListEntries(EntryFilter{ProjectID: &pid})
-	if len(entries) != 1 {
-		t.Fatalf("expected 1 entry for project A, got %d", len(entries))
+	total, err := s.GetTodayTotal()
+	if err != nil {
+		t.Fatal(err)
 	}
-	if entries[0].ProjectID != p1.ID {
-		t.Fatal("wrong project in filtered result")
+	if total != 5400 {
+		t.Fatalf("expected 5400s, got %d", total)
 	}
 }
 
-func TestListEntriesWithTaskFilter(t *testing.T) {
+func TestGetTodayTotalEmpty(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
-	task, _ := s.CreateTask(p.ID, "Feature", "")
-
-	tid := task.ID
-	e1, _ := s.StartEntry(p.ID, &tid)
-	s.StopEntry(e1.ID)
-	e2, _ := s.StartEntry(p.ID, nil)
-	s.StopEntry(e2.ID)
-
-	entries, _ := s.ListEntries(EntryFilter{TaskID: &tid})
-	if len(entries) != 1 {
-		t.Fatalf("expected 1 entry for task, got %d", len(entries))
+	total, err := s.GetTodayTotal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("expected 0 for empty, got %d", total)
 	}
 }
 
-func TestListEntriesWithDateFilter(t *testing.T) {
+func TestGetProjectTodayTotal(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
 
-	insertEntry(t, s, p.ID, nil, 7200, 3600) // 2h ago, 1h duration
-	insertEntry(t, s, p.ID, nil, 600, 300)   // 10min ago, 5min duration
+	insertEntry(t, s, dev.ID, nil, 600, 3600)
+	insertEntry(t, s, ops.ID, nil, 300, 1800)
 
-	now := time.Now().UTC()
-	from := now.Add(-1 * time.Hour)
-	to := now.Add(1 * time.Hour)
-	entries, _ := s.ListEntries(EntryFilter{From: &from, To: &to})
-	if len(entries) != 1 {
-		t.Fatalf("expected 1 entry in last hour, got %d", len(entries))
+	total, err := s.GetProjectTodayTotal(dev.ID)
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestListEntriesWithLimit(t *testing.T) {
-	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
-	for i := 0; i < 5; i++ {
-		insertEntry(t, s, p.ID, nil, i*100, 60)
+	if total != 3600 {
+		t.Fatalf("expected 3600s for Dev, got %d", total)
 	}
 
-	entries, _ := s.ListEntries(EntryFilter{Limit: 3})
-	if len(entries) != 3 {
-		t.Fatalf("expected 3 entries with limit, got %d", len(entries))
+	totals, err := s.GetProjectTodayTotals()
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestListEntriesNoFilter(t *testing.T) {
-	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
-	for i := 0; i < 5; i++ {
-		insertEntry(t, s, p.ID, nil, i*100, 60)
+	if len(totals) != 2 || totals[dev.ID] != 3600 || totals[ops.ID] != 1800 {
+		t.Fatalf("unexpected per-project totals: %v", totals)
 	}
 
-	entries, _ := s.ListEntries(EntryFilter{})
-	if len(entries) != 5 {
-		t.Fatalf("expected 5 entries without limit, got %d", len(entries))
+	if err := s.SetProjectDailyGoal(dev.ID, 7200); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.GetProject(dev.ID)
+	if got.DailyGoal != 7200 {
+		t.Fatalf("DailyGoal = %d, want 7200", got.DailyGoal)
+	}
+	if err := s.SetProjectDailyGoal(dev.ID, -1); err == nil {
+		t.Fatal("expected an error for a negative goal")
 	}
 }
 
-func TestGetDailySummary(t *testing.T) {
+func TestGetDashboardStats(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p1, _ := s.CreateProject("A", "#111", "work", "")
+	p2, _ := s.CreateProject("B", "#222", "work", "")
+	archived, _ := s.CreateProject("Old", "#333", "work", "")
+	s.ArchiveProject(archived.ID)
 
-	now := time.Now().UTC()
-	start := now.Add(-1 * time.Hour)
-	s.db.Exec(
-		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
-		p.ID, start.Format(time.RFC3339), now.Format(time.RFC3339), 3600,
-	)
+	insertEntry(t, s, p1.ID, nil, 60, 600)
+	insertEntry(t, s, p2.ID, nil, 120, 900)
 
-	from := now.Add(-24 * time.Hour)
-	to := now.Add(24 * time.Hour)
-	summaries, err := s.GetDailySummary(from, to)
+	stats, err := s.GetDashboardStats(time.Monday, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(summaries) != 1 {
-		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	today, _ := s.GetTodayTotal()
+	if stats.TodayTotal != today {
+		t.Fatalf("TodayTotal = %d, want %d", stats.TodayTotal, today)
 	}
-	if summaries[0].TotalSeconds != 3600 {
-		t.Fatalf("expected 3600s, got %d", summaries[0].TotalSeconds)
+	if len(stats.RecentEntries) != 2 {
+		t.Fatalf("expected 2 recent entries, got %d", len(stats.RecentEntries))
 	}
-	if summaries[0].ProjectName != "Dev" {
-		t.Fatalf("expected project name Dev, got %s", summaries[0].ProjectName)
+	week, _ := s.GetWeekTotal(time.Monday)
+	if stats.WeekTotal != week {
+		t.Fatalf("WeekTotal = %d, want %d", stats.WeekTotal, week)
 	}
-	if summaries[0].EntryCount != 1 {
-		t.Fatalf("expected 1 entry, got %d", summaries[0].EntryCount)
+	if stats.ActiveProjects != 2 {
+		t.Fatalf("ActiveProjects = %d, want 2", stats.ActiveProjects)
+	}
+	if stats, _ = s.GetDashboardStats(time.Monday, 1); len(stats.RecentEntries) != 1 {
+		t.Fatalf("recentLimit should cap recent entries, got %d", len(stats.RecentEntries))
 	}
 }
 
-func TestGetDailySummaryMultipleProjects(t *testing.T) {
+func TestRecords(t *testing.T) {
 	s := newTestStore(t)
-	p1, _ := s.CreateProject("A", "#111", "work")
-	p2, _ := s.CreateProject("B", "#222", "personal")
+	s.SetLocation(time.UTC)
 
-	now := time.Now().UTC()
-	start := now.Add(-1 * time.Hour)
-	s.db.Exec(
-		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
-		p1.ID, start.Format(time.RFC3339), now.Format(time.RFC3339), 3600,
-	)
-	s.db.Exec(
-		`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
-		p2.ID, start.Format(time.RFC3339), now.Format(time.RFC3339), 1800,
-	)
-
-	from := now.Add(-24 * time.Hour)
-	to := now.Add(24 * time.Hour)
-	summaries, _ := s.GetDailySummary(from, to)
-	if len(summaries) != 2 {
-		t.Fatalf("expected 2 summaries (one per project), got %d", len(summaries))
+	if e, err := s.GetLongestSession(); err != nil || e != nil {
+		t.Fatalf("empty history: got %v, %v", e, err)
+	}
+	if d, secs, err := s.GetMostProductiveDay(); err != nil || d != "" || secs != 0 {
+		t.Fatalf("empty history: got %q, %d, %v", d, secs, err)
+	}
+	if p, secs, err := s.GetTopProject(); err != nil || p != nil || secs != 0 {
+		t.Fatalf("empty history: got %v, %d, %v", p, secs, err)
 	}
-}
-
-func TestGetDailySummaryExcludesRunning(t *testing.T) {
-	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
 
-	// Running entry (no end_time)
-	s.StartEntry(p.ID, nil)
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
+	insert := func(pid int64, start string, secs int) int64 {
+		st, _ := time.Parse(time.RFC3339, start)
+		res, err := s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			pid, start, st.Add(time.Duration(secs)*time.Second).Format(time.RFC3339), secs,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := res.LastInsertId()
+		return id
+	}
+	insert(dev.ID, "2024-01-15T09:00:00Z", 2*3600)
+	insert(dev.ID, "2024-01-15T13:00:00Z", 2*3600)
+	longest := insert(ops.ID, "2024-01-16T09:00:00Z", 3*3600)
+	archived := insert(ops.ID, "2024-01-17T09:00:00Z", 10*3600)
+	s.ArchiveEntry(archived)
 
-	now := time.Now().UTC()
-	from := now.Add(-24 * time.Hour)
-	to := now.Add(24 * time.Hour)
-	summaries, _ := s.GetDailySummary(from, to)
-	if len(summaries) != 0 {
-		t.Fatal("running entries should be excluded from daily summary")
+	e, err := s.GetLongestSession()
+	if err != nil || e == nil || e.ID != longest {
+		t.Fatalf("longest session should be entry %d, got %+v, %v", longest, e, err)
+	}
+	d, secs, err := s.GetMostProductiveDay()
+	if err != nil || d != "2024-01-15" || secs != 4*3600 {
+		t.Fatalf("most productive day = %q, %d, %v", d, secs, err)
+	}
+	p, secs, err := s.GetTopProject()
+	if err != nil || p == nil || p.ID != dev.ID || secs != 4*3600 {
+		t.Fatalf("top project = %+v, %d, %v", p, secs, err)
 	}
 }
 
-func TestGetDailySummaryEmpty(t *testing.T) {
+func TestTotalsSplitAtMidnight(t *testing.T) {
 	s := newTestStore(t)
-	now := time.Now().UTC()
-	summaries, err := s.GetDailySummary(now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	s.SetLocation(time.UTC)
+	s.SetClock(func() time.Time { return time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC) }) // Tuesday
+	p, _ := s.CreateProject("Dev", "#111", "work", "")
+	insert := func(start time.Time, secs int) {
+		s.db.Exec(
+			`INSERT INTO time_entries (project_id, start_time, end_time, duration) VALUES (?, ?, ?, ?)`,
+			p.ID, start.Format(time.RFC3339), start.Add(time.Duration(secs)*time.Second).Format(time.RFC3339), secs,
+		)
+	}
+	insert(time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC), 2*3600) // Sunday into Monday
+	insert(time.Date(2024, 1, 15, 22, 0, 0, 0, time.UTC), 4*3600) // Monday into today
+
+	today, err := s.GetTodayTotal()
 	if err != nil {
 		t.Fatal(err)
 	}
-	if summaries != nil {
-		t.Fatal("expected nil for empty summary")
+	dayStart, dayEnd := s.Today()
+	summary, _ := s.GetDailySummary(dayStart, dayEnd)
+	if today != 2*3600 || len(summary) != 1 || summary[0].TotalSeconds != today {
+		t.Fatalf("today should count only the part after midnight, as the summary does: %d vs %+v", today, summary)
+	}
+	if got, _ := s.GetProjectTodayTotal(p.ID); got != today {
+		t.Fatalf("project today total = %d, want %d", got, today)
+	}
+	if got, _ := s.GetProjectTodayTotals(); got[p.ID] != today {
+		t.Fatalf("project today totals = %v, want %d", got, today)
+	}
+
+	// The Sunday entry has one hour in a Monday week.
+	if got, _ := s.GetWeekTotal(time.Monday); got != 5*3600 {
+		t.Fatalf("week total should split at the week start, got %d", got)
+	}
+	stats, _ := s.GetDashboardStats(time.Monday, 0)
+	if stats.TodayTotal != today || stats.WeekTotal != 5*3600 {
+		t.Fatalf("dashboard stats should agree: %+v", stats)
 	}
 }
 
-func TestGetTodayTotal(t *testing.T) {
+func TestGetWeekTotal(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	insertEntry(t, s, p.ID, nil, 600, 3600)
-	insertEntry(t, s, p.ID, nil, 300, 1800)
+	insertEntry(t, s, p.ID, nil, 14*24*3600, 1800) // two weeks ago
+	s.StartEntry(p.ID, nil)                        // running, excluded
 
-	total, err := s.GetTodayTotal()
+	total, err := s.GetWeekTotal(time.Monday)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if total != 5400 {
-		t.Fatalf("expected 5400s, got %d", total)
+	if total != 3600 {
+		t.Fatalf("expected 3600s, got %d", total)
 	}
 }
 
-func TestGetTodayTotalEmpty(t *testing.T) {
+func TestStartOfWeek(t *testing.T) {
+	// Wednesday 2024-01-17 15:30 UTC
+	wed := time.Date(2024, 1, 17, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		weekStart time.Weekday
+		want      time.Time
+	}{
+		{time.Monday, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Sunday, time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{time.Wednesday, time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := StartOfWeek(wed, tt.weekStart); !got.Equal(tt.want) {
+			t.Errorf("StartOfWeek(%v) = %v, want %v", tt.weekStart, got, tt.want)
+		}
+	}
+}
+
+func TestWeekStart(t *testing.T) {
 	s := newTestStore(t)
-	total, err := s.GetTodayTotal()
-	if err != nil {
-		t.Fatal(err)
+	if s.WeekStart() != time.Monday {
+		t.Fatal("default week start should be Monday")
 	}
-	if total != 0 {
-		t.Fatalf("expected 0 for empty, got %d", total)
+	s.SetSetting("week_start", "sunday")
+	if s.WeekStart() != time.Sunday {
+		t.Fatal("sunday should map to time.Sunday")
+	}
+	s.SetSetting("week_start", "bogus")
+	if s.WeekStart() != time.Monday {
+		t.Fatal("unknown values should fall back to Monday")
 	}
 }
 
 func TestGetTodayTotalExcludesRunning(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	s.StartEntry(p.ID, nil) // running, no end_time
 
 	total, _ := s.GetTodayTotal()
@@ -750,7 +2746,7 @@ func TestPomodoroLifecycle(t *testing.T) {
 
 func TestPomodoroWithTimeEntry(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	entry, _ := s.StartEntry(p.ID, nil)
 
 	eid := entry.ID
@@ -836,6 +2832,39 @@ func TestGetPomodoroStatsEmpty(t *testing.T) {
 	}
 }
 
+func TestListAndDeletePomodoroSessions(t *testing.T) {
+	s := newTestStore(t)
+	old, _ := s.StartPomodoro(nil, 1500, 300, 4)
+	s.db.Exec(`UPDATE pomodoro_sessions SET started_at = ? WHERE id = ?`,
+		time.Now().AddDate(0, 0, -10).UTC().Format(time.RFC3339), old.ID)
+	done, _ := s.StartPomodoro(nil, 1500, 300, 4)
+	s.CompletePomodoro(done.ID)
+	cancelled, _ := s.StartPomodoro(nil, 1500, 300, 4)
+	s.CancelPomodoro(cancelled.ID)
+
+	now := time.Now()
+	sessions, err := s.ListPomodoroSessions(now.AddDate(0, 0, -7), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != cancelled.ID || sessions[1].ID != done.ID {
+		t.Fatalf("expected the two sessions in range, newest first, got %+v", sessions)
+	}
+	if sessions[0].Status != "cancelled" || sessions[1].CompletedCount != 4 {
+		t.Fatalf("sessions should carry their status and counts, got %+v", sessions)
+	}
+
+	if err := s.DeletePomodoroSession(cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, _ := s.ListPomodoroSessions(now.AddDate(0, 0, -7), now.Add(time.Hour)); len(sessions) != 1 {
+		t.Fatalf("deleted session still listed: %+v", sessions)
+	}
+	if err := s.DeletePomodoroSession(cancelled.ID); err == nil {
+		t.Fatal("deleting a missing session should fail")
+	}
+}
+
 // ============================================================
 // Settings
 // ============================================================
@@ -899,6 +2928,41 @@ func TestSetSettingOverwrite(t *testing.T) {
 	}
 }
 
+func TestTypedSettings(t *testing.T) {
+	s := newTestStore(t)
+	s.SetSetting("count", "7")
+	s.SetSetting("flag", "false")
+	s.SetSetting("bad", "seven")
+	s.SetSetting("zero", "0")
+
+	if got := s.GetSettingInt("count", 1); got != 7 {
+		t.Fatalf("GetSettingInt = %d, want 7", got)
+	}
+	if got := s.GetSettingBool("flag", true); got {
+		t.Fatal("GetSettingBool should read false")
+	}
+	if got := s.GetSettingDuration("pomodoro_work", time.Minute); got != 25*time.Minute {
+		t.Fatalf("GetSettingDuration = %v, want 25m", got)
+	}
+
+	// Malformed, missing and non-positive values fall back.
+	if got := s.GetSettingInt("bad", 3); got != 3 {
+		t.Fatalf("malformed int: got %d, want fallback 3", got)
+	}
+	if got := s.GetSettingInt("missing", 3); got != 3 {
+		t.Fatalf("missing int: got %d, want fallback 3", got)
+	}
+	if got := s.GetSettingBool("bad", true); !got {
+		t.Fatal("malformed bool should return the fallback")
+	}
+	if got := s.GetSettingDuration("bad", time.Hour); got != time.Hour {
+		t.Fatalf("malformed duration: got %v, want fallback", got)
+	}
+	if got := s.GetSettingDuration("zero", time.Hour); got != time.Hour {
+		t.Fatalf("zero duration: got %v, want fallback", got)
+	}
+}
+
 func TestGetSettingNotFound(t *testing.T) {
 	s := newTestStore(t)
 	_, err := s.GetSetting("nonexistent")
@@ -924,6 +2988,64 @@ func TestGetAllSettings(t *testing.T) {
 	}
 }
 
+func TestSetSettings(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.SetSettings(map[string]string{"daily_goal": "3600", "sound": "false", "new_key": "v"}); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{"daily_goal": "3600", "sound": "false", "new_key": "v"} {
+		if got, _ := s.GetSetting(k); got != want {
+			t.Errorf("%s = %q, want %q", k, got, want)
+		}
+	}
+
+	// A key that fails part-way rolls back the keys saved before it.
+	if _, err := s.db.Exec(`CREATE TRIGGER reject_bad BEFORE INSERT ON settings
+		WHEN NEW.key = 'zz_bad' BEGIN SELECT RAISE(ABORT, 'rejected'); END`); err != nil {
+		t.Fatal(err)
+	}
+	err := s.SetSettings(map[string]string{"daily_goal": "7200", "sound": "true", "zz_bad": "x"})
+	if err == nil || !strings.Contains(err.Error(), "zz_bad") {
+		t.Fatalf("expected an error naming the failing key, got %v", err)
+	}
+	if got, _ := s.GetSetting("daily_goal"); got != "3600" {
+		t.Fatalf("daily_goal = %q after a failed batch, want the old 3600", got)
+	}
+	if got, _ := s.GetSetting("sound"); got != "false" {
+		t.Fatalf("sound = %q after a failed batch, want the old false", got)
+	}
+}
+
+func TestExportImportSettings(t *testing.T) {
+	src := newTestStore(t)
+	src.SetSetting("daily_goal", "21600")
+	src.SetSetting("week_start", "sunday")
+	src.SetSetting("from_a_newer_version", "kept")
+	want, err := src.ExportSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, _ := src.GetAllSettings()
+	if len(want) != len(all) || want["from_a_newer_version"] != "kept" {
+		t.Fatalf("export should include every setting, got %v", want)
+	}
+
+	dst := newTestStore(t)
+	dst.SetSetting("local_only", "x")
+	if err := dst.ImportSettings(want); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := dst.ExportSettings()
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+	if got["local_only"] != "x" {
+		t.Fatal("import should leave settings missing from the file alone")
+	}
+}
+
 // ============================================================
 // Foreign key constraints
 // ============================================================
@@ -970,7 +3092,7 @@ func TestStopEntryNonExistent(t *testing.T) {
 
 func TestMultipleRunningEntries(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	s.StartEntry(p.ID, nil)
 	s.StartEntry(p.ID, nil)
@@ -1,16 +1,26 @@
 package store
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
+// ErrTaskExists is returned when a project already has a task with the name.
+var ErrTaskExists = errors.New("task already exists")
+
 func (s *Store) CreateTask(projectID int64, name, tags string) (*Task, error) {
 	now := time.Now().UTC().Format(time.RFC3339)
 	res, err := s.db.Exec(
 		`INSERT INTO tasks (project_id, name, tags, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
 		projectID, name, tags, now, now,
 	)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: %q", ErrTaskExists, name)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("insert task: %w", err)
 	}
@@ -18,28 +28,59 @@ func (s *Store) CreateTask(projectID int64, name, tags string) (*Task, error) {
 	return s.GetTask(id)
 }
 
-func (s *Store) GetTask(id int64) (*Task, error) {
+// UntaggedLabel is the tag summary bucket for entries whose task has no
+// tags, or that have no task.
+const UntaggedLabel = "(untagged)"
+
+// ParseTags splits a task's comma-separated tags, trimming spaces and a
+// leading '#'. Empty and repeated tags are dropped.
+func ParseTags(tags string) []string {
+	var out []string
+	for _, t := range strings.Split(tags, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "#")
+		if t != "" && !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+const taskColumns = `id, project_id, name, tags, archived, completed_at, created_at, updated_at`
+
+// scanTask reads a row selected with taskColumns.
+func scanTask(row interface{ Scan(...any) error }) (*Task, error) {
 	t := &Task{}
 	var createdAt, updatedAt string
+	var completedAt sql.NullString
 	var archived int
-	err := s.db.QueryRow(
-		`SELECT id, project_id, name, tags, archived, created_at, updated_at FROM tasks WHERE id = ?`, id,
-	).Scan(&t.ID, &t.ProjectID, &t.Name, &t.Tags, &archived, &createdAt, &updatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("get task %d: %w", id, err)
+	if err := row.Scan(&t.ID, &t.ProjectID, &t.Name, &t.Tags, &archived, &completedAt, &createdAt, &updatedAt); err != nil {
+		return nil, err
 	}
 	t.Archived = archived == 1
+	if completedAt.Valid {
+		c, _ := time.Parse(time.RFC3339, completedAt.String)
+		t.CompletedAt = &c
+	}
 	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 	return t, nil
 }
 
+func (s *Store) GetTask(id int64) (*Task, error) {
+	t, err := scanTask(s.db.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("get task %d: %w", id, err)
+	}
+	return t, nil
+}
+
+// ListTasks returns a project's tasks, open ones first, then by name.
 func (s *Store) ListTasks(projectID int64, includeArchived bool) ([]Task, error) {
-	query := `SELECT id, project_id, name, tags, archived, created_at, updated_at FROM tasks WHERE project_id = ?`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE project_id = ?`
 	if !includeArchived {
 		query += ` AND archived = 0`
 	}
-	query += ` ORDER BY name`
+	query += ` ORDER BY completed_at IS NOT NULL, name`
 
 	rows, err := s.db.Query(query, projectID)
 	if err != nil {
@@ -49,16 +90,11 @@ func (s *Store) ListTasks(projectID int64, includeArchived bool) ([]Task, error)
 
 	var tasks []Task
 	for rows.Next() {
-		var t Task
-		var createdAt, updatedAt string
-		var archived int
-		if err := rows.Scan(&t.ID, &t.ProjectID, &t.Name, &t.Tags, &archived, &createdAt, &updatedAt); err != nil {
+		t, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
-		t.Archived = archived == 1
-		t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
-		tasks = append(tasks, t)
+		tasks = append(tasks, *t)
 	}
 	return tasks, rows.Err()
 }
@@ -72,6 +108,59 @@ func (s *Store) UpdateTask(id int64, name, tags string) error {
 	return err
 }
 
+// MoveTask moves a task to another project. The task's time entries move
+// with it, so an entry's project always matches its task's project. It
+// returns ErrTaskExists if the target project already has a task with the
+// same name.
+func (s *Store) MoveTask(taskID, newProjectID int64) error {
+	task, err := s.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("move task: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err = tx.Exec(
+		`UPDATE tasks SET project_id = ?, updated_at = ? WHERE id = ?`, newProjectID, now, taskID,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %q", ErrTaskExists, task.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("move task: %w", err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE time_entries SET project_id = ? WHERE task_id = ?`, newProjectID, taskID,
+	); err != nil {
+		return fmt.Errorf("move task entries: %w", err)
+	}
+	return tx.Commit()
+}
+
+// CompleteTask marks a task done now. Unlike archiving, a completed task
+// stays listed.
+func (s *Store) CompleteTask(id int64) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(
+		`UPDATE tasks SET completed_at = ?, updated_at = ? WHERE id = ?`, now, now, id,
+	)
+	return err
+}
+
+// ReopenTask clears a task's completion.
+func (s *Store) ReopenTask(id int64) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(
+		`UPDATE tasks SET completed_at = NULL, updated_at = ? WHERE id = ?`, now, id,
+	)
+	return err
+}
+
 func (s *Store) ArchiveTask(id int64) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 	_, err := s.db.Exec(
@@ -79,3 +168,19 @@ func (s *Store) ArchiveTask(id int64) error {
 	)
 	return err
 }
+
+// ArchiveTasksForProject archives a project's tasks in one go, or only its
+// completed ones when onlyCompleted is set, and reports how many it
+// archived. Entries keep their task, which stays readable when archived.
+func (s *Store) ArchiveTasksForProject(projectID int64, onlyCompleted bool) (int64, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := `UPDATE tasks SET archived = 1, updated_at = ? WHERE project_id = ? AND archived = 0`
+	if onlyCompleted {
+		query += ` AND completed_at IS NOT NULL`
+	}
+	res, err := s.db.Exec(query, now, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("archive tasks: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SaveTimerState records how long a running entry has been paused, so a
+// paused timer survives a restart.
+func (s *Store) SaveTimerState(st TimerState) error {
+	var pausedAt any
+	if st.PausedAt != nil {
+		pausedAt = st.PausedAt.UTC().Format(time.RFC3339)
+	}
+	_, err := s.db.Exec(
+		`INSERT OR REPLACE INTO timer_state (entry_id, pause_gap, paused_at) VALUES (?, ?, ?)`,
+		st.EntryID, st.PauseGap, pausedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save timer state: %w", err)
+	}
+	return nil
+}
+
+// GetTimerState returns the saved pause state of an entry, or nil if it was
+// never paused.
+func (s *Store) GetTimerState(entryID int64) (*TimerState, error) {
+	st := &TimerState{EntryID: entryID}
+	var pausedAt sql.NullString
+	err := s.db.QueryRow(
+		`SELECT pause_gap, paused_at FROM timer_state WHERE entry_id = ?`, entryID,
+	).Scan(&st.PauseGap, &pausedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get timer state: %w", err)
+	}
+	if pausedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, pausedAt.String)
+		st.PausedAt = &t
+	}
+	return st, nil
+}
+
+// PausedSeconds is the time an entry has spent paused as of now, including
+// a pause still in progress. StopEntry leaves it out of the duration.
+func (s *Store) PausedSeconds(entryID int64, now time.Time) (int64, error) {
+	st, err := s.GetTimerState(entryID)
+	if err != nil || st == nil {
+		return 0, err
+	}
+	secs := st.PauseGap
+	if st.PausedAt != nil && now.After(*st.PausedAt) {
+		secs += int64(now.Sub(*st.PausedAt).Seconds())
+	}
+	return secs, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -24,6 +25,12 @@ type App struct {
 	showHelp      bool
 	exportPicking bool
 	exportCursor  int
+	quitConfirm   bool
+	quitCursor    int
+	focusMode     bool     // full-screen timer, no tabs or footer
+	orphans       []orphan // long-running timers found on launch, still to resolve
+	orphanCursor  int
+	title         string // terminal title last set, "" if none
 
 	dashboard dashboardModel
 	projects  projectsModel
@@ -38,6 +45,10 @@ type App struct {
 func NewApp(s *store.Store) App {
 	h := help.New()
 	h.ShowAll = false
+	loadAccessibleMode(s)
+	loadTerminalTitle(s)
+	loadMultiTimer(s)
+	loadDurationFormat(s)
 
 	return App{
 		store:      s,
@@ -47,6 +58,7 @@ func NewApp(s *store.Store) App {
 		reports:    newReportsModel(s),
 		pomodoro:   newPomodoroModel(s),
 		settings:   newSettingsModel(s),
+		orphans:    loadOrphans(s),
 		help:       h,
 	}
 }
@@ -54,16 +66,31 @@ func NewApp(s *store.Store) App {
 func (a App) Init() tea.Cmd {
 	return tea.Batch(
 		a.dashboard.Init(),
-		tickCmd(),
+		tickCmd(slowTick),
 	)
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+// Tick rates: a fast tick keeps running countdowns from visibly skipping
+// seconds, and the slow tick keeps CPU use low when nothing is timing.
+const (
+	slowTick = time.Second
+	fastTick = 250 * time.Millisecond
+)
+
+func tickCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
+// tickRate returns the interval for the next tick.
+func (a App) tickRate() time.Duration {
+	if (a.dashboard.isRunning() && !a.dashboard.isPaused()) || a.pomodoro.timedPhase() {
+		return fastTick
+	}
+	return slowTick
+}
+
 func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
@@ -78,14 +105,32 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.reports.setSize(a.width, contentHeight)
 		a.pomodoro.setSize(a.width, contentHeight)
 		a.settings.setSize(a.width, contentHeight)
+		if a.reports.mode == reportHeatmap {
+			// The heatmap's range depends on how many weeks fit.
+			return a, a.reports.refresh()
+		}
 		return a, nil
 
 	case tea.KeyMsg:
+		// Orphaned timers are resolved before anything else.
+		if len(a.orphans) > 0 {
+			return a.updateOrphanPrompt(msg)
+		}
+
 		// Export picker
 		if a.exportPicking {
 			return a.updateExportPicker(msg)
 		}
 
+		// Quit confirmation
+		if a.quitConfirm {
+			return a.updateQuitConfirm(msg)
+		}
+
+		if a.focusMode && !key.Matches(msg, keys.Quit) {
+			return a.updateFocus(msg)
+		}
+
 		// If a child view is capturing input (e.g. form), delegate first.
 		if a.isFormActive() {
 			return a.updateActiveView(msg)
@@ -97,7 +142,17 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.exportCursor = 0
 			return a, nil
 		case key.Matches(msg, keys.Quit):
-			return a, tea.Quit
+			if a.dashboard.isRunning() {
+				a.quitConfirm = true
+				a.quitCursor = 0
+				return a, nil
+			}
+			return a.quit()
+		case key.Matches(msg, keys.Focus):
+			a.focusMode = true
+			return a, nil
+		case a.activeView != viewDashboard && a.isTimerKey(msg):
+			return a.updateTimerFromView(msg)
 		case key.Matches(msg, keys.Help):
 			a.showHelp = !a.showHelp
 			a.help.ShowAll = a.showHelp
@@ -113,7 +168,7 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, a.reports.refresh()
 		case key.Matches(msg, keys.Tab4):
 			a.activeView = viewPomodoro
-			return a, nil
+			return a, a.pomodoro.refresh()
 		case key.Matches(msg, keys.Tab5):
 			a.activeView = viewSettings
 			return a, a.settings.refresh()
@@ -123,7 +178,6 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tickMsg:
-		cmds = append(cmds, tickCmd())
 		// Always route ticks to dashboard timer
 		var cmd tea.Cmd
 		a.dashboard, cmd = a.dashboard.update(msg)
@@ -135,8 +189,19 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+		a, cmd = a.syncTitle()
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, tickCmd(a.tickRate()))
 		return a, tea.Batch(cmds...)
 
+	case systemIdleMsg:
+		// The dashboard timer asked, whichever view is showing.
+		var cmd tea.Cmd
+		a.dashboard, cmd = a.dashboard.update(msg)
+		return a, cmd
+
 	case statusMsg:
 		a.status = msg.text
 		return a, nil
@@ -153,11 +218,58 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.status = "Exported to " + msg.path
 		a.exportPicking = false
 		return a, nil
+
+	case showProjectTasksMsg:
+		a.activeView = viewProjects
+		var cmd tea.Cmd
+		a.projects, cmd = a.projects.focusProject(msg.projectID)
+		return a, cmd
 	}
 
 	return a.updateActiveView(msg)
 }
 
+// isTimerKey reports whether msg controls the dashboard timer from the
+// active view. Views that give the same keys their own meaning keep them.
+func (a App) isTimerKey(msg tea.KeyMsg) bool {
+	switch a.activeView {
+	case viewPomodoro:
+		return false
+	case viewProjects:
+		if a.projects.viewingTasks && key.Matches(msg, keys.Pause) {
+			return false
+		}
+	}
+	return key.Matches(msg, keys.Start, keys.Backdate, keys.Stop, keys.Pause)
+}
+
+// updateTimerFromView routes a timer key to the dashboard, as ticks are. A
+// start that needs the project picker switches to the dashboard to show it.
+func (a App) updateTimerFromView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	a.dashboard, cmd = a.dashboard.update(msg)
+	if a.dashboard.picking {
+		a.activeView = viewDashboard
+	}
+	return a, cmd
+}
+
+// updateFocus handles keys in focus mode: the timer still pauses and stops,
+// esc or F leaves, and any other key only counts as activity.
+func (a App) updateFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, keys.Pause), key.Matches(msg, keys.Stop):
+		a.dashboard, cmd = a.dashboard.update(msg)
+	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Focus):
+		a.focusMode = false
+		fallthrough
+	default:
+		a.dashboard.timer.recordActivity()
+	}
+	return a, cmd
+}
+
 func (a App) updateActiveView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch a.activeView {
@@ -177,10 +289,14 @@ func (a App) updateActiveView(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (a App) isFormActive() bool {
 	switch a.activeView {
+	case viewDashboard:
+		return a.dashboard.picking || a.dashboard.formActive || a.dashboard.noting || a.dashboard.stoppingAt
 	case viewProjects:
 		return a.projects.formActive
 	case viewSettings:
 		return a.settings.formActive
+	case viewReports:
+		return a.reports.formActive
 	case viewPomodoro:
 		return a.pomodoro.formActive
 	}
@@ -195,6 +311,8 @@ func (a App) refreshCurrentView() tea.Cmd {
 		return a.projects.refresh()
 	case viewReports:
 		return a.reports.refresh()
+	case viewPomodoro:
+		return a.pomodoro.refresh()
 	case viewSettings:
 		return a.settings.refresh()
 	}
@@ -205,6 +323,9 @@ func (a App) View() string {
 	if a.width == 0 {
 		return "Loading..."
 	}
+	if a.focusMode && !a.quitConfirm {
+		return a.dashboard.renderFocus(a.width, a.height)
+	}
 
 	header := a.renderHeader()
 	footer := a.renderFooter()
@@ -236,6 +357,15 @@ func (a App) View() string {
 		content = a.renderExportPicker(contentHeight)
 	}
 
+	// Show quit confirmation overlay
+	if a.quitConfirm {
+		content = a.renderQuitConfirm()
+	}
+
+	if len(a.orphans) > 0 {
+		content = a.renderOrphanPrompt()
+	}
+
 	content = lipgloss.NewStyle().
 		Width(a.width).
 		Height(contentHeight).
@@ -269,7 +399,7 @@ func (a App) renderHeader() string {
 }
 
 func (a App) renderFooter() string {
-	helpView := a.help.View(keys)
+	helpView := a.help.View(viewHelp{view: a.activeView})
 
 	status := ""
 	if a.status != "" {
@@ -284,6 +414,9 @@ func (a App) renderFooter() string {
 		if a.dashboard.isPaused() {
 			timerInfo = warningStyle.Render(" ⏸ " + formatDuration(elapsed))
 		}
+		if n := a.dashboard.activeTimers(); n > 1 {
+			timerInfo += mutedStyle.Render(fmt.Sprintf(" (%d timers)", n))
+		}
 	}
 
 	left := footerStyle.Render(helpView)
@@ -300,7 +433,10 @@ func (a App) renderFooter() string {
 
 func (a App) renderExportPicker(_ int) string {
 	title := titleStyle.Render("Export Format")
-	formats := []string{"CSV", "JSON"}
+	if a.activeView == viewReports {
+		title = titleStyle.Render("Export Report")
+	}
+	formats := a.exportFormats()
 	var rows []string
 	rows = append(rows, title)
 	rows = append(rows, "")
@@ -320,6 +456,15 @@ func (a App) renderExportPicker(_ int) string {
 	return activePanelStyle.Width(w).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
 }
 
+// exportFormats lists the export picker's choices; Reports can also export
+// its chart as text.
+func (a App) exportFormats() []string {
+	if a.activeView == viewReports {
+		return []string{"CSV", "JSON", "Chart (text)"}
+	}
+	return []string{"CSV", "JSON"}
+}
+
 func (a App) updateExportPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, keys.Up):
@@ -327,7 +472,7 @@ func (a App) updateExportPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.exportCursor--
 		}
 	case key.Matches(msg, keys.Down):
-		if a.exportCursor < 1 {
+		if a.exportCursor < len(a.exportFormats())-1 {
 			a.exportCursor++
 		}
 	case key.Matches(msg, keys.Enter):
@@ -339,32 +484,94 @@ func (a App) updateExportPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+var quitOptions = []string{"Stop and quit", "Quit anyway", "Cancel"}
+
+func (a App) renderQuitConfirm() string {
+	title := warningStyle.Bold(true).Render("Timer still running")
+	var rows []string
+	rows = append(rows, title)
+	rows = append(rows, "")
+	for i, o := range quitOptions {
+		cursor := "  "
+		style := normalItemStyle
+		if i == a.quitCursor {
+			cursor = "> "
+			style = selectedItemStyle
+		}
+		rows = append(rows, style.Render(cursor+o))
+	}
+	rows = append(rows, "")
+	rows = append(rows, mutedStyle.Render("  enter: confirm  esc: cancel"))
+
+	w := a.width - 4
+	return activePanelStyle.Width(w).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (a App) updateQuitConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Up):
+		if a.quitCursor > 0 {
+			a.quitCursor--
+		}
+	case key.Matches(msg, keys.Down):
+		if a.quitCursor < len(quitOptions)-1 {
+			a.quitCursor++
+		}
+	case key.Matches(msg, keys.Enter):
+		a.quitConfirm = false
+		switch a.quitCursor {
+		case 0:
+			d, err := a.dashboard.stopAllTimers()
+			a.dashboard = d
+			if err != nil {
+				// Stay open so the error is visible.
+				return a, errorStatus(err)
+			}
+			return a.quit()
+		case 1:
+			return a.quit()
+		}
+	case key.Matches(msg, keys.Back):
+		a.quitConfirm = false
+	}
+	return a, nil
+}
+
 func (a App) doExport(format int) tea.Cmd {
+	if a.activeView == viewReports {
+		home, _ := os.UserHomeDir()
+		if format == 2 {
+			return a.reports.exportChart(home)
+		}
+		return a.reports.exportSummaries(format, home)
+	}
 	return func() tea.Msg {
 		entries, err := a.store.ListEntries(store.EntryFilter{})
 		if err != nil {
 			return statusMsg{text: fmt.Sprintf("Export error: %v", err), isError: true}
 		}
 
-		// Build project lookup
-		projects := make(map[int64]*store.Project)
-		plist, _ := a.store.ListProjects(true)
-		for i := range plist {
-			projects[plist[i].ID] = &plist[i]
+		projects, err := a.store.GetProjectColorMap(true)
+		if err != nil {
+			return statusMsg{text: fmt.Sprintf("Export error: %v", err), isError: true}
 		}
 
+		ext := "csv"
+		if format != 0 {
+			ext = "json"
+		}
+		name, err := export.FileName(a.exportFileTemplate(), time.Now().Format("2006-01-02"), entriesRange(entries), ext)
+		if err != nil {
+			return statusMsg{text: fmt.Sprintf("Export error: %v", err), isError: true}
+		}
 		home, _ := os.UserHomeDir()
-		dateStr := time.Now().Format("2006-01-02")
-
-		var path string
+		path := filepath.Join(home, name)
 		if format == 0 {
-			path = filepath.Join(home, fmt.Sprintf("trackr-export-%s.csv", dateStr))
-			if err := export.ToCSV(entries, projects, path); err != nil {
+			if err := export.ToCSV(entries, projects, path, durationFormat); err != nil {
 				return statusMsg{text: fmt.Sprintf("CSV error: %v", err), isError: true}
 			}
 		} else {
-			path = filepath.Join(home, fmt.Sprintf("trackr-export-%s.json", dateStr))
-			if err := export.ToJSON(entries, projects, path); err != nil {
+			if err := export.ToJSON(entries, projects, path, durationFormat); err != nil {
 				return statusMsg{text: fmt.Sprintf("JSON error: %v", err), isError: true}
 			}
 		}
@@ -372,3 +579,31 @@ func (a App) doExport(format int) tea.Cmd {
 		return exportDoneMsg{path: path}
 	}
 }
+
+// exportFileTemplate reads export_filename_template, falling back to the
+// default when it is unset or blank.
+func (a App) exportFileTemplate() string {
+	if v, err := a.store.GetSetting("export_filename_template"); err == nil && strings.TrimSpace(v) != "" {
+		return v
+	}
+	return export.DefaultFileNameTemplate
+}
+
+// entriesRange spans the days the entries start on as "FROM_TO", the form
+// report exports use. With no entries both ends are today.
+func entriesRange(entries []store.TimeEntry) string {
+	if len(entries) == 0 {
+		today := time.Now().Format("2006-01-02")
+		return today + "_" + today
+	}
+	first, last := entries[0].StartTime, entries[0].StartTime
+	for _, e := range entries[1:] {
+		if e.StartTime.Before(first) {
+			first = e.StartTime
+		}
+		if e.StartTime.After(last) {
+			last = e.StartTime
+		}
+	}
+	return first.Local().Format("2006-01-02") + "_" + last.Local().Format("2006-01-02")
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyText copies text to the system clipboard. When no clipboard is
+// available (headless or over SSH) it writes a temp file instead and
+// reports the path.
+func copyText(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err == nil {
+			return statusMsg{text: "Copied to clipboard"}
+		}
+
+		f, err := os.CreateTemp("", "trackr-summary-*.txt")
+		if err != nil {
+			return statusMsg{text: fmt.Sprintf("Copy failed: %v", err), isError: true}
+		}
+		defer f.Close()
+		if _, err := f.WriteString(text + "\n"); err != nil {
+			return statusMsg{text: fmt.Sprintf("Copy failed: %v", err), isError: true}
+		}
+		return statusMsg{text: "No clipboard available, summary saved to " + f.Name()}
+	}
+}
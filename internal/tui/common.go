@@ -2,8 +2,14 @@ package tui
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sadopc/trackr/internal/export"
 	"github.com/sadopc/trackr/internal/store"
 )
 
@@ -30,6 +36,13 @@ type timerStoppedMsg struct {
 	entry *store.TimeEntry
 }
 
+// systemIdleMsg carries the OS idle time probed for a running entry.
+type systemIdleMsg struct {
+	entryID int64
+	secs    int
+	ok      bool
+}
+
 type timerPausedMsg struct{}
 type timerResumedMsg struct{}
 
@@ -43,6 +56,11 @@ type taskCreatedMsg struct {
 	task *store.Task
 }
 
+// showProjectTasksMsg asks the app to open a project's task list.
+type showProjectTasksMsg struct {
+	projectID int64
+}
+
 type statusMsg struct {
 	text    string
 	isError bool
@@ -63,20 +81,166 @@ type formCancelMsg struct{}
 
 // --- Helpers ---
 
+// errorStatus wraps err in a command that reports it in the status bar.
+func errorStatus(err error) tea.Cmd {
+	return func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("Error: %v", err), isError: true}
+	}
+}
+
+// formatDuration renders d as HH:MM:SS, showing a negative duration, which
+// only a malformed row could have, as zero.
 func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
 	h := int(d.Hours())
 	m := int(d.Minutes()) % 60
 	s := int(d.Seconds()) % 60
 	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
 }
 
+// durationFormat mirrors the duration_format setting, one of the
+// export.Duration constants. Totals on screen and in exports use it.
+var durationFormat = export.DurationHMS
+
+// formatSeconds renders a tracked total in the duration_format setting.
+// Running clocks keep formatDuration's HH:MM:SS.
 func formatSeconds(secs int64) string {
-	return formatDuration(time.Duration(secs) * time.Second)
+	return export.FormatDuration(secs, durationFormat)
+}
+
+// loadDurationFormat reads the duration_format setting.
+func loadDurationFormat(s *store.Store) {
+	v, _ := s.GetSetting("duration_format")
+	switch v {
+	case export.DurationCompact, export.DurationDecimal:
+		durationFormat = v
+	default:
+		durationFormat = export.DurationHMS
+	}
+}
+
+// tagLabel shows comma-separated tags as "#a #b".
+func tagLabel(tags string) string {
+	names := store.ParseTags(tags)
+	for i, t := range names {
+		names[i] = "#" + t
+	}
+	return strings.Join(names, " ")
+}
+
+// formatShort renders seconds compactly, e.g. "1h30m" or "45m", whatever
+// the duration_format setting, for labels with little room.
+func formatShort(secs int64) string {
+	return export.FormatDuration(secs, export.DurationCompact)
+}
+
+// singleLine collapses runs of whitespace, including newlines, to single
+// spaces.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate cuts s to at most width terminal cells, ending with "…" when
+// anything was cut.
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := lipgloss.Width(string(r))
+		if used+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += rw
+	}
+	return b.String() + "…"
 }
 
-func formatHours(secs int64) string {
-	h := float64(secs) / 3600
-	return fmt.Sprintf("%.1fh", h)
+// bellOut receives the terminal bell; tests replace it.
+var bellOut io.Writer = os.Stdout
+
+// bell rings the terminal bell unless the sound setting is "false". The BEL
+// is written on its own so status text stays plain.
+func bell(s *store.Store) tea.Cmd {
+	return func() tea.Msg {
+		if v, _ := s.GetSetting("sound"); v != "false" {
+			fmt.Fprint(bellOut, "\a")
+		}
+		return nil
+	}
+}
+
+// accessibleMode mirrors the accessible_mode setting. When set, projects
+// are marked with a per-project glyph as well as their color.
+var accessibleMode bool
+
+var projectGlyphs = []string{"●", "▲", "■", "◆", "▼", "✚", "✖", "◉"}
+
+// loadAccessibleMode reads the accessible_mode setting.
+func loadAccessibleMode(s *store.Store) {
+	v, _ := s.GetSetting("accessible_mode")
+	accessibleMode = v == "true"
+}
+
+// projectGlyph returns the project's marker: a dot, or in accessible mode a
+// glyph derived from the project ID so it stays stable across views.
+func projectGlyph(id int64) string {
+	if !accessibleMode || id <= 0 {
+		return "●"
+	}
+	return projectGlyphs[(id-1)%int64(len(projectGlyphs))]
+}
+
+// projectMarker renders projectGlyph in the project's color.
+func projectMarker(id int64, color string) string {
+	return projectColor(color).Render(projectGlyph(id))
+}
+
+// projectColor returns a style in the given project color, falling back to
+// the default for empty or malformed values.
+func projectColor(c string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(store.NormalizeColor(c)))
+}
+
+// parseClock parses a time of day, such as "14:30" or "2:30pm", on the local
+// calendar day of day.
+func parseClock(s string, day time.Time) (time.Time, error) {
+	h, m, ok := parseTimeOfDay(s)
+	if !ok {
+		return time.Time{}, fmt.Errorf("use HH:MM")
+	}
+	day = day.Local()
+	return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, time.Local), nil
+}
+
+// progressBar renders a fixed-width bar filled to ratio (clamped to 0..1).
+func progressBar(width int, ratio float64) string {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * float64(width))
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// scrollWindow returns the [first, last) slice of n rows to show in fit
+// lines, scrolled just far enough to keep cursor in view.
+func scrollWindow(cursor, n, fit int) (int, int) {
+	if n <= fit {
+		return 0, n
+	}
+	first := min(max(cursor-fit+1, 0), n-fit)
+	return first, first + fit
 }
 
 func min(a, b int) int {
@@ -1,12 +1,17 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/sadopc/trackr/internal/store"
 )
@@ -14,24 +19,97 @@ import (
 type dashboardModel struct {
 	store  *store.Store
 	timer  timerModel
+	timers []timerModel // the other running timers in multi-timer mode
 	width  int
 	height int
 
 	todayTotal    int64
 	todaySummary  []store.DailySummary
-	recentEntries []store.TimeEntry
+	recentEntries []store.DetailedEntry
 	projects      []store.Project
+	weekTotal     int64
+	weekEntries   int64
+	weeklyGoal    int64
+	dailyGoal     int64
+
+	// Today's total at the last check against dailyGoal (-1 before the
+	// first), and the day the goal was last reached, so reaching it is
+	// announced once a day
+	lastTodayTotal int64
+	goalDay        string
+
+	// This week's per-project totals, shown in the summary panel instead
+	// of today's when showWeek is set
+	weekSummary []store.DailySummary
+	showWeek    bool
+
+	// Today's completed entries in start order, shown instead of the
+	// recent list when showTimeline is set
+	timeline     []store.DetailedEntry
+	showTimeline bool
+
+	// Recently archived entries, shown instead of the recent list when
+	// showTrash is set
+	trash       []store.DetailedEntry
+	showTrash   bool
+	trashCursor int
 
 	// Project picker state
-	picking       bool
-	pickerCursor  int
+	picking      bool
+	pickerCursor int
+	pickerQuery  string
+	pickerItems  []store.Project // projects in the picker, most recently used first
+	pickerToday  map[int64]int64 // seconds tracked today per project, loaded on open
+	startOffset  time.Duration   // how far back the picked timer starts
+
+	// Inline new-task input, opened from the picker for one project
+	newTaskProject *store.Project
+	taskInput      string
+	taskErr        string
+
+	// Inline quick note for the running entry
+	noting    bool
+	noteInput string
+
+	// Stop-at input: a time of day to stop the running timer at
+	stoppingAt  bool
+	stopAtInput string
+
+	// Recent entry selection and edit form
+	recentCursor int
+	formActive   bool
+	form         *huh.Form
+	editing      store.DetailedEntry
+
+	// Form field pointers (survive value copies)
+	formNotes    *string
+	formTags     *string
+	formSplitAt  *string
+	formBillable *bool
+	formProject  *int64
+	formTask     *int64 // 0 for no task
 }
 
 func newDashboardModel(s *store.Store) dashboardModel {
-	return dashboardModel{
-		store: s,
-		timer: newTimerModel(s),
+	notes, tags, splitAt, billable := "", "", "", true
+	var project, task int64
+	timer := newTimerModel(s)
+	timer.restore()
+	d := dashboardModel{
+		store:          s,
+		timer:          timer,
+		lastTodayTotal: -1,
+		formNotes:      &notes,
+		formTags:       &tags,
+		formSplitAt:    &splitAt,
+		formBillable:   &billable,
+		formProject:    &project,
+		formTask:       &task,
 	}
+	if multiTimer {
+		d.reconcileTimers()
+	}
+	return d
 }
 
 func (d dashboardModel) Init() tea.Cmd {
@@ -52,29 +130,116 @@ func (d dashboardModel) elapsed() time.Duration {
 type dashboardDataMsg struct {
 	todayTotal    int64
 	todaySummary  []store.DailySummary
-	recentEntries []store.TimeEntry
+	recentEntries []store.DetailedEntry
 	projects      []store.Project
+	weekTotal     int64
+	weekEntries   int64
+	weeklyGoal    int64
+	weekSummary   []store.DailySummary
+	dailyGoal     int64
+	timeline      []store.DetailedEntry
+	trash         []store.DetailedEntry
+	err           error // first query failure, if any
 }
 
 func (d dashboardModel) loadData() tea.Cmd {
 	return func() tea.Msg {
-		total, _ := d.store.GetTodayTotal()
-
-		now := time.Now().UTC()
-		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-		dayEnd := dayStart.Add(24 * time.Hour)
-		summary, _ := d.store.GetDailySummary(dayStart, dayEnd)
+		weekStart := d.store.WeekStart()
+		// The panels render whatever did load; the first failure is
+		// reported in the status bar.
+		var firstErr error
+		check := func(err error) {
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		stats, err := d.store.GetDashboardStats(weekStart, d.loadRecentCount())
+		if err != nil {
+			check(err)
+			stats = &store.DashboardStats{}
+		}
+		projects, err := d.store.ListProjectsSorted(false, loadProjectSort(d.store))
+		check(err)
 
-		entries, _ := d.store.ListEntries(store.EntryFilter{Limit: 5})
-		projects, _ := d.store.ListProjects(false)
+		dayStart, _ := d.store.Today()
+		timeline, err := d.store.GetEntriesForDay(dayStart)
+		check(err)
+		trash, err := d.store.ListEntriesDetailed(store.EntryFilter{ArchivedOnly: true, Limit: trashLimit})
+		check(err)
+		weekFrom := store.StartOfWeek(dayStart, weekStart)
+		weekTo := weekFrom.AddDate(0, 0, 7)
+		weekEntries, err := d.store.CountEntries(store.EntryFilter{From: &weekFrom, To: &weekTo})
+		check(err)
+		weekDays, err := d.store.GetDailySummary(weekFrom, weekTo)
+		check(err)
 
 		return dashboardDataMsg{
-			todayTotal:    total,
-			todaySummary:  summary,
-			recentEntries: entries,
+			todayTotal:    stats.TodayTotal,
+			todaySummary:  stats.TodaySummary,
+			recentEntries: stats.RecentEntries,
 			projects:      projects,
+			weekTotal:     stats.WeekTotal,
+			weekEntries:   weekEntries,
+			weekSummary:   foldByProject(weekDays),
+			dailyGoal:     int64(max(d.store.GetSettingInt("daily_goal", 28800), 0)),
+			weeklyGoal:    loadWeeklyGoal(d.store),
+			timeline:      timeline,
+			trash:         trash,
+			err:           firstErr,
+		}
+	}
+}
+
+// foldByProject merges per-day summaries into one total per project,
+// ordered by name.
+func foldByProject(days []store.DailySummary) []store.DailySummary {
+	index := make(map[int64]int)
+	var out []store.DailySummary
+	for _, ds := range days {
+		i, ok := index[ds.ProjectID]
+		if !ok {
+			i = len(out)
+			index[ds.ProjectID] = i
+			out = append(out, store.DailySummary{
+				ProjectID:    ds.ProjectID,
+				ProjectName:  ds.ProjectName,
+				ProjectColor: ds.ProjectColor,
+			})
 		}
+		out[i].TotalSeconds += ds.TotalSeconds
+		out[i].EntryCount += ds.EntryCount
 	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].ProjectName < out[j].ProjectName
+	})
+	return out
+}
+
+// maxRecentCount caps the dashboard_recent_count setting.
+const maxRecentCount = 50
+
+// loadRecentCount reads dashboard_recent_count, defaulting to 5 and clamped
+// to 1–maxRecentCount.
+func (d dashboardModel) loadRecentCount() int {
+	n := d.store.GetSettingInt("dashboard_recent_count", 5)
+	return min(max(n, 1), maxRecentCount)
+}
+
+// loadWeeklyGoal reads the weekly_goal setting in seconds, falling back to five
+// times the daily goal when it has never been saved. Settings shows the same
+// fallback.
+func loadWeeklyGoal(s *store.Store) int64 {
+	if v, err := s.GetSetting("weekly_goal"); err == nil {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return secs
+		}
+	}
+	if v, err := s.GetSetting("daily_goal"); err == nil {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return 5 * secs
+		}
+	}
+	return 5 * 28800
 }
 
 func (d dashboardModel) update(msg tea.Msg) (dashboardModel, tea.Cmd) {
@@ -83,73 +248,648 @@ func (d dashboardModel) update(msg tea.Msg) (dashboardModel, tea.Cmd) {
 		d.todayTotal = msg.todayTotal
 		d.todaySummary = msg.todaySummary
 		d.recentEntries = msg.recentEntries
+		if d.recentCursor >= len(d.recentEntries) {
+			d.recentCursor = max(0, len(d.recentEntries)-1)
+		}
 		d.projects = msg.projects
-		return d, nil
+		d.weekTotal = msg.weekTotal
+		d.weekEntries = msg.weekEntries
+		d.weekSummary = msg.weekSummary
+		d.weeklyGoal = msg.weeklyGoal
+		d.dailyGoal = msg.dailyGoal
+		d.timeline = msg.timeline
+		d.trash = msg.trash
+		if d.trashCursor >= len(d.trash) {
+			d.trashCursor = max(0, len(d.trash)-1)
+		}
+		wasRunning := d.timer.running()
+		if err := d.reconcileTimers(); err != nil {
+			return d, errorStatus(err)
+		}
+		if msg.err != nil {
+			return d, errorStatus(msg.err)
+		}
+		var goalCmd tea.Cmd
+		d, goalCmd = d.checkDailyGoal()
+		if wasRunning && !d.timer.running() {
+			return d, tea.Batch(goalCmd, func() tea.Msg { return statusMsg{text: "Timer was stopped outside trackr"} })
+		}
+		return d, goalCmd
 
 	case tickMsg:
 		d.timer.tick()
+		probe := d.timer.checkSystemIdle()
+		var cmd tea.Cmd
+		d, cmd = d.checkDailyGoal()
+		return d, tea.Batch(probe, cmd)
+
+	case systemIdleMsg:
+		d.timer.applySystemIdle(msg)
 		return d, nil
 
 	case tea.KeyMsg:
 		d.timer.recordActivity()
 
+		if d.formActive && d.form != nil {
+			return d.updateForm(msg)
+		}
+		if d.newTaskProject != nil {
+			return d.updateNewTask(msg)
+		}
+		if d.noting {
+			return d.updateNote(msg)
+		}
+		if d.stoppingAt {
+			return d.updateStopAt(msg)
+		}
 		if d.picking {
 			return d.updatePicker(msg)
 		}
 
 		switch {
-		case key.Matches(msg, keys.Start):
-			if d.timer.running() {
-				return d, nil
+		case key.Matches(msg, keys.Start), key.Matches(msg, keys.Backdate):
+			d.startOffset = 0
+			if key.Matches(msg, keys.Backdate) {
+				d.startOffset = backdateStep
 			}
-			if len(d.projects) == 0 {
-				return d, func() tea.Msg {
-					return statusMsg{text: "No projects yet. Press 2 to go to Projects and create one.", isError: true}
-				}
+			if d.timer.running() && !multiTimer {
+				return d, nil
 			}
 			if len(d.projects) == 1 {
 				return d.startTimer(d.projects[0].ID, d.projects[0].Name, nil, "")
 			}
-			d.picking = true
-			d.pickerCursor = 0
-			return d, nil
+			return d.openPicker()
 
 		case key.Matches(msg, keys.Stop):
 			return d.stopTimer()
 
+		case key.Matches(msg, keys.StopAt):
+			if !d.timer.running() {
+				return d, func() tea.Msg { return statusMsg{text: "No timer running", isError: true} }
+			}
+			d.stoppingAt = true
+			d.stopAtInput = ""
+			return d, nil
+
+		case key.Matches(msg, keys.Resume):
+			if d.timer.running() {
+				return d, nil
+			}
+			return d.resumeLast()
+
+		case key.Matches(msg, keys.Switch):
+			// Hand off: stop the current entry and pick the next one in
+			// a single step.
+			d.startOffset = 0
+			var stopCmd tea.Cmd
+			if d.timer.running() {
+				id := d.timer.entryID
+				d, stopCmd = d.stopTimer()
+				if d.timer.running() && d.timer.entryID == id {
+					return d, stopCmd
+				}
+			}
+			d, cmd := d.openPicker()
+			return d, tea.Batch(stopCmd, cmd)
+
 		case key.Matches(msg, keys.Pause):
 			d.timer.toggle()
 			return d, nil
+
+		case key.Matches(msg, keys.NextTimer):
+			d.nextTimer()
+			return d, nil
+
+		case key.Matches(msg, keys.Mode):
+			d.showWeek = !d.showWeek
+			return d, nil
+
+		case key.Matches(msg, keys.Timeline):
+			d.showTimeline = !d.showTimeline
+			d.showTrash = false
+			return d, nil
+
+		case key.Matches(msg, keys.Note):
+			if !d.timer.running() {
+				return d, func() tea.Msg { return statusMsg{text: "No timer running", isError: true} }
+			}
+			d.noting = true
+			d.noteInput = ""
+			return d, nil
+
+		case key.Matches(msg, keys.GoToTasks):
+			if id := d.currentProjectID(); id != 0 {
+				return d, func() tea.Msg { return showProjectTasksMsg{projectID: id} }
+			}
+			return d, nil
+
+		case key.Matches(msg, keys.Trash):
+			d.showTrash = !d.showTrash
+			d.showTimeline = false
+			return d, nil
+
+		case key.Matches(msg, keys.Up) && d.showTrash:
+			if d.trashCursor > 0 {
+				d.trashCursor--
+			}
+		case key.Matches(msg, keys.Down) && d.showTrash:
+			if d.trashCursor < len(d.trash)-1 {
+				d.trashCursor++
+			}
+		case key.Matches(msg, keys.Enter) && d.showTrash:
+			if len(d.trash) > 0 {
+				return d, d.restoreEntry(d.trash[d.trashCursor])
+			}
+		case key.Matches(msg, keys.Delete) && d.recentFocused():
+			if len(d.recentEntries) > 0 {
+				return d, d.archiveEntry(d.recentEntries[d.recentCursor])
+			}
+
+		case key.Matches(msg, keys.Up) && d.recentFocused():
+			if d.recentCursor > 0 {
+				d.recentCursor--
+			}
+		case key.Matches(msg, keys.Down) && d.recentFocused():
+			if d.recentCursor < len(d.recentEntries)-1 {
+				d.recentCursor++
+			}
+		case key.Matches(msg, keys.Enter) && d.recentFocused():
+			if len(d.recentEntries) > 0 {
+				return d.showEntryForm()
+			}
+		case key.Matches(msg, keys.Copy):
+			return d, copyText(d.summaryText())
+		case key.Matches(msg, keys.Duplicate) && d.recentFocused():
+			if len(d.recentEntries) > 0 {
+				return d, d.duplicateEntry(d.recentEntries[d.recentCursor])
+			}
+		}
+
+	default:
+		// Forms rely on their own internal messages (focus, blink, submit).
+		if d.formActive && d.form != nil {
+			return d.updateForm(msg)
 		}
 	}
 	return d, nil
 }
 
-func (d dashboardModel) updatePicker(msg tea.Msg) (dashboardModel, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, keys.Up):
-			if d.pickerCursor > 0 {
-				d.pickerCursor--
-			}
-		case key.Matches(msg, keys.Down):
-			if d.pickerCursor < len(d.projects)-1 {
-				d.pickerCursor++
-			}
-		case key.Matches(msg, keys.Enter):
-			p := d.projects[d.pickerCursor]
-			d.picking = false
-			return d.startTimer(p.ID, p.Name, nil, "")
-		case key.Matches(msg, keys.Back):
-			d.picking = false
+// currentProjectID is the running timer's project, else the project of the
+// most recent entry, else 0.
+func (d dashboardModel) currentProjectID() int64 {
+	if d.timer.running() {
+		return d.timer.projectID
+	}
+	if len(d.recentEntries) > 0 {
+		return d.recentEntries[0].ProjectID
+	}
+	return 0
+}
+
+// trashLimit is how many archived entries the trash panel lists.
+const trashLimit = 20
+
+// recentFocused reports whether the recent entries list is showing, so its
+// selection keys apply.
+func (d dashboardModel) recentFocused() bool {
+	return !d.showTimeline && !d.showTrash
+}
+
+// archiveEntry moves e to the trash, where it can be restored.
+func (d dashboardModel) archiveEntry(e store.DetailedEntry) tea.Cmd {
+	if e.EndTime == nil {
+		return func() tea.Msg {
+			return statusMsg{text: "Stop the timer before archiving its entry", isError: true}
+		}
+	}
+	if err := d.store.ArchiveEntry(e.ID); err != nil {
+		return errorStatus(err)
+	}
+	return tea.Batch(d.loadData(), func() tea.Msg {
+		return statusMsg{text: "Entry moved to trash (T to review)"}
+	})
+}
+
+// restoreEntry brings e back from the trash.
+func (d dashboardModel) restoreEntry(e store.DetailedEntry) tea.Cmd {
+	if err := d.store.UnarchiveEntry(e.ID); err != nil {
+		return errorStatus(err)
+	}
+	return tea.Batch(d.loadData(), func() tea.Msg { return statusMsg{text: "Entry restored"} })
+}
+
+func (d dashboardModel) showEntryForm() (dashboardModel, tea.Cmd) {
+	e := d.recentEntries[d.recentCursor]
+	d.editing = e
+	*d.formNotes = e.Notes
+	*d.formTags = e.Tags
+	*d.formSplitAt = ""
+	*d.formBillable = e.Billable
+	*d.formProject = e.ProjectID
+	*d.formTask = 0
+	if e.TaskID != nil {
+		*d.formTask = *e.TaskID
+	}
+
+	fields := []huh.Field{
+		huh.NewInput().Title("Notes").Value(d.formNotes),
+		huh.NewInput().Title("Tags (comma-separated)").Value(d.formTags),
+		huh.NewSelect[int64]().Title("Project").Options(d.entryProjectOptions(e)...).Value(d.formProject),
+		huh.NewSelect[int64]().Title("Task").
+			OptionsFunc(d.entryTaskOptions, d.formProject).
+			Value(d.formTask),
+		huh.NewConfirm().Title("Billable").Value(d.formBillable),
+	}
+	if e.EndTime != nil {
+		fields = append(fields, huh.NewInput().
+			Title("Split at ("+timeHint+"; blank to skip)").
+			Value(d.formSplitAt).
+			Validate(func(v string) error {
+				if strings.TrimSpace(v) == "" {
+					return nil
+				}
+				_, err := splitTime(e.TimeEntry, v)
+				return err
+			}))
+	}
+
+	d.form = huh.NewForm(huh.NewGroup(fields...)).WithShowHelp(true).WithShowErrors(true)
+	d.formActive = true
+	return d, d.form.Init()
+}
+
+// entryProjectOptions lists the active projects, plus the entry's own
+// project if it has since been archived.
+func (d dashboardModel) entryProjectOptions(e store.DetailedEntry) []huh.Option[int64] {
+	var opts []huh.Option[int64]
+	found := false
+	for _, p := range d.projects {
+		opts = append(opts, huh.NewOption(p.Name, p.ID))
+		found = found || p.ID == e.ProjectID
+	}
+	if !found {
+		opts = append(opts, huh.NewOption(e.ProjectName, e.ProjectID))
+	}
+	return opts
+}
+
+// entryTaskOptions lists the tasks of the project selected in the entry
+// form. A task that doesn't belong to that project is cleared.
+func (d dashboardModel) entryTaskOptions() []huh.Option[int64] {
+	opts := []huh.Option[int64]{huh.NewOption("(none)", int64(0))}
+	tasks, _ := d.store.ListTasks(*d.formProject, false)
+	keep := false
+	for _, t := range tasks {
+		opts = append(opts, huh.NewOption(t.Name, t.ID))
+		keep = keep || t.ID == *d.formTask
+	}
+	if !keep {
+		*d.formTask = 0
+	}
+	return opts
+}
+
+// sameTask reports whether two optional task IDs refer to the same task.
+func sameTask(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// splitTime resolves a split point within a completed entry. A bare time of
+// day is taken on the entry's day, rolling over to the next day for entries
+// that cross midnight; anything else goes through parseFuzzyTime.
+func splitTime(e store.TimeEntry, v string) (time.Time, error) {
+	at, err := parseClock(v, e.StartTime)
+	if err == nil && !at.After(e.StartTime) {
+		at = at.AddDate(0, 0, 1)
+	}
+	if err != nil {
+		if at, err = parseFuzzyTime(v, time.Now()); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if !at.After(e.StartTime) || !at.Before(*e.EndTime) {
+		return time.Time{}, fmt.Errorf("must be between %s and %s",
+			e.StartTime.Local().Format("15:04"), e.EndTime.Local().Format("15:04"))
+	}
+	return at, nil
+}
+
+func (d dashboardModel) updateForm(msg tea.Msg) (dashboardModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if msg.String() == "esc" {
+			d.formActive = false
+			d.form = nil
+			return d, nil
+		}
+	}
+
+	form, cmd := d.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		d.form = f
+	}
+
+	if d.form.State == huh.StateCompleted {
+		d.formActive = false
+		cmd := d.saveEntryForm()
+		if d.timer.running() && d.timer.entryID == d.editing.ID {
+			d.timer.refreshLabels()
+		}
+		return d, cmd
+	}
+	return d, cmd
+}
+
+// saveEntryForm persists the entry edit form and reloads the dashboard.
+func (d dashboardModel) saveEntryForm() tea.Cmd {
+	e := d.editing
+	if *d.formNotes != e.Notes {
+		if err := d.store.UpdateEntryNotes(e.ID, *d.formNotes); err != nil {
+			return errorStatus(err)
+		}
+	}
+	if *d.formTags != e.Tags {
+		if err := d.store.UpdateEntryTags(e.ID, *d.formTags); err != nil {
+			return errorStatus(err)
+		}
+	}
+	if *d.formBillable != e.Billable {
+		if err := d.store.SetEntryBillable(e.ID, *d.formBillable); err != nil {
+			return errorStatus(err)
+		}
+	}
+	var taskID *int64
+	if *d.formTask != 0 {
+		taskID = d.formTask
+	}
+	if *d.formProject != e.ProjectID || !sameTask(taskID, e.TaskID) {
+		if err := d.store.UpdateEntryProject(e.ID, *d.formProject, taskID); err != nil {
+			return errorStatus(err)
+		}
+	}
+	status := "Entry updated"
+	if v := strings.TrimSpace(*d.formSplitAt); v != "" {
+		at, err := splitTime(e.TimeEntry, v)
+		if err == nil {
+			_, _, err = d.store.SplitEntry(e.ID, at)
+		}
+		if err != nil {
+			return errorStatus(err)
+		}
+		status = "Entry split at " + at.Format("15:04")
+	}
+	return tea.Batch(d.loadData(), func() tea.Msg { return statusMsg{text: status} })
+}
+
+// duplicateEntry logs a copy of e that ends now, for repeated work of a
+// known shape.
+func (d dashboardModel) duplicateEntry(e store.DetailedEntry) tea.Cmd {
+	if e.EndTime == nil {
+		return func() tea.Msg {
+			return statusMsg{text: "Can't duplicate a running entry", isError: true}
+		}
+	}
+	start := time.Now().Add(-time.Duration(e.Duration) * time.Second)
+	dup, err := d.store.DuplicateEntry(e.ID, start)
+	if err != nil {
+		return errorStatus(err)
+	}
+	status := "Duplicated " + e.ProjectName + " entry" + d.overlapWarning(*dup)
+	return tea.Batch(d.loadData(), func() tea.Msg { return statusMsg{text: status} })
+}
+
+// overlapWarning describes the first other entry of e's project that e
+// overlaps, as " · overlaps with a 10:00–11:00 entry", or "" if none does.
+// Overlaps are allowed; this only points them out.
+func (d dashboardModel) overlapWarning(e store.TimeEntry) string {
+	end := time.Now()
+	if e.EndTime != nil {
+		end = *e.EndTime
+	}
+	others, err := d.store.FindOverlappingEntries(e.ProjectID, e.StartTime, end, e.ID)
+	if err != nil || len(others) == 0 {
+		return ""
+	}
+	o := others[0]
+	to := "now"
+	if o.EndTime != nil {
+		to = o.EndTime.Local().Format("15:04")
+	}
+	return fmt.Sprintf(" · overlaps with a %s–%s entry", o.StartTime.Local().Format("15:04"), to)
+}
+
+// pickerMatches returns the projects whose names contain the picker query,
+// case-insensitively.
+func (d dashboardModel) pickerMatches() []store.Project {
+	if d.pickerQuery == "" {
+		return d.pickerItems
+	}
+	q := strings.ToLower(d.pickerQuery)
+	var matches []store.Project
+	for _, p := range d.pickerItems {
+		if strings.Contains(strings.ToLower(p.Name), q) {
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
+
+// updatePicker handles input while the project picker is open. Printable
+// keys edit the filter query, so only arrow keys navigate.
+func (d dashboardModel) updatePicker(msg tea.KeyMsg) (dashboardModel, tea.Cmd) {
+	matches := d.pickerMatches()
+	switch msg.Type {
+	case tea.KeyUp:
+		if d.pickerCursor > 0 {
+			d.pickerCursor--
+		}
+	case tea.KeyDown:
+		if d.pickerCursor < len(matches)-1 {
+			d.pickerCursor++
 		}
+	case tea.KeyEnter:
+		if len(matches) == 0 {
+			return d, nil
+		}
+		p := matches[d.pickerCursor]
+		d.picking = false
+		d.pickerQuery = ""
+		return d.startTimer(p.ID, p.Name, nil, "")
+	case tea.KeyTab:
+		if len(matches) == 0 {
+			return d, nil
+		}
+		p := matches[d.pickerCursor]
+		d.newTaskProject = &p
+		d.taskInput = ""
+		d.taskErr = ""
+	case tea.KeyEsc:
+		d.picking = false
+		d.pickerQuery = ""
+	case tea.KeyBackspace:
+		if r := []rune(d.pickerQuery); len(r) > 0 {
+			d.pickerQuery = string(r[:len(r)-1])
+			d.pickerCursor = 0
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		d.pickerQuery += string(msg.Runes)
+		d.pickerCursor = 0
 	}
 	return d, nil
 }
 
+// updateNewTask handles the inline task name input. Enter creates the task
+// and starts tracking it; a duplicate name keeps the input open.
+func (d dashboardModel) updateNewTask(msg tea.KeyMsg) (dashboardModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		name := strings.TrimSpace(d.taskInput)
+		if name == "" {
+			return d, nil
+		}
+		p := d.newTaskProject
+		task, err := d.store.CreateTask(p.ID, name, "")
+		if errors.Is(err, store.ErrTaskExists) {
+			d.taskErr = fmt.Sprintf("%s already has a task named %q", p.Name, name)
+			return d, nil
+		}
+		if err != nil {
+			return d, errorStatus(err)
+		}
+		d.newTaskProject = nil
+		d.picking = false
+		d.pickerQuery = ""
+		return d.startTimer(p.ID, p.Name, &task.ID, task.Name)
+	case tea.KeyEsc:
+		d.newTaskProject = nil
+	case tea.KeyBackspace:
+		if r := []rune(d.taskInput); len(r) > 0 {
+			d.taskInput = string(r[:len(r)-1])
+		}
+		d.taskErr = ""
+	case tea.KeyRunes, tea.KeySpace:
+		d.taskInput += string(msg.Runes)
+		d.taskErr = ""
+	}
+	return d, nil
+}
+
+// updateNote handles the quick note input. Enter appends the line to the
+// running entry's notes; an empty line just closes the input.
+func (d dashboardModel) updateNote(msg tea.KeyMsg) (dashboardModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		d.noting = false
+		line := singleLine(d.noteInput)
+		if line == "" || !d.timer.running() {
+			return d, nil
+		}
+		if err := d.store.AppendEntryNote(d.timer.entryID, line); err != nil {
+			return d, errorStatus(err)
+		}
+		return d, tea.Batch(
+			func() tea.Msg { return statusMsg{text: "Note added to " + d.timer.projectName} },
+			d.loadData(),
+		)
+	case tea.KeyEsc:
+		d.noting = false
+	case tea.KeyBackspace:
+		if r := []rune(d.noteInput); len(r) > 0 {
+			d.noteInput = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		d.noteInput += string(msg.Runes)
+	}
+	return d, nil
+}
+
+// updateStopAt handles the stop-at input. Enter stops the running timer at
+// the typed time of day: today, or yesterday if that is still to come.
+func (d dashboardModel) updateStopAt(msg tea.KeyMsg) (dashboardModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		now := time.Now()
+		end, err := parseFuzzyTime(d.stopAtInput, now)
+		if err != nil {
+			return d, errorStatus(err)
+		}
+		// A bare time of day still to come today means yesterday's.
+		if _, _, ok := parseTimeOfDay(strings.ToLower(strings.TrimSpace(d.stopAtInput))); ok && end.After(now) {
+			end = end.AddDate(0, 0, -1)
+		}
+		d.stoppingAt = false
+		return d.stopTimerAt(end)
+	case tea.KeyEsc:
+		d.stoppingAt = false
+	case tea.KeyBackspace:
+		if r := []rune(d.stopAtInput); len(r) > 0 {
+			d.stopAtInput = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		d.stopAtInput += string(msg.Runes)
+	}
+	return d, nil
+}
+
+// resumeLast starts a timer on the last used project, continuing the task
+// of its latest entry if that task is still open.
+func (d dashboardModel) resumeLast() (dashboardModel, tea.Cmd) {
+	id := d.currentProjectID()
+	var proj *store.Project
+	for i := range d.projects {
+		if d.projects[i].ID == id {
+			proj = &d.projects[i]
+		}
+	}
+	if proj == nil {
+		return d, func() tea.Msg { return statusMsg{text: "Nothing to resume", isError: true} }
+	}
+	last, err := d.store.GetLastEntryForProject(proj.ID)
+	if err != nil {
+		return d, errorStatus(err)
+	}
+	if last != nil && last.TaskID != nil {
+		if task, err := d.store.GetTask(*last.TaskID); err == nil && !task.Archived {
+			return d.startTimer(proj.ID, proj.Name, &task.ID, task.Name)
+		}
+	}
+	return d.startTimer(proj.ID, proj.Name, nil, "")
+}
+
+// backdateStep is how far back the Backdate key starts the timer.
+const backdateStep = 5 * time.Minute
+
+// openPicker shows the project picker, which starts a timer on selection.
+func (d dashboardModel) openPicker() (dashboardModel, tea.Cmd) {
+	if len(d.projects) == 0 {
+		return d, func() tea.Msg {
+			return statusMsg{text: "No projects yet. Press 2 to go to Projects and create one.", isError: true}
+		}
+	}
+	d.picking = true
+	d.pickerCursor = 0
+	d.pickerQuery = ""
+	d.pickerItems = d.projects
+	if recent, err := d.store.RecentProjects(0); err == nil {
+		d.pickerItems = recent
+	}
+	// A failed lookup only loses the totals; the picker still works.
+	d.pickerToday, _ = d.store.GetProjectTodayTotals()
+	return d, nil
+}
+
 func (d dashboardModel) startTimer(projectID int64, projectName string, taskID *int64, taskName string) (dashboardModel, tea.Cmd) {
-	if err := d.timer.start(projectID, projectName, taskID, taskName); err != nil {
+	start := time.Now().Add(-d.startOffset)
+	d.startOffset = 0
+	timer, others := d.timer, d.timers
+	if multiTimer && d.timer.running() {
+		d.timers = append(slices.Clone(d.timers), d.timer)
+		d.timer = newTimerModel(d.store)
+	}
+	if err := d.timer.startAt(projectID, projectName, taskID, taskName, start); err != nil {
+		d.timer, d.timers = timer, others
 		return d, func() tea.Msg {
 			return statusMsg{text: fmt.Sprintf("Error: %v", err), isError: true}
 		}
@@ -158,12 +898,18 @@ func (d dashboardModel) startTimer(projectID int64, projectName string, taskID *
 }
 
 func (d dashboardModel) stopTimer() (dashboardModel, tea.Cmd) {
-	entry, err := d.timer.stop()
+	return d.stopTimerAt(time.Now())
+}
+
+// stopTimerAt stops the running timer as of end.
+func (d dashboardModel) stopTimerAt(end time.Time) (dashboardModel, tea.Cmd) {
+	entry, err := d.timer.stopAt(end)
 	if err != nil {
 		return d, func() tea.Msg {
 			return statusMsg{text: fmt.Sprintf("Error: %v", err), isError: true}
 		}
 	}
+	d.promoteTimer()
 	return d, tea.Batch(
 		d.loadData(),
 		func() tea.Msg { return timerStoppedMsg{entry: entry} },
@@ -175,25 +921,53 @@ func (d dashboardModel) view() string {
 		return "Terminal too small"
 	}
 
+	if d.width < miniWidth {
+		return d.renderMini()
+	}
+
 	contentWidth := d.width - 4
 
-	// Timer panel
+	// Timer panel, with any other running timers stacked below
 	timerPanel := d.renderTimerPanel(contentWidth)
+	if others := d.renderOtherTimers(contentWidth); others != "" {
+		timerPanel = lipgloss.JoinVertical(lipgloss.Left, timerPanel, others)
+	}
 
 	// Today summary panel
 	summaryPanel := d.renderSummaryPanel(contentWidth)
 
-	// Recent entries or project picker
-	var bottomPanel string
-	if d.picking {
-		bottomPanel = d.renderProjectPicker(contentWidth)
-	} else {
-		bottomPanel = d.renderRecentPanel(contentWidth)
+	// Recent entries, or whatever replaces them
+	bottomPanel, ok := d.renderOverlayPanel(contentWidth)
+	if !ok {
+		avail := d.height - lipgloss.Height(timerPanel) - lipgloss.Height(summaryPanel)
+		bottomPanel = d.renderRecentPanel(contentWidth, avail)
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, timerPanel, summaryPanel, bottomPanel)
 }
 
+// renderOverlayPanel renders the entry form, project picker or other panel
+// that takes the place of the recent entries, if one is open.
+func (d dashboardModel) renderOverlayPanel(w int) (string, bool) {
+	switch {
+	case d.formActive && d.form != nil:
+		return d.renderEntryForm(w), true
+	case d.newTaskProject != nil:
+		return d.renderNewTask(w), true
+	case d.noting:
+		return d.renderNote(w), true
+	case d.stoppingAt:
+		return d.renderStopAt(w), true
+	case d.picking:
+		return d.renderProjectPicker(w), true
+	case d.showTimeline:
+		return d.renderTimelinePanel(w), true
+	case d.showTrash:
+		return d.renderTrashPanel(w), true
+	}
+	return "", false
+}
+
 func (d dashboardModel) renderTimerPanel(w int) string {
 	var timeDisplay string
 	var indicator string
@@ -205,7 +979,7 @@ func (d dashboardModel) renderTimerPanel(w int) string {
 		if d.timer.paused() {
 			timeDisplay = timerPausedStyle.Width(w - 6).Render(timeStr)
 			if d.timer.isIdle {
-				indicator = warningStyle.Render("⏸  IDLE")
+				indicator = warningStyle.Render("⏸  IDLE") + mutedStyle.Render("  any key resumes")
 			} else {
 				indicator = warningStyle.Render("⏸  PAUSED")
 			}
@@ -240,35 +1014,197 @@ func (d dashboardModel) renderTimerPanel(w int) string {
 }
 
 func (d dashboardModel) renderSummaryPanel(w int) string {
-	title := titleStyle.Render("Today")
-	total := highlightStyle.Render(formatSeconds(d.todayTotal))
-	header := fmt.Sprintf("%s  %s", title, total)
+	period, summary, total := d.summaryPeriod()
+	title := titleStyle.Render(period)
+	header := fmt.Sprintf("%s  %s", title, highlightStyle.Render(formatSeconds(total)))
 
-	if len(d.todaySummary) == 0 {
-		content := lipgloss.JoinVertical(lipgloss.Left,
-			header,
-			mutedStyle.Render("No entries today"),
-		)
-		return panelStyle.Width(w).Render(content)
+	weekLine := d.renderWeeklyProgress()
+	statsLine := mutedStyle.Render(d.weekStatsLine())
+	goalLine := ""
+	if !d.showWeek {
+		goalLine = d.renderDailyProgress()
+	}
+
+	if len(summary) == 0 {
+		rows := []string{header}
+		if goalLine != "" {
+			rows = append(rows, goalLine)
+		}
+		if weekLine != "" {
+			rows = append(rows, weekLine)
+		}
+		rows = append(rows, statsLine)
+		rows = append(rows, mutedStyle.Render("No entries "+strings.ToLower(period)))
+		return panelStyle.Width(w).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
 	}
 
 	var rows []string
 	rows = append(rows, header)
-	for _, s := range d.todaySummary {
-		colorDot := lipgloss.NewStyle().Foreground(lipgloss.Color(s.ProjectColor)).Render("●")
-		row := fmt.Sprintf("  %s %-20s %s  (%d entries)",
-			colorDot,
-			s.ProjectName,
-			formatSeconds(s.TotalSeconds),
-			s.EntryCount,
-		)
+	if goalLine != "" {
+		rows = append(rows, goalLine)
+	}
+	if weekLine != "" {
+		rows = append(rows, weekLine)
+	}
+	rows = append(rows, statsLine)
+	for _, s := range summary {
+		row := todaySummaryRow(projectMarker(s.ProjectID, s.ProjectColor), s)
+		// Project goals are daily, so the week view has no bars.
+		if goal := d.projectGoal(s.ProjectID); goal > 0 && !d.showWeek {
+			row += "  " + goalBar(s.TotalSeconds, goal)
+		}
 		rows = append(rows, row)
 	}
 
 	return panelStyle.Width(w).Render(strings.Join(rows, "\n"))
 }
 
-func (d dashboardModel) renderRecentPanel(w int) string {
+// summaryPeriod returns the summary panel's title, per-project rows and
+// total: today's, or this week's when showWeek is set.
+func (d dashboardModel) summaryPeriod() (string, []store.DailySummary, int64) {
+	if !d.showWeek {
+		return "Today", d.todaySummary, d.todayTotal
+	}
+	var total int64
+	for _, s := range d.weekSummary {
+		total += s.TotalSeconds
+	}
+	return "This week", d.weekSummary, total
+}
+
+func todaySummaryRow(dot string, s store.DailySummary) string {
+	return fmt.Sprintf("  %s %-20s %s  (%d entries)",
+		dot,
+		s.ProjectName,
+		formatSeconds(s.TotalSeconds),
+		s.EntryCount,
+	)
+}
+
+// projectGoal returns the daily goal of an active project, or 0.
+func (d dashboardModel) projectGoal(id int64) int64 {
+	for _, p := range d.projects {
+		if p.ID == id {
+			return p.DailyGoal
+		}
+	}
+	return 0
+}
+
+// goalBar is a short progress bar of secs against a project's daily goal.
+func goalBar(secs, goal int64) string {
+	bar := highlightStyle.Render(progressBar(8, float64(secs)/float64(goal)))
+	if secs >= goal {
+		bar = successStyle.Render(progressBar(8, 1))
+	}
+	return bar + mutedStyle.Render(" "+formatShort(goal))
+}
+
+// summaryText is the summary panel as plain text, for pasting elsewhere.
+func (d dashboardModel) summaryText() string {
+	period, summary, total := d.summaryPeriod()
+	rows := []string{fmt.Sprintf("%s  %s", period, formatSeconds(total))}
+	if len(summary) == 0 {
+		rows = append(rows, "No entries "+strings.ToLower(period))
+	}
+	for _, s := range summary {
+		rows = append(rows, todaySummaryRow(projectGlyph(s.ProjectID), s))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// liveTodayTotal is today's tracked time including the running timers,
+// counting only the part of each since midnight.
+func (d dashboardModel) liveTodayTotal() int64 {
+	dayStart, _ := d.store.Today()
+	since := time.Since(dayStart)
+	total := d.todayTotal
+	for _, t := range append([]timerModel{d.timer}, d.timers...) {
+		run := t.currentElapsed()
+		if run > since {
+			run = since
+		}
+		total += int64(run.Seconds())
+	}
+	return total
+}
+
+// checkDailyGoal announces, once a day, today's total crossing the daily
+// goal while trackr is open. A goal already met at launch is not announced.
+func (d dashboardModel) checkDailyGoal() (dashboardModel, tea.Cmd) {
+	total := d.liveTodayTotal()
+	prev := d.lastTodayTotal
+	d.lastTodayTotal = total
+	today := time.Now().Format("2006-01-02")
+	if d.dailyGoal <= 0 || total < d.dailyGoal || d.goalDay == today {
+		return d, nil
+	}
+	d.goalDay = today
+	if prev < 0 || prev >= d.dailyGoal {
+		return d, nil
+	}
+	return d, tea.Batch(
+		func() tea.Msg { return statusMsg{text: "🎉 Daily goal reached!"} },
+		bell(d.store),
+	)
+}
+
+// renderDailyProgress shows today's running total against daily_goal, in
+// green once it is met, or nothing when the goal is disabled.
+func (d dashboardModel) renderDailyProgress() string {
+	if d.dailyGoal <= 0 {
+		return ""
+	}
+	total := d.liveTodayTotal()
+	bar := highlightStyle.Render(progressBar(20, float64(total)/float64(d.dailyGoal)))
+	if total >= d.dailyGoal {
+		bar = successStyle.Render(progressBar(20, 1))
+	}
+	return fmt.Sprintf("%s %s %s",
+		mutedStyle.Render("Goal"),
+		bar,
+		mutedStyle.Render(fmt.Sprintf("%s / %s", formatSeconds(total), formatSeconds(d.dailyGoal))),
+	)
+}
+
+// renderWeeklyProgress shows the week's total against weekly_goal, or
+// nothing when the goal is disabled.
+func (d dashboardModel) renderWeeklyProgress() string {
+	if d.weeklyGoal <= 0 {
+		return ""
+	}
+	ratio := float64(d.weekTotal) / float64(d.weeklyGoal)
+	bar := progressBar(20, ratio)
+	if d.weekTotal >= d.weeklyGoal {
+		bar = successStyle.Render(bar)
+	} else {
+		bar = highlightStyle.Render(bar)
+	}
+	return fmt.Sprintf("%s %s %s",
+		mutedStyle.Render("Week"),
+		bar,
+		mutedStyle.Render(fmt.Sprintf("%s / %s", formatSeconds(d.weekTotal), formatSeconds(d.weeklyGoal))),
+	)
+}
+
+// weekStatsLine counts this week's entries and hours, and estimates the
+// time still untracked against weekly_goal when one is set and unmet.
+func (d dashboardModel) weekStatsLine() string {
+	noun := "entries"
+	if d.weekEntries == 1 {
+		noun = "entry"
+	}
+	line := fmt.Sprintf("This week: %d %s · %s tracked", d.weekEntries, noun, formatSeconds(d.weekTotal))
+	if d.weeklyGoal > d.weekTotal {
+		line += fmt.Sprintf(" · ~%s untracked vs goal", formatSeconds(d.weeklyGoal-d.weekTotal))
+	}
+	return line
+}
+
+// renderRecentPanel lists recent entries in a panel at most h lines tall,
+// scrolling to keep the selection visible. A non-positive h means the
+// height is unknown and every entry is shown.
+func (d dashboardModel) renderRecentPanel(w, h int) string {
 	title := titleStyle.Render("Recent Entries")
 	if len(d.recentEntries) == 0 {
 		content := lipgloss.JoinVertical(lipgloss.Left,
@@ -278,14 +1214,17 @@ func (d dashboardModel) renderRecentPanel(w int) string {
 		return panelStyle.Width(w).Render(content)
 	}
 
+	// Border, padding and title take five lines.
+	first, last := 0, len(d.recentEntries)
+	if d.height > 0 {
+		first, last = scrollWindow(d.recentCursor, last, max(h-5, 1))
+	}
+
 	var rows []string
 	rows = append(rows, title)
-	for _, e := range d.recentEntries {
-		project, _ := d.store.GetProject(e.ProjectID)
-		pName := "?"
-		if project != nil {
-			pName = project.Name
-		}
+	for i := first; i < last; i++ {
+		e := d.recentEntries[i]
+		pName := e.ProjectName
 		dur := formatSeconds(e.Duration)
 		startStr := e.StartTime.Local().Format("15:04")
 		status := "✓"
@@ -293,30 +1232,202 @@ func (d dashboardModel) renderRecentPanel(w int) string {
 			status = "●"
 			dur = "running"
 		}
-		row := fmt.Sprintf("  %s %s  %-16s %s", status, startStr, pName, dur)
+		cursor := "  "
+		style := normalItemStyle
+		if i == d.recentCursor {
+			cursor = "> "
+			style = selectedItemStyle
+		}
+		text := fmt.Sprintf("%s%s%s %s  %-16s %s", cursor, status, noteMarker(e.Notes), startStr, pName, dur)
+		row := style.Render(text)
+		// Panel padding takes four columns; keep two before the tags
+		// and notes.
+		if notes := strings.TrimSpace(tagLabel(e.Tags) + " " + singleLine(e.Notes)); notes != "" {
+			if room := w - 4 - lipgloss.Width(text) - 2; room >= 4 {
+				row += "  " + mutedStyle.Render(truncate(notes, room))
+			}
+		}
 		rows = append(rows, row)
 	}
 
 	return panelStyle.Width(w).Render(strings.Join(rows, "\n"))
 }
 
+// noteMarker flags an entry with notes. It is always one cell wide so the
+// columns after it line up.
+func noteMarker(notes string) string {
+	if strings.TrimSpace(notes) == "" {
+		return " "
+	}
+	return "✎"
+}
+
+// renderTrashPanel lists archived entries, newest first, for restoring.
+func (d dashboardModel) renderTrashPanel(w int) string {
+	title := titleStyle.Render("Trash")
+	if len(d.trash) == 0 {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			title,
+			mutedStyle.Render("Trash is empty"),
+		)
+		return panelStyle.Width(w).Render(content)
+	}
+
+	rows := []string{title}
+	for i, e := range d.trash {
+		cursor := "  "
+		style := normalItemStyle
+		if i == d.trashCursor {
+			cursor = "> "
+			style = selectedItemStyle
+		}
+		rows = append(rows, style.Render(fmt.Sprintf("%s%s  %-16s %s",
+			cursor, e.StartTime.Local().Format("Jan 02 15:04"), e.ProjectName, formatSeconds(e.Duration))))
+	}
+	rows = append(rows, mutedStyle.Render("  enter: restore  T: close"))
+
+	return panelStyle.Width(w).Render(strings.Join(rows, "\n"))
+}
+
+// renderTimelinePanel lists today's entries as a schedule, with untracked
+// gaps muted and overlaps flagged.
+func (d dashboardModel) renderTimelinePanel(w int) string {
+	title := titleStyle.Render("Today's Timeline")
+	if len(d.timeline) == 0 {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			title,
+			mutedStyle.Render("No completed entries today"),
+		)
+		return panelStyle.Width(w).Render(content)
+	}
+
+	rows := []string{title}
+	var prevEnd time.Time
+	for i, e := range d.timeline {
+		start, end := e.StartTime.Local(), e.EndTime.Local()
+		if i > 0 {
+			switch {
+			case start.Before(prevEnd):
+				rows = append(rows, warningStyle.Render(fmt.Sprintf("  ⚠ overlaps previous entry by %s",
+					formatShort(int64(prevEnd.Sub(start).Seconds())))))
+			case start.Sub(prevEnd) >= time.Minute:
+				rows = append(rows, mutedStyle.Render(fmt.Sprintf("  %s–%s  untracked (%s)",
+					prevEnd.Format("15:04"), start.Format("15:04"), formatShort(int64(start.Sub(prevEnd).Seconds())))))
+			}
+		}
+		name := e.ProjectName
+		if e.TaskName != "" {
+			name += " / " + e.TaskName
+		}
+		block := projectColor(e.ProjectColor).Render("█")
+		if accessibleMode {
+			block = projectMarker(e.ProjectID, e.ProjectColor)
+		}
+		rows = append(rows, fmt.Sprintf("  %s%s %s–%s  %s (%s)",
+			block, noteMarker(e.Notes),
+			start.Format("15:04"), end.Format("15:04"),
+			name, formatShort(e.Duration)))
+		if end.After(prevEnd) {
+			prevEnd = end
+		}
+	}
+
+	return panelStyle.Width(w).Render(strings.Join(rows, "\n"))
+}
+
+func (d dashboardModel) renderEntryForm(w int) string {
+	e := d.editing
+	end := "running"
+	if e.EndTime != nil {
+		end = e.EndTime.Local().Format("15:04")
+	}
+	title := titleStyle.Render("Edit Entry")
+	info := mutedStyle.Render(fmt.Sprintf("%s  %s–%s  %s",
+		e.ProjectName, e.StartTime.Local().Format("Jan 02 15:04"), end, formatSeconds(e.Duration)))
+	content := lipgloss.JoinVertical(lipgloss.Left, title, info, "", d.form.View())
+	return activePanelStyle.Width(w).Render(content)
+}
+
 func (d dashboardModel) renderProjectPicker(w int) string {
 	title := titleStyle.Render("Select Project")
 
 	var rows []string
 	rows = append(rows, title)
-	for i, p := range d.projects {
-		colorDot := lipgloss.NewStyle().Foreground(lipgloss.Color(p.Color)).Render("●")
+	if d.pickerQuery != "" {
+		rows = append(rows, highlightStyle.Render("  / "+d.pickerQuery))
+	} else {
+		rows = append(rows, mutedStyle.Render("  type to filter"))
+	}
+
+	matches := d.pickerMatches()
+	if len(matches) == 0 {
+		rows = append(rows, mutedStyle.Render("  No matching projects"))
+	}
+	for i, p := range matches {
+		colorDot := projectMarker(p.ID, p.Color)
 		cursor := "  "
 		style := normalItemStyle
 		if i == d.pickerCursor {
 			cursor = "> "
 			style = selectedItemStyle
 		}
-		rows = append(rows, style.Render(fmt.Sprintf("%s%s %s", cursor, colorDot, p.Name)))
+		pin := " "
+		if p.Pinned {
+			pin = warningStyle.Render("★")
+		}
+		today := ""
+		if secs := d.pickerToday[p.ID]; secs > 0 {
+			today = mutedStyle.Render(fmt.Sprintf("  (%s today)", formatShort(secs)))
+		}
+		rows = append(rows, style.Render(cursor)+colorDot+pin+style.Render(p.Name)+today)
+	}
+	rows = append(rows, "")
+	rows = append(rows, mutedStyle.Render("  ↑/↓: move  enter: select  tab: + new task…  esc: cancel"))
+
+	return activePanelStyle.Width(w).Render(strings.Join(rows, "\n"))
+}
+
+func (d dashboardModel) renderNote(w int) string {
+	title := titleStyle.Render("Quick Note")
+	rows := []string{
+		title,
+		mutedStyle.Render("  " + d.timer.projectName),
+		"",
+		highlightStyle.Render("  > " + d.noteInput + "█"),
+		"",
+		mutedStyle.Render("  enter: add to notes  esc: cancel"),
+	}
+	return activePanelStyle.Width(w).Render(strings.Join(rows, "\n"))
+}
+
+func (d dashboardModel) renderStopAt(w int) string {
+	title := titleStyle.Render("Stop At")
+	rows := []string{
+		title,
+		mutedStyle.Render(fmt.Sprintf("  %s · started %s", d.timer.projectName,
+			startedLabel(d.timer.startTime, time.Now()))),
+		"",
+		highlightStyle.Render("  > " + d.stopAtInput + "█"),
+		"",
+		mutedStyle.Render("  enter: stop at this time (e.g. 14:30, 20m ago, now-20m)  esc: cancel"),
+	}
+	return activePanelStyle.Width(w).Render(strings.Join(rows, "\n"))
+}
+
+func (d dashboardModel) renderNewTask(w int) string {
+	p := d.newTaskProject
+	title := titleStyle.Render("New Task")
+	rows := []string{
+		title,
+		projectMarker(p.ID, p.Color) + " " + mutedStyle.Render(p.Name),
+		"",
+		highlightStyle.Render("  > " + d.taskInput + "█"),
+	}
+	if d.taskErr != "" {
+		rows = append(rows, errorStyle.Render("  "+d.taskErr))
 	}
 	rows = append(rows, "")
-	rows = append(rows, mutedStyle.Render("  enter: select  esc: cancel"))
+	rows = append(rows, mutedStyle.Render("  enter: create and start  esc: back"))
 
 	return activePanelStyle.Width(w).Render(strings.Join(rows, "\n"))
 }
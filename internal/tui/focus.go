@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bigGlyphs draws the characters of a formatted duration five rows tall.
+var bigGlyphs = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {"  █", "  █", "  █", "  █", "  █"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// bigText renders s with bigGlyphs, or returns "" if s has a character
+// without a glyph.
+func bigText(s string) string {
+	var rows [5][]string
+	for _, r := range s {
+		g, ok := bigGlyphs[r]
+		if !ok {
+			return ""
+		}
+		for i := range rows {
+			rows[i] = append(rows[i], g[i])
+		}
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderFocus is the distraction-free timer: a large clock and the project,
+// centered in w×h with nothing else on screen.
+func (d dashboardModel) renderFocus(w, h int) string {
+	style := timerStyle
+	indicator := mutedStyle.Render("■  STOPPED")
+	timeStr := "00:00:00"
+	project := ""
+	if d.timer.running() {
+		timeStr = formatDuration(d.timer.currentElapsed())
+		style = timerRunningStyle
+		indicator = successStyle.Render("●  RUNNING")
+		if d.timer.paused() {
+			style = timerPausedStyle
+			indicator = warningStyle.Render("⏸  PAUSED")
+		}
+		project = highlightStyle.Render(d.timer.projectName)
+		if d.timer.taskName != "" {
+			project += mutedStyle.Render(" / " + d.timer.taskName)
+		}
+	}
+
+	clock := bigText(timeStr)
+	if clock == "" || lipgloss.Width(clock) > w {
+		clock = timeStr
+	}
+	hint := mutedStyle.Render("space: pause/resume  x: stop  esc: leave focus")
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		style.Render(clock), "", indicator, project, "", hint,
+	)
+	return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, content)
+}
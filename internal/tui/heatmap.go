@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sadopc/trackr/internal/store"
+)
+
+// maxHeatmapWeeks is a year of columns; narrower terminals show fewer.
+const maxHeatmapWeeks = 53
+
+// heatmapShades are the cells for no time and increasing intensity.
+var heatmapShades = []string{"·", "░", "▒", "▓", "█"}
+
+// heatmapWeeks is how many week columns fit: a 4-column weekday label,
+// then two columns per week.
+func (r reportsModel) heatmapWeeks() int {
+	return min(max((r.width-12)/2, 4), maxHeatmapWeeks)
+}
+
+// heatLevel buckets secs into an index of heatmapShades. Days are measured
+// against the daily goal when there is one, otherwise against the busiest
+// day shown.
+func heatLevel(secs, goal, busiest int64) int {
+	if secs <= 0 {
+		return 0
+	}
+	scale := goal
+	if scale <= 0 {
+		scale = busiest
+	}
+	ratio := float64(secs) / float64(scale)
+	switch {
+	case ratio >= 1:
+		return 4
+	case ratio >= 0.5:
+		return 3
+	case ratio >= 0.25:
+		return 2
+	}
+	return 1
+}
+
+// renderHeatmap draws one column per week and one row per weekday, each
+// cell shaded by the time tracked that day. Days after today are blank.
+func (r reportsModel) renderHeatmap() string {
+	from, to := r.dateRange()
+	totals := store.TotalsByDate(r.summaries)
+	var busiest int64
+	for _, secs := range totals {
+		if secs > busiest {
+			busiest = secs
+		}
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// Month labels sit above the first week that starts in a new month.
+	months := []rune(strings.Repeat(" ", 4+2*r.heatmapWeeks()))
+	lastMonth := time.Month(0)
+	for col, week := 0, from; week.Before(to); col, week = col+1, week.AddDate(0, 0, 7) {
+		if week.Month() != lastMonth && 4+2*col+3 <= len(months) {
+			copy(months[4+2*col:], []rune(week.Format("Jan")))
+			lastMonth = week.Month()
+		}
+	}
+	rows := []string{mutedStyle.Render(strings.TrimRight(string(months), " "))}
+
+	for day := 0; day < 7; day++ {
+		first := from.AddDate(0, 0, day)
+		var b strings.Builder
+		b.WriteString(mutedStyle.Render(first.Format("Mon")) + " ")
+		for d := first; d.Before(to); d = d.AddDate(0, 0, 7) {
+			if d.After(today) {
+				b.WriteString("  ")
+				continue
+			}
+			secs := totals[d.Format("2006-01-02")]
+			level := heatLevel(secs, r.dailyGoal, busiest)
+			cell := heatmapShades[level]
+			switch {
+			case level == 0:
+				cell = mutedStyle.Render(cell)
+			case r.goalMet(secs):
+				cell = successStyle.Render(cell)
+			default:
+				cell = highlightStyle.Render(cell)
+			}
+			b.WriteString(cell + " ")
+		}
+		rows = append(rows, b.String())
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderHeatmapLegend explains the shades and sums up the range.
+func (r reportsModel) renderHeatmapLegend() string {
+	totals := store.TotalsByDate(r.summaries)
+	var total int64
+	active := 0
+	for _, secs := range totals {
+		total += secs
+		if secs > 0 {
+			active++
+		}
+	}
+	scale := "busiest day"
+	if r.dailyGoal > 0 {
+		scale = "goal " + formatShort(r.dailyGoal)
+	}
+	return mutedStyle.Render(fmt.Sprintf("  less %s more (vs %s)  ·  active days: %d  ·  %s total",
+		strings.Join(heatmapShades, ""), scale, active, formatShort(total)))
+}
@@ -4,12 +4,37 @@ import "github.com/charmbracelet/bubbles/key"
 
 type keyMap struct {
 	Start      key.Binding
+	Backdate   key.Binding
 	Stop       key.Binding
+	StopAt     key.Binding
+	Resume     key.Binding
+	Switch     key.Binding
 	Pause      key.Binding
+	NextTimer  key.Binding
 	New        key.Binding
 	Delete     key.Binding
+	ArchiveAll key.Binding
+	Pin        key.Binding
+	Sort       key.Binding
+	Duplicate  key.Binding
+	Copy       key.Binding
+	Note       key.Binding
+	Timeline   key.Binding
+	Trash      key.Binding
+	Filter     key.Binding
+	HideEmpty  key.Binding
+	GoToTasks  key.Binding
+	Categories key.Binding
+	Details    key.Binding
+	Prune      key.Binding
+	Compact    key.Binding
+	SaveConfig key.Binding
+	LoadConfig key.Binding
+	Snapshot   key.Binding
 	Pomodoro   key.Binding
 	Export     key.Binding
+	Mode       key.Binding
+	Focus      key.Binding
 	Tab1       key.Binding
 	Tab2       key.Binding
 	Tab3       key.Binding
@@ -31,14 +56,34 @@ var keys = keyMap{
 		key.WithKeys("s"),
 		key.WithHelp("s", "start"),
 	),
+	Backdate: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "start 5 min ago"),
+	),
 	Stop: key.NewBinding(
 		key.WithKeys("x"),
 		key.WithHelp("x", "stop"),
 	),
+	StopAt: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "stop at…"),
+	),
+	Resume: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "resume last"),
+	),
+	Switch: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "stop & switch"),
+	),
 	Pause: key.NewBinding(
 		key.WithKeys(" "),
 		key.WithHelp("space", "pause/resume"),
 	),
+	NextTimer: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "switch timer"),
+	),
 	New: key.NewBinding(
 		key.WithKeys("n"),
 		key.WithHelp("n", "new"),
@@ -47,6 +92,78 @@ var keys = keyMap{
 		key.WithKeys("d"),
 		key.WithHelp("d", "archive"),
 	),
+	ArchiveAll: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "archive tasks"),
+	),
+	Pin: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "pin"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "sort projects"),
+	),
+	Duplicate: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "duplicate entry"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy summary"),
+	),
+	Note: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "quick note"),
+	),
+	Timeline: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "timeline"),
+	),
+	Trash: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "trash"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter project"),
+	),
+	HideEmpty: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "hide empty days"),
+	),
+	GoToTasks: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "project tasks"),
+	),
+	Categories: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "categories"),
+	),
+	Details: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "project details"),
+	),
+	Prune: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "delete old entries"),
+	),
+	Compact: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "compact database"),
+	),
+	SaveConfig: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "export settings"),
+	),
+	LoadConfig: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "import settings"),
+	),
+	Snapshot: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "export database"),
+	),
 	Pomodoro: key.NewBinding(
 		key.WithKeys("p"),
 		key.WithHelp("p", "pomodoro"),
@@ -55,6 +172,14 @@ var keys = keyMap{
 		key.WithKeys("e"),
 		key.WithHelp("e", "export"),
 	),
+	Mode: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "switch mode"),
+	),
+	Focus: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "focus mode"),
+	),
 	Tab1: key.NewBinding(
 		key.WithKeys("1"),
 		key.WithHelp("1", "dashboard"),
@@ -117,11 +242,70 @@ func (k keyMap) ShortHelp() []key.Binding {
 	return []key.Binding{k.Start, k.Stop, k.Pause, k.New, k.Help, k.Quit}
 }
 
+// FullHelp lists the bindings that work in every view.
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Start, k.Stop, k.Pause},
-		{k.New, k.Delete, k.Export},
 		{k.Tab1, k.Tab2, k.Tab3, k.Tab4, k.Tab5},
-		{k.Up, k.Down, k.Enter, k.Back, k.Quit},
+		{k.Tab, k.Focus, k.Export, k.Help, k.Quit},
+	}
+}
+
+// viewHelp is the help for one view: its own bindings first, then the
+// global ones.
+type viewHelp struct {
+	view viewState
+}
+
+func (h viewHelp) ShortHelp() []key.Binding {
+	return keys.ShortHelp()
+}
+
+func (h viewHelp) FullHelp() [][]key.Binding {
+	var groups [][]key.Binding
+	own := viewKeys(h.view)
+	for len(own) > 0 {
+		n := min(len(own), 5)
+		groups = append(groups, own[:n])
+		own = own[n:]
 	}
+	return append(groups, keys.FullHelp()...)
+}
+
+// viewKeys returns the bindings a view handles, described as they act there.
+func viewKeys(v viewState) []key.Binding {
+	k := keys
+	switch v {
+	case viewDashboard:
+		return []key.Binding{
+			k.Start, k.Backdate, k.Stop, k.StopAt, k.Resume, k.Switch, k.Pause, k.NextTimer,
+			relabel(k.Enter, "edit entry"), k.Delete, k.Duplicate, k.Timeline, k.Trash,
+			k.Note, k.GoToTasks, k.Copy, relabel(k.Mode, "today/week"), k.Up, k.Down,
+		}
+	case viewProjects:
+		return []key.Binding{
+			k.New, relabel(k.Enter, "tasks"), k.Delete, k.Pin, k.Sort, relabel(k.Categories, "next category"), k.Details, relabel(k.Pause, "complete task"),
+			k.ArchiveAll, k.Back, k.Up, k.Down,
+		}
+	case viewReports:
+		return []key.Binding{
+			relabel(k.Left, "previous period"), relabel(k.Right, "next period"),
+			k.Mode, k.Filter, k.HideEmpty, relabel(k.Copy, "copy table"), relabel(k.Up, "scroll table"), relabel(k.Down, "scroll table"),
+		}
+	case viewPomodoro:
+		return []key.Binding{
+			relabel(k.Start, "start/continue"), relabel(k.Stop, "cancel"), relabel(k.Pause, "pause/skip break"),
+			relabel(k.Delete, "delete session"), k.Up, k.Down,
+		}
+	case viewSettings:
+		return []key.Binding{
+			relabel(k.Enter, "edit settings"), k.Categories, k.Prune, k.Compact, k.SaveConfig, k.LoadConfig, k.Snapshot,
+		}
+	}
+	return nil
+}
+
+// relabel returns a copy of b with a view-specific description.
+func relabel(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
 }
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// miniWidth is the terminal width below which the dashboard drops its
+// bordered panels for the condensed layout, e.g. in a tmux sidebar.
+const miniWidth = 64
+
+// renderMini is the dashboard as plain lines: the timer, the summary
+// totals and the recent entries, each cut to the terminal width.
+func (d dashboardModel) renderMini() string {
+	w := d.width
+	rows := []string{d.miniTimerLine()}
+	if len(d.timers) > 0 {
+		rows = append(rows, d.otherTimerLines()...)
+	}
+	rows = append(rows, "")
+
+	period, summary, total := d.summaryPeriod()
+	header := titleStyle.Render(period) + "  " + highlightStyle.Render(formatSeconds(total))
+	if d.weeklyGoal > 0 {
+		header += mutedStyle.Render(fmt.Sprintf("  week %s / %s", formatSeconds(d.weekTotal), formatSeconds(d.weeklyGoal)))
+	}
+	rows = append(rows, header)
+	for _, s := range summary {
+		rows = append(rows, fmt.Sprintf("%s %s %s",
+			projectMarker(s.ProjectID, s.ProjectColor),
+			formatSeconds(s.TotalSeconds),
+			s.ProjectName,
+		))
+	}
+	rows = append(rows, "")
+
+	if panel, ok := d.renderOverlayPanel(w); ok {
+		return lipgloss.JoinVertical(lipgloss.Left, cutLines(rows, w), panel)
+	}
+
+	rows = append(rows, titleStyle.Render("Recent"))
+	if len(d.recentEntries) == 0 {
+		rows = append(rows, mutedStyle.Render("No entries yet"))
+		return cutLines(rows, w)
+	}
+	first, last := 0, len(d.recentEntries)
+	if d.height > 0 {
+		first, last = scrollWindow(d.recentCursor, last, max(d.height-len(rows), 1))
+	}
+	for i := first; i < last; i++ {
+		e := d.recentEntries[i]
+		status, dur := "✓", formatSeconds(e.Duration)
+		if e.EndTime == nil {
+			status, dur = "●", "running"
+		}
+		cursor, style := "  ", normalItemStyle
+		if i == d.recentCursor {
+			cursor, style = "> ", selectedItemStyle
+		}
+		text := fmt.Sprintf("%s%s %s %s %s", cursor, status, e.StartTime.Local().Format("15:04"), dur, e.ProjectName)
+		rows = append(rows, style.Render(text))
+	}
+	return cutLines(rows, w)
+}
+
+// miniTimerLine is the timer state on one line.
+func (d dashboardModel) miniTimerLine() string {
+	if !d.timer.running() {
+		return mutedStyle.Render("■ 00:00:00") + "  " + mutedStyle.Render("s: start")
+	}
+	return timerLine(d.timer)
+}
+
+// timerLine is a running timer's state, elapsed time and project on one
+// line.
+func timerLine(t timerModel) string {
+	elapsed := formatDuration(t.currentElapsed())
+	line := successStyle.Render("● " + elapsed)
+	if t.paused() {
+		line = warningStyle.Render("⏸ " + elapsed)
+	}
+	project := t.projectName
+	if t.taskName != "" {
+		project += " / " + t.taskName
+	}
+	return line + "  " + highlightStyle.Render(project)
+}
+
+// cutLines joins rows, truncating each to w cells so nothing wraps.
+func cutLines(rows []string, w int) string {
+	for i, r := range rows {
+		rows[i] = ansi.Truncate(r, w, "…")
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sadopc/trackr/internal/store"
+)
+
+// multiTimer mirrors the multi_timer setting. When it is on, starting a
+// timer while one runs keeps the first one going, so several entries can
+// run at once. Pause, stop, idle detection and the other timer keys act on
+// the selected timer only.
+var multiTimer bool
+
+// loadMultiTimer reads the multi_timer setting.
+func loadMultiTimer(s *store.Store) {
+	v, _ := s.GetSetting("multi_timer")
+	multiTimer = v == "true"
+}
+
+// activeTimers counts the running timers, the selected one included.
+func (d dashboardModel) activeTimers() int {
+	n := len(d.timers)
+	if d.timer.running() {
+		n++
+	}
+	return n
+}
+
+// nextTimer selects the next running timer and moves the selected one to
+// the back of the others.
+func (d *dashboardModel) nextTimer() {
+	if len(d.timers) == 0 || !d.timer.running() {
+		return
+	}
+	others := append(slices.Clone(d.timers[1:]), d.timer)
+	d.timer, d.timers = d.timers[0], others
+	// The others are not watched for idleness, so start afresh.
+	d.timer.lastActivity = time.Now()
+}
+
+// promoteTimer selects the first of the other timers once the selected one
+// has stopped.
+func (d *dashboardModel) promoteTimer() {
+	if d.timer.running() || len(d.timers) == 0 {
+		return
+	}
+	d.timer, d.timers = d.timers[0], slices.Clone(d.timers[1:])
+	d.timer.lastActivity = time.Now()
+}
+
+// stopAllTimers stops every running timer, not just the selected one.
+func (d dashboardModel) stopAllTimers() (dashboardModel, error) {
+	if _, err := d.store.StopAllRunning(); err != nil {
+		return d, err
+	}
+	d.timer.state = timerStopped
+	d.timer.elapsed = 0
+	d.timers = nil
+	return d, nil
+}
+
+// reconcileTimers is reconcile for every timer: timers whose entries were
+// stopped elsewhere are dropped and, in multi-timer mode, entries started
+// elsewhere are adopted. With a single timer outside multi-timer mode it
+// is plain reconcile.
+func (d *dashboardModel) reconcileTimers() error {
+	if !multiTimer && len(d.timers) == 0 {
+		return d.timer.reconcile()
+	}
+	running, err := d.store.ListRunningEntries()
+	if err != nil {
+		return err
+	}
+	live := make(map[int64]bool, len(running))
+	for _, e := range running {
+		live[e.ID] = true
+	}
+	if d.timer.running() && !live[d.timer.entryID] {
+		d.timer.state = timerStopped
+		d.timer.elapsed = 0
+	}
+
+	tracked := map[int64]bool{d.timer.entryID: d.timer.running()}
+	var others []timerModel
+	for _, t := range d.timers {
+		if live[t.entryID] {
+			others = append(others, t)
+			tracked[t.entryID] = true
+		}
+	}
+	if multiTimer {
+		for i := range running {
+			if tracked[running[i].ID] {
+				continue
+			}
+			t := newTimerModel(d.store)
+			if err := t.restoreEntry(&running[i]); err != nil {
+				return err
+			}
+			others = append(others, t)
+		}
+	}
+	d.timers = others
+	d.promoteTimer()
+	return nil
+}
+
+// renderOtherTimers stacks the running timers besides the selected one
+// under the timer panel, or returns "" when there are none.
+func (d dashboardModel) renderOtherTimers(w int) string {
+	if len(d.timers) == 0 {
+		return ""
+	}
+	return panelStyle.Width(w).Render(strings.Join(d.otherTimerLines(), "\n"))
+}
+
+// otherTimerLines is a heading and one line per other running timer.
+func (d dashboardModel) otherTimerLines() []string {
+	rows := []string{titleStyle.Render(fmt.Sprintf("Also running (%d)", len(d.timers))) + mutedStyle.Render("  v: switch timer")}
+	for _, t := range d.timers {
+		rows = append(rows, timerLine(t))
+	}
+	return rows
+}
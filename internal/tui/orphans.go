@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sadopc/trackr/internal/store"
+)
+
+// defaultOrphanThreshold is how long a timer may run before trackr asks
+// about it on launch; the orphan_threshold setting (seconds) overrides it.
+const defaultOrphanThreshold = 12 * time.Hour
+
+var orphanOptions = []string{"Keep as-is", "Stop now", "Discard"}
+
+// orphan is a timer found running for longer than the threshold on launch.
+type orphan struct {
+	entry   store.TimeEntry
+	project string
+}
+
+// loadOrphans lists the long-running timers to ask about on launch.
+func loadOrphans(s *store.Store) []orphan {
+	entries, err := s.ListOrphanedEntries(s.GetSettingDuration("orphan_threshold", defaultOrphanThreshold))
+	if err != nil {
+		return nil
+	}
+	out := make([]orphan, len(entries))
+	for i, e := range entries {
+		out[i] = orphan{entry: e}
+		if p, err := s.GetProject(e.ProjectID); err == nil {
+			out[i].project = p.Name
+		}
+	}
+	return out
+}
+
+// startedLabel describes when t was, relative to now: "today 09:00",
+// "yesterday 09:00" or "Mon Jan 02 09:00".
+func startedLabel(t, now time.Time) string {
+	t = t.In(now.Location())
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.Date()
+	yy, ym, yd := now.AddDate(0, 0, -1).Date()
+	switch {
+	case y1 == y2 && m1 == m2 && d1 == d2:
+		return "today " + t.Format("15:04")
+	case y1 == yy && m1 == ym && d1 == yd:
+		return "yesterday " + t.Format("15:04")
+	}
+	return t.Format("Mon Jan 02 15:04")
+}
+
+func (a App) renderOrphanPrompt() string {
+	o := a.orphans[0]
+	title := "Found a timer still running"
+	if len(a.orphans) > 1 {
+		title = fmt.Sprintf("Found %d timers still running", len(a.orphans))
+	}
+	now := time.Now()
+	detail := fmt.Sprintf("%s has been running for %s (started %s)",
+		highlightStyle.Render(o.project), formatShort(int64(now.Sub(o.entry.StartTime).Seconds())),
+		startedLabel(o.entry.StartTime, now))
+
+	rows := []string{warningStyle.Bold(true).Render(title), "", detail, ""}
+	for i, opt := range orphanOptions {
+		cursor := "  "
+		style := normalItemStyle
+		if i == a.orphanCursor {
+			cursor = "> "
+			style = selectedItemStyle
+		}
+		rows = append(rows, style.Render(cursor+opt))
+	}
+	rows = append(rows, "", mutedStyle.Render("  enter: confirm  esc: keep as-is"))
+
+	w := a.width - 4
+	return activePanelStyle.Width(w).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// updateOrphanPrompt resolves the first orphaned timer and moves on to the
+// next one, if any.
+func (a App) updateOrphanPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	choice := -1
+	switch {
+	case key.Matches(msg, keys.Up):
+		if a.orphanCursor > 0 {
+			a.orphanCursor--
+		}
+	case key.Matches(msg, keys.Down):
+		if a.orphanCursor < len(orphanOptions)-1 {
+			a.orphanCursor++
+		}
+	case key.Matches(msg, keys.Enter):
+		choice = a.orphanCursor
+	case key.Matches(msg, keys.Back):
+		choice = 0
+	}
+	if choice < 0 {
+		return a, nil
+	}
+
+	o := a.orphans[0]
+	a.orphans = a.orphans[1:]
+	a.orphanCursor = 0
+	var err error
+	var status string
+	switch choice {
+	case 0:
+		return a, nil
+	case 1:
+		_, err = a.store.StopEntry(o.entry.ID)
+		status = "Stopped the " + o.project + " timer"
+	case 2:
+		err = a.store.DeleteEntry(o.entry.ID)
+		status = "Discarded the " + o.project + " timer"
+	}
+	if err != nil {
+		return a, errorStatus(err)
+	}
+	// Catch the timer up now, so the dashboard does not report it as
+	// stopped outside trackr.
+	if err := a.dashboard.reconcileTimers(); err != nil {
+		return a, errorStatus(err)
+	}
+	return a, tea.Batch(a.dashboard.loadData(), func() tea.Msg { return statusMsg{text: status} })
+}
@@ -2,7 +2,6 @@ package tui
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -47,13 +46,35 @@ type pomodoroModel struct {
 	workDuration      time.Duration
 	breakDuration     time.Duration
 	longBreakDuration time.Duration
+	autoStartBreak    bool
+	autoStartWork     bool
+
+	// waiting is set between phases when auto-start is off: phase is the
+	// upcoming phase and the countdown starts on the next start key.
+	waiting bool
+
+	// paused freezes a work phase's countdown at remaining; resuming moves
+	// phaseEnd on by the time spent paused.
+	paused   bool
+	pausedAt time.Time
 
 	sessionID int64 // pomodoro_sessions.id
 	entryID   *int64
 
+	// Recent sessions, newest first
+	history       []store.PomodoroSession
+	historyCursor int
+
 	formActive bool
 }
 
+// pomodoroHistoryDays is how far back the session history goes.
+const pomodoroHistoryDays = 7
+
+type pomodoroHistoryMsg struct {
+	sessions []store.PomodoroSession
+}
+
 func newPomodoroModel(s *store.Store) pomodoroModel {
 	m := pomodoroModel{
 		store:       s,
@@ -65,24 +86,31 @@ func newPomodoroModel(s *store.Store) pomodoroModel {
 }
 
 func (p *pomodoroModel) loadSettings() {
-	p.workDuration = p.getSettingDuration("pomodoro_work", 25*time.Minute)
-	p.breakDuration = p.getSettingDuration("pomodoro_break", 5*time.Minute)
-	p.longBreakDuration = p.getSettingDuration("pomodoro_long_break", 15*time.Minute)
-
-	if v, err := p.store.GetSetting("pomodoro_count"); err == nil {
-		if n, err := strconv.Atoi(v); err == nil {
-			p.targetCount = n
-		}
+	// Durations fall back for non-positive values, so a phase can never end
+	// immediately.
+	p.workDuration = p.store.GetSettingDuration("pomodoro_work", 25*time.Minute)
+	p.breakDuration = p.store.GetSettingDuration("pomodoro_break", 5*time.Minute)
+	p.longBreakDuration = p.store.GetSettingDuration("pomodoro_long_break", 15*time.Minute)
+
+	p.autoStartBreak = p.store.GetSettingBool("pomodoro_auto_start_break", true)
+	p.autoStartWork = p.store.GetSettingBool("pomodoro_auto_start_work", true)
+
+	p.targetCount = 4
+	if n := p.store.GetSettingInt("pomodoro_count", 4); n >= 1 {
+		p.targetCount = n
 	}
 }
 
-func (p *pomodoroModel) getSettingDuration(key string, fallback time.Duration) time.Duration {
-	if v, err := p.store.GetSetting(key); err == nil {
-		if secs, err := strconv.Atoi(v); err == nil {
-			return time.Duration(secs) * time.Second
+// refresh loads the session history.
+func (p pomodoroModel) refresh() tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		sessions, err := p.store.ListPomodoroSessions(now.AddDate(0, 0, -pomodoroHistoryDays), now.Add(time.Minute))
+		if err != nil {
+			return errorStatus(err)()
 		}
+		return pomodoroHistoryMsg{sessions: sessions}
 	}
-	return fallback
 }
 
 func (p *pomodoroModel) setSize(w, h int) {
@@ -92,14 +120,33 @@ func (p *pomodoroModel) setSize(w, h int) {
 
 func (p pomodoroModel) update(msg tea.Msg) (pomodoroModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case pomodoroHistoryMsg:
+		p.history = msg.sessions
+		if p.historyCursor >= len(p.history) {
+			p.historyCursor = max(0, len(p.history)-1)
+		}
+		return p, nil
+
 	case tickMsg:
-		if p.phase == pomodoroWork || p.phase == pomodoroShortBreak || p.phase == pomodoroLongBreak {
-			p.remaining = time.Until(p.phaseEnd)
-			if p.remaining <= 0 {
-				return p.advancePhase()
+		if !p.timedPhase() {
+			return p, nil
+		}
+		// After a sleep or a delayed tick several phases may have elapsed;
+		// advance through all of them, anchoring each new phase at the end
+		// of the previous one so the countdown reflects real time.
+		var cmds []tea.Cmd
+		p.remaining = time.Until(p.phaseEnd)
+		for p.timedPhase() && p.remaining <= 0 {
+			prevEnd := p.phaseEnd
+			var cmd tea.Cmd
+			p, cmd = p.advancePhase()
+			cmds = append(cmds, cmd)
+			if p.timedPhase() {
+				p.phaseEnd = prevEnd.Add(p.phaseDuration())
+				p.remaining = time.Until(p.phaseEnd)
 			}
 		}
-		return p, nil
+		return p, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
 		switch {
@@ -107,20 +154,62 @@ func (p pomodoroModel) update(msg tea.Msg) (pomodoroModel, tea.Cmd) {
 			if p.phase == pomodoroIdle || p.phase == pomodoroCompleted {
 				return p.startSession()
 			}
+			if p.waiting {
+				return p.beginWaitingPhase()
+			}
+			if p.paused {
+				return p.resume(), nil
+			}
 		case key.Matches(msg, keys.Stop):
 			if p.phase != pomodoroIdle {
 				return p.cancelSession()
 			}
 		case key.Matches(msg, keys.Pause):
-			// Skip break
-			if p.phase == pomodoroShortBreak || p.phase == pomodoroLongBreak {
+			switch {
+			case p.paused:
+				return p.resume(), nil
+			case p.phase == pomodoroWork && !p.waiting:
+				return p.pause(), nil
+			case p.phase == pomodoroShortBreak || p.phase == pomodoroLongBreak:
+				// Skip break
 				return p.startWorkPhase()
 			}
+		case key.Matches(msg, keys.Up):
+			if p.historyCursor > 0 {
+				p.historyCursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if p.historyCursor < len(p.history)-1 {
+				p.historyCursor++
+			}
+		case key.Matches(msg, keys.Delete):
+			if len(p.history) > 0 {
+				return p, p.deleteSession(p.history[p.historyCursor])
+			}
 		}
 	}
 	return p, nil
 }
 
+// timedPhase reports whether the current phase is counting down.
+func (p pomodoroModel) timedPhase() bool {
+	if p.waiting || p.paused {
+		return false
+	}
+	return p.phase == pomodoroWork || p.phase == pomodoroShortBreak || p.phase == pomodoroLongBreak
+}
+
+// phaseDuration returns the configured length of the current phase.
+func (p pomodoroModel) phaseDuration() time.Duration {
+	switch p.phase {
+	case pomodoroShortBreak:
+		return p.breakDuration
+	case pomodoroLongBreak:
+		return p.longBreakDuration
+	}
+	return p.workDuration
+}
+
 func (p pomodoroModel) startSession() (pomodoroModel, tea.Cmd) {
 	p.completedCount = 0
 	p.loadSettings()
@@ -137,11 +226,30 @@ func (p pomodoroModel) startSession() (pomodoroModel, tea.Cmd) {
 	}
 	p.sessionID = session.ID
 
-	return p.startWorkPhase()
+	p, cmd := p.startWorkPhase()
+	return p, tea.Batch(cmd, p.refresh())
+}
+
+// pause freezes the work countdown.
+func (p pomodoroModel) pause() pomodoroModel {
+	p.remaining = time.Until(p.phaseEnd)
+	p.paused = true
+	p.pausedAt = time.Now()
+	return p
+}
+
+// resume continues a paused countdown from where it stopped.
+func (p pomodoroModel) resume() pomodoroModel {
+	p.phaseEnd = p.phaseEnd.Add(time.Since(p.pausedAt))
+	p.remaining = time.Until(p.phaseEnd)
+	p.paused = false
+	return p
 }
 
 func (p pomodoroModel) startWorkPhase() (pomodoroModel, tea.Cmd) {
 	p.phase = pomodoroWork
+	p.waiting = false
+	p.paused = false
 	p.remaining = p.workDuration
 	p.phaseEnd = time.Now().Add(p.workDuration)
 	if p.sessionID > 0 {
@@ -163,31 +271,54 @@ func (p pomodoroModel) advancePhase() (pomodoroModel, tea.Cmd) {
 			if p.sessionID > 0 {
 				p.store.CompletePomodoro(p.sessionID)
 			}
-			return p, func() tea.Msg {
-				return statusMsg{text: "Pomodoro session complete! \a"}
-			}
+			return p, tea.Batch(p.notify("Pomodoro session complete!"), p.refresh())
 		}
 
 		// Every 4th pomodoro gets a long break
 		if p.completedCount%p.targetCount == 0 {
 			p.phase = pomodoroLongBreak
-			p.remaining = p.longBreakDuration
-			p.phaseEnd = time.Now().Add(p.longBreakDuration)
 		} else {
 			p.phase = pomodoroShortBreak
-			p.remaining = p.breakDuration
-			p.phaseEnd = time.Now().Add(p.breakDuration)
 		}
+		p.remaining = p.phaseDuration()
+		if !p.autoStartBreak {
+			p.waiting = true
+			return p, p.notify("Work done! Press s to start your break")
+		}
+		p.phaseEnd = time.Now().Add(p.remaining)
 		if p.sessionID > 0 {
 			p.store.UpdatePomodoroStatus(p.sessionID, string(phaseNames[p.phase]))
 		}
-		return p, func() tea.Msg {
-			return statusMsg{text: "Break time! \a"}
-		}
+		return p, p.notify("Break time!")
 
 	case pomodoroShortBreak, pomodoroLongBreak:
+		if !p.autoStartWork {
+			p.phase = pomodoroWork
+			p.remaining = p.workDuration
+			p.waiting = true
+			return p, p.notify("Break over! Press s to start working")
+		}
+		return p.startWorkPhase()
+	}
+	return p, nil
+}
+
+// notify reports a phase change in the status bar and rings the bell.
+func (p pomodoroModel) notify(text string) tea.Cmd {
+	return tea.Batch(bell(p.store), func() tea.Msg { return statusMsg{text: text} })
+}
+
+// beginWaitingPhase starts the countdown for a phase that was held because
+// auto-start is off.
+func (p pomodoroModel) beginWaitingPhase() (pomodoroModel, tea.Cmd) {
+	if p.phase == pomodoroWork {
 		return p.startWorkPhase()
 	}
+	p.waiting = false
+	p.phaseEnd = time.Now().Add(p.remaining)
+	if p.sessionID > 0 {
+		p.store.UpdatePomodoroStatus(p.sessionID, string(phaseNames[p.phase]))
+	}
 	return p, nil
 }
 
@@ -196,10 +327,26 @@ func (p pomodoroModel) cancelSession() (pomodoroModel, tea.Cmd) {
 		p.store.CancelPomodoro(p.sessionID)
 	}
 	p.phase = pomodoroIdle
+	p.waiting = false
+	p.paused = false
 	p.remaining = 0
-	return p, func() tea.Msg {
+	return p, tea.Batch(p.refresh(), func() tea.Msg {
 		return statusMsg{text: "Pomodoro cancelled"}
+	})
+}
+
+// deleteSession removes a session from the history. The session in
+// progress can't be deleted.
+func (p pomodoroModel) deleteSession(session store.PomodoroSession) tea.Cmd {
+	if session.ID == p.sessionID && p.phase != pomodoroIdle && p.phase != pomodoroCompleted {
+		return func() tea.Msg {
+			return statusMsg{text: "Can't delete the session in progress", isError: true}
+		}
 	}
+	if err := p.store.DeletePomodoroSession(session.ID); err != nil {
+		return errorStatus(err)
+	}
+	return tea.Batch(p.refresh(), func() tea.Msg { return statusMsg{text: "Pomodoro session deleted"} })
 }
 
 func (p pomodoroModel) view() string {
@@ -235,6 +382,13 @@ func (p pomodoroModel) view() string {
 		indicator = p.renderProgress()
 	}
 
+	if p.waiting {
+		indicator = lipgloss.JoinVertical(lipgloss.Center, indicator, mutedStyle.Render("Press s to start"))
+	}
+	if p.paused {
+		phaseLabel = warningStyle.Bold(true).Render("WORK · PAUSED")
+	}
+
 	content := lipgloss.JoinVertical(lipgloss.Center,
 		title,
 		"",
@@ -250,14 +404,64 @@ func (p pomodoroModel) view() string {
 	case pomodoroIdle, pomodoroCompleted:
 		controls = mutedStyle.Render("s: start  q: quit")
 	case pomodoroWork:
-		controls = mutedStyle.Render("x: cancel")
+		controls = mutedStyle.Render("space: pause  x: cancel")
+		if p.paused {
+			controls = mutedStyle.Render("space: resume  x: cancel")
+		}
 	case pomodoroShortBreak, pomodoroLongBreak:
 		controls = mutedStyle.Render("space: skip break  x: cancel")
 	}
+	if p.waiting {
+		controls = mutedStyle.Render("s: start  ") + controls
+	}
 
-	return panelStyle.Width(w).Render(
-		lipgloss.JoinVertical(lipgloss.Center, content, "", controls),
-	)
+	timer := panelStyle.Width(w).Render(lipgloss.JoinVertical(lipgloss.Center, content, "", controls))
+	return lipgloss.JoinVertical(lipgloss.Left, timer, p.renderHistory(w, p.height-lipgloss.Height(timer)))
+}
+
+// renderHistory lists the recent sessions: when each started, its status,
+// pomodoros done against the target and the focus time. Like the recent
+// entries panel, it scrolls to fit in h lines when h is positive.
+func (p pomodoroModel) renderHistory(w, h int) string {
+	title := titleStyle.Render(fmt.Sprintf("History (last %d days)", pomodoroHistoryDays))
+	if len(p.history) == 0 {
+		return panelStyle.Width(w).Render(lipgloss.JoinVertical(lipgloss.Left,
+			title, mutedStyle.Render("No sessions yet")))
+	}
+	// Border, padding, title and the hint take eight lines.
+	first, last := 0, len(p.history)
+	if p.height > 0 {
+		first, last = scrollWindow(p.historyCursor, last, max(h-8, 1))
+	}
+	rows := []string{title}
+	for i := first; i < last; i++ {
+		h := p.history[i]
+		cursor := "  "
+		style := normalItemStyle
+		if i == p.historyCursor {
+			cursor = "> "
+			style = selectedItemStyle
+		}
+		rows = append(rows, style.Render(fmt.Sprintf("%s%s  %-11s %d/%d  %s",
+			cursor,
+			h.StartedAt.Local().Format("Mon 15:04"),
+			sessionStatus(h.Status),
+			h.CompletedCount, h.TargetCount,
+			formatShort(int64(h.WorkDuration*h.CompletedCount)),
+		)))
+	}
+	rows = append(rows, "", mutedStyle.Render("  ↑/↓: move  d: delete session"))
+	return panelStyle.Width(w).Render(strings.Join(rows, "\n"))
+}
+
+// sessionStatus names a stored session status for the history; sessions
+// still in a work or break phase are in progress.
+func sessionStatus(status string) string {
+	switch status {
+	case "completed", "cancelled":
+		return status
+	}
+	return "in progress"
 }
 
 func (p pomodoroModel) renderProgress() string {
@@ -1,8 +1,12 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -24,29 +28,44 @@ type projectsModel struct {
 	cursor       int
 	taskCursor   int
 	showArchived bool
-	viewingTasks bool // true = viewing tasks of selected project
+	viewingTasks bool                // true = viewing tasks of selected project
+	stats        *store.ProjectStats // set while the selected project's details show
+	sortOrder    store.ProjectSort
 
 	formActive bool
 	form       *huh.Form
-	formType   string // "project", "task", "edit_project"
+	formType   string // "project", "task", "edit_project", "archive_tasks"
+	formErr    string // shown above the form after a failed save
 
 	// Form field pointers (survive value copies)
 	formName     *string
 	formColor    *string
 	formCategory *string
+	formDesc     *string
+	formRate     *string
+	formGoal     *string // hours per day
 	formTags     *string
+	formScope    *string // "completed" or "all" tasks to archive
+	formConfirm  *bool
 
 	editingID int64 // project ID being edited
+	focusID   int64 // project whose tasks open once projects load
 }
 
 func newProjectsModel(s *store.Store) projectsModel {
-	name, color, cat, tags := "", projectColors[0], "", ""
+	name, color, cat, desc, rate, goal, tags, scope, confirm := "", projectColors[0], "", "", "", "", "", "completed", false
 	return projectsModel{
 		store:        s,
+		sortOrder:    loadProjectSort(s),
 		formName:     &name,
 		formColor:    &color,
 		formCategory: &cat,
+		formDesc:     &desc,
+		formRate:     &rate,
+		formGoal:     &goal,
 		formTags:     &tags,
+		formScope:    &scope,
+		formConfirm:  &confirm,
 	}
 }
 
@@ -65,11 +84,35 @@ type tasksDataMsg struct {
 
 func (p projectsModel) refresh() tea.Cmd {
 	return func() tea.Msg {
-		projects, _ := p.store.ListProjects(p.showArchived)
+		projects, _ := p.store.ListProjectsSorted(p.showArchived, p.sortOrder)
 		return projectsDataMsg{projects: projects}
 	}
 }
 
+// loadProjectSort reads the project_sort setting, defaulting to by name.
+func loadProjectSort(s *store.Store) store.ProjectSort {
+	v, _ := s.GetSetting("project_sort")
+	if order := store.ProjectSort(v); slices.Contains(store.ProjectSorts, order) {
+		return order
+	}
+	return store.SortByName
+}
+
+// nextProjectSort cycles through store.ProjectSorts.
+func nextProjectSort(order store.ProjectSort) store.ProjectSort {
+	i := slices.Index(store.ProjectSorts, order)
+	return store.ProjectSorts[(i+1)%len(store.ProjectSorts)]
+}
+
+// focusProject reloads the project list and then opens the task list of
+// the given project, if it is listed.
+func (p projectsModel) focusProject(id int64) (projectsModel, tea.Cmd) {
+	p.focusID = id
+	p.viewingTasks = false
+	p.stats = nil
+	return p, p.refresh()
+}
+
 func (p projectsModel) refreshTasks() tea.Cmd {
 	if p.cursor >= len(p.projects) {
 		return nil
@@ -92,6 +135,18 @@ func (p projectsModel) update(msg tea.Msg) (projectsModel, tea.Cmd) {
 		if p.cursor >= len(p.projects) {
 			p.cursor = max(0, len(p.projects)-1)
 		}
+		if p.focusID != 0 {
+			id := p.focusID
+			p.focusID = 0
+			for i, proj := range p.projects {
+				if proj.ID == id {
+					p.cursor = i
+					p.viewingTasks = true
+					p.taskCursor = 0
+					return p, p.refreshTasks()
+				}
+			}
+		}
 		return p, nil
 
 	case tasksDataMsg:
@@ -102,6 +157,12 @@ func (p projectsModel) update(msg tea.Msg) (projectsModel, tea.Cmd) {
 		return p, nil
 
 	case tea.KeyMsg:
+		if p.stats != nil {
+			if key.Matches(msg, keys.Back, keys.Details) {
+				p.stats = nil
+			}
+			return p, nil
+		}
 		if p.viewingTasks {
 			return p.updateTaskView(msg)
 		}
@@ -131,9 +192,38 @@ func (p projectsModel) updateProjectList(msg tea.KeyMsg) (projectsModel, tea.Cmd
 	case key.Matches(msg, keys.Delete):
 		if len(p.projects) > 0 {
 			proj := p.projects[p.cursor]
-			p.store.ArchiveProject(proj.ID)
+			if err := p.store.ArchiveProject(proj.ID); err != nil {
+				return p, errorStatus(err)
+			}
 			return p, p.refresh()
 		}
+	case key.Matches(msg, keys.Pin):
+		if len(p.projects) > 0 {
+			proj := p.projects[p.cursor]
+			if err := p.store.SetProjectPinned(proj.ID, !proj.Pinned); err != nil {
+				return p, errorStatus(err)
+			}
+			return p, p.refresh()
+		}
+	case key.Matches(msg, keys.Categories):
+		if len(p.projects) > 0 {
+			return p.cycleCategory()
+		}
+	case key.Matches(msg, keys.Details):
+		if len(p.projects) > 0 {
+			stats, err := p.store.GetProjectStats(p.projects[p.cursor].ID)
+			if err != nil {
+				return p, errorStatus(err)
+			}
+			p.stats = stats
+		}
+	case key.Matches(msg, keys.Sort):
+		p.sortOrder = nextProjectSort(p.sortOrder)
+		p.cursor = 0
+		if err := p.store.SetSetting("project_sort", string(p.sortOrder)); err != nil {
+			return p, errorStatus(err)
+		}
+		return p, p.refresh()
 	case key.Matches(msg, keys.Export):
 		if len(p.projects) > 0 {
 			return p.showEditProjectForm()
@@ -160,7 +250,27 @@ func (p projectsModel) updateTaskView(msg tea.KeyMsg) (projectsModel, tea.Cmd) {
 	case key.Matches(msg, keys.Delete):
 		if len(p.tasks) > 0 {
 			task := p.tasks[p.taskCursor]
-			p.store.ArchiveTask(task.ID)
+			if err := p.store.ArchiveTask(task.ID); err != nil {
+				return p, errorStatus(err)
+			}
+			return p, p.refreshTasks()
+		}
+	case key.Matches(msg, keys.ArchiveAll):
+		if len(p.tasks) > 0 {
+			return p.showArchiveTasksForm()
+		}
+	case key.Matches(msg, keys.Pause):
+		if len(p.tasks) > 0 {
+			task := p.tasks[p.taskCursor]
+			var err error
+			if task.CompletedAt != nil {
+				err = p.store.ReopenTask(task.ID)
+			} else {
+				err = p.store.CompleteTask(task.ID)
+			}
+			if err != nil {
+				return p, errorStatus(err)
+			}
 			return p, p.refreshTasks()
 		}
 	}
@@ -171,27 +281,11 @@ func (p projectsModel) showNewProjectForm() (projectsModel, tea.Cmd) {
 	*p.formName = ""
 	*p.formColor = projectColors[0]
 	*p.formCategory = "work"
+	*p.formDesc = ""
+	*p.formRate = ""
+	*p.formGoal = ""
 	p.formType = "project"
-
-	colorOptions := make([]huh.Option[string], len(projectColors))
-	for i, c := range projectColors {
-		colorOptions[i] = huh.NewOption(fmt.Sprintf("● %s", c), c)
-	}
-	catOptions := make([]huh.Option[string], len(projectCategories))
-	for i, c := range projectCategories {
-		catOptions[i] = huh.NewOption(c, c)
-	}
-
-	p.form = huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().Title("Project Name").Value(p.formName),
-			huh.NewSelect[string]().Title("Color").Options(colorOptions...).Value(p.formColor),
-			huh.NewSelect[string]().Title("Category").Options(catOptions...).Value(p.formCategory),
-		),
-	).WithShowHelp(true).WithShowErrors(true)
-
-	p.formActive = true
-	return p, p.form.Init()
+	return p.showProjectForm()
 }
 
 func (p projectsModel) showEditProjectForm() (projectsModel, tea.Cmd) {
@@ -199,16 +293,24 @@ func (p projectsModel) showEditProjectForm() (projectsModel, tea.Cmd) {
 	*p.formName = proj.Name
 	*p.formColor = proj.Color
 	*p.formCategory = proj.Category
+	*p.formDesc = proj.Description
+	*p.formRate = formatRate(proj.HourlyRate)
+	*p.formGoal = formatGoal(proj.DailyGoal)
 	p.formType = "edit_project"
 	p.editingID = proj.ID
+	return p.showProjectForm()
+}
 
+// showProjectForm builds the project form from the current field values, so
+// it can be reopened with the user's input after a failed save.
+func (p projectsModel) showProjectForm() (projectsModel, tea.Cmd) {
 	colorOptions := make([]huh.Option[string], len(projectColors))
 	for i, c := range projectColors {
 		colorOptions[i] = huh.NewOption(fmt.Sprintf("● %s", c), c)
 	}
-	catOptions := make([]huh.Option[string], len(projectCategories))
-	for i, c := range projectCategories {
-		catOptions[i] = huh.NewOption(c, c)
+	var catOptions []huh.Option[string]
+	for _, c := range p.categories() {
+		catOptions = append(catOptions, huh.NewOption(c, c))
 	}
 
 	p.form = huh.NewForm(
@@ -216,6 +318,9 @@ func (p projectsModel) showEditProjectForm() (projectsModel, tea.Cmd) {
 			huh.NewInput().Title("Project Name").Value(p.formName),
 			huh.NewSelect[string]().Title("Color").Options(colorOptions...).Value(p.formColor),
 			huh.NewSelect[string]().Title("Category").Options(catOptions...).Value(p.formCategory),
+			huh.NewText().Title("Description (optional)").Value(p.formDesc),
+			huh.NewInput().Title("Hourly rate (optional)").Value(p.formRate).Validate(validateRate),
+			huh.NewInput().Title("Daily goal in hours (optional)").Value(p.formGoal).Validate(validateRate),
 		),
 	).WithShowHelp(true).WithShowErrors(true)
 
@@ -223,6 +328,33 @@ func (p projectsModel) showEditProjectForm() (projectsModel, tea.Cmd) {
 	return p, p.form.Init()
 }
 
+// categories is the built-in category list followed by any other
+// categories already in use, so renamed categories stay selectable.
+func (p projectsModel) categories() []string {
+	cats := append([]string(nil), projectCategories...)
+	inUse, _ := p.store.ListCategories()
+	for _, c := range inUse {
+		if !slices.Contains(cats, c) {
+			cats = append(cats, c)
+		}
+	}
+	return cats
+}
+
+// cycleCategory moves the selected project to the next category in
+// categories(), without opening the edit form.
+func (p projectsModel) cycleCategory() (projectsModel, tea.Cmd) {
+	proj := p.projects[p.cursor]
+	cats := p.categories()
+	next := cats[(slices.Index(cats, proj.Category)+1)%len(cats)]
+	if err := p.store.SetProjectCategory(proj.ID, next); err != nil {
+		return p, errorStatus(err)
+	}
+	return p, tea.Batch(p.refresh(), func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("Moved %s to %s", proj.Name, next)}
+	})
+}
+
 func (p projectsModel) showNewTaskForm() (projectsModel, tea.Cmd) {
 	*p.formName = ""
 	*p.formTags = ""
@@ -239,12 +371,54 @@ func (p projectsModel) showNewTaskForm() (projectsModel, tea.Cmd) {
 	return p, p.form.Init()
 }
 
+// showArchiveTasksForm asks whether to archive the project's completed
+// tasks or all of them, and confirms before doing so.
+func (p projectsModel) showArchiveTasksForm() (projectsModel, tea.Cmd) {
+	*p.formScope = "completed"
+	*p.formConfirm = false
+	p.formType = "archive_tasks"
+
+	p.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Archive").Options(
+				huh.NewOption("Completed tasks", "completed"),
+				huh.NewOption("All tasks", "all"),
+			).Value(p.formScope),
+			huh.NewConfirm().Title("Archive them? Their time entries are kept.").
+				Affirmative("Archive").Negative("Cancel").Value(p.formConfirm),
+		),
+	).WithShowHelp(true).WithShowErrors(true)
+
+	p.formActive = true
+	return p, p.form.Init()
+}
+
+// archiveTasks applies the archive form to the selected project.
+func (p projectsModel) archiveTasks() (projectsModel, tea.Cmd) {
+	if !*p.formConfirm || p.cursor >= len(p.projects) {
+		return p, p.refreshTasks()
+	}
+	n, err := p.store.ArchiveTasksForProject(p.projects[p.cursor].ID, *p.formScope == "completed")
+	if err != nil {
+		return p, tea.Batch(p.refreshTasks(), errorStatus(err))
+	}
+	noun := "tasks"
+	if n == 1 {
+		noun = "task"
+	}
+	p.taskCursor = 0
+	return p, tea.Batch(p.refreshTasks(), func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("Archived %d %s", n, noun)}
+	})
+}
+
 func (p projectsModel) updateForm(msg tea.Msg) (projectsModel, tea.Cmd) {
 	// Check for escape to cancel form
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		if msg.String() == "esc" {
 			p.formActive = false
 			p.form = nil
+			p.formErr = ""
 			return p, nil
 		}
 	}
@@ -257,27 +431,90 @@ func (p projectsModel) updateForm(msg tea.Msg) (projectsModel, tea.Cmd) {
 	if p.form.State == huh.StateCompleted {
 		p.formActive = false
 		switch p.formType {
-		case "project":
-			if *p.formName != "" {
-				p.store.CreateProject(*p.formName, *p.formColor, *p.formCategory)
-			}
-			return p, p.refresh()
-		case "edit_project":
-			if *p.formName != "" {
-				p.store.UpdateProject(p.editingID, *p.formName, *p.formColor, *p.formCategory)
-			}
-			return p, p.refresh()
+		case "project", "edit_project":
+			return p.saveProjectForm()
 		case "task":
-			if *p.formName != "" && p.cursor < len(p.projects) {
-				p.store.CreateTask(p.projects[p.cursor].ID, *p.formName, *p.formTags)
-			}
-			return p, p.refreshTasks()
+			return p.saveTaskForm()
+		case "archive_tasks":
+			return p.archiveTasks()
 		}
 	}
 
 	return p, cmd
 }
 
+// saveProjectForm creates or updates the project from the form fields. A
+// name collision reopens the form with the user's input and an error.
+func (p projectsModel) saveProjectForm() (projectsModel, tea.Cmd) {
+	if *p.formName == "" {
+		return p, p.refresh()
+	}
+	id := p.editingID
+	var err error
+	if p.formType == "project" {
+		var proj *store.Project
+		proj, err = p.store.CreateProject(*p.formName, *p.formColor, *p.formCategory, strings.TrimSpace(*p.formDesc))
+		if err == nil {
+			id = proj.ID
+		}
+	} else {
+		err = p.store.UpdateProject(id, *p.formName, *p.formColor, *p.formCategory, strings.TrimSpace(*p.formDesc))
+	}
+	if err == nil {
+		rate, _ := strconv.ParseFloat(strings.TrimSpace(*p.formRate), 64)
+		err = p.store.SetProjectRate(id, rate)
+	}
+	if err == nil {
+		hours, _ := strconv.ParseFloat(strings.TrimSpace(*p.formGoal), 64)
+		err = p.store.SetProjectDailyGoal(id, int64(hours*3600))
+	}
+	if errors.Is(err, store.ErrProjectExists) {
+		p.formErr = fmt.Sprintf("A project named %q already exists", *p.formName)
+		return p.showProjectForm()
+	}
+	p.formErr = ""
+	if err != nil {
+		return p, tea.Batch(p.refresh(), errorStatus(err))
+	}
+	return p, p.refresh()
+}
+
+// saveTaskForm creates a task in the selected project from the form fields.
+func (p projectsModel) saveTaskForm() (projectsModel, tea.Cmd) {
+	if *p.formName == "" || p.cursor >= len(p.projects) {
+		return p, p.refreshTasks()
+	}
+	if _, err := p.store.CreateTask(p.projects[p.cursor].ID, *p.formName, *p.formTags); err != nil {
+		return p, tea.Batch(p.refreshTasks(), errorStatus(err))
+	}
+	return p, p.refreshTasks()
+}
+
+// validateRate accepts a blank (unset) or non-negative hourly rate. Daily
+// goals use it too.
+func validateRate(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return validateNonNegativeFloat(s)
+}
+
+// formatRate renders an hourly rate for the form, blank when unset.
+func formatRate(rate float64) string {
+	if rate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// formatGoal renders a daily goal in hours for the form, blank when unset.
+func formatGoal(secs int64) string {
+	if secs == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(secs)/3600, 'f', -1, 64)
+}
+
 func (p projectsModel) view() string {
 	if p.formActive && p.form != nil {
 		title := titleStyle.Render("New Project")
@@ -285,12 +522,20 @@ func (p projectsModel) view() string {
 			title = titleStyle.Render("Edit Project")
 		} else if p.formType == "task" {
 			title = titleStyle.Render("New Task")
+		} else if p.formType == "archive_tasks" {
+			title = titleStyle.Render("Archive Tasks")
 		}
 		formView := p.form.View()
+		if p.formErr != "" {
+			title = lipgloss.JoinVertical(lipgloss.Left, title, errorStyle.Render(p.formErr))
+		}
 		content := lipgloss.JoinVertical(lipgloss.Left, title, "", formView)
 		return panelStyle.Width(p.width - 4).Render(content)
 	}
 
+	if p.stats != nil {
+		return p.renderProjectStats()
+	}
 	if p.viewingTasks {
 		return p.renderTaskView()
 	}
@@ -299,7 +544,7 @@ func (p projectsModel) view() string {
 
 func (p projectsModel) renderProjectList() string {
 	w := p.width - 4
-	title := titleStyle.Render("Projects")
+	title := titleStyle.Render("Projects") + mutedStyle.Render("  sorted by "+projectSortLabel(p.sortOrder))
 
 	if len(p.projects) == 0 {
 		content := lipgloss.JoinVertical(lipgloss.Left,
@@ -318,29 +563,82 @@ func (p projectsModel) renderProjectList() string {
 	header := mutedStyle.Render(fmt.Sprintf("  %-3s %-24s %-12s %-12s", "", "Name", "Category", "Color"))
 	rows = append(rows, header)
 
-	for i, proj := range p.projects {
-		colorDot := lipgloss.NewStyle().Foreground(lipgloss.Color(proj.Color)).Render("●")
+	// Border, padding, title, header and the hint take nine lines.
+	first, last := 0, len(p.projects)
+	if p.height > 0 {
+		first, last = scrollWindow(p.cursor, last, max(p.height-9, 1))
+	}
+	for i := first; i < last; i++ {
+		proj := p.projects[i]
+		colorDot := projectMarker(proj.ID, proj.Color)
 		cursor := "  "
 		style := normalItemStyle
 		if i == p.cursor {
 			cursor = "> "
 			style = selectedItemStyle
 		}
-		row := style.Render(fmt.Sprintf("%s%s %-24s %-12s", cursor, colorDot, proj.Name, proj.Category))
+		pin := " "
+		if proj.Pinned {
+			pin = warningStyle.Render("★")
+		}
+		row := style.Render(cursor) + colorDot + pin + style.Render(fmt.Sprintf("%-24s %-12s", proj.Name, proj.Category))
 		rows = append(rows, row)
 	}
 
 	rows = append(rows, "")
-	rows = append(rows, mutedStyle.Render("  n: new  e: edit  d: archive  enter: tasks  esc: back"))
+	rows = append(rows, mutedStyle.Render("  n: new  e: edit  c: category  d: archive  f: pin  o: sort  i: details  enter: tasks"))
 
 	return panelStyle.Width(w).Render(strings.Join(rows, "\n"))
 }
 
+// projectSortLabel names a sort order for the projects header.
+func projectSortLabel(order store.ProjectSort) string {
+	switch order {
+	case store.SortByRecent:
+		return "recent use"
+	case store.SortByTotal:
+		return "total time"
+	}
+	return "name"
+}
+
+// renderProjectStats shows the selected project's totals and when it was
+// first and last tracked.
+func (p projectsModel) renderProjectStats() string {
+	proj := p.projects[p.cursor]
+	title := titleStyle.Render(fmt.Sprintf("%s %s — Details", projectMarker(proj.ID, proj.Color), proj.Name))
+	date := func(t *time.Time) string {
+		if t == nil {
+			return "never"
+		}
+		return t.Local().Format("Mon Jan 02 2006")
+	}
+	st := p.stats
+	fields := []struct{ label, value string }{
+		{"Total", formatSeconds(st.TotalSeconds)},
+		{"This week", formatSeconds(st.WeekSeconds)},
+		{"Entries", strconv.Itoa(st.EntryCount)},
+		{"Average session", formatShort(st.AverageSeconds)},
+		{"First tracked", date(st.FirstTracked)},
+		{"Last tracked", date(st.LastTracked)},
+	}
+	rows := []string{title, ""}
+	for _, f := range fields {
+		label := lipgloss.NewStyle().Width(18).Render(f.label)
+		rows = append(rows, "  "+label+highlightStyle.Render(f.value))
+	}
+	rows = append(rows, "", mutedStyle.Render("  esc: back"))
+	return panelStyle.Width(p.width - 4).Render(strings.Join(rows, "\n"))
+}
+
 func (p projectsModel) renderTaskView() string {
 	w := p.width - 4
 	proj := p.projects[p.cursor]
-	colorDot := lipgloss.NewStyle().Foreground(lipgloss.Color(proj.Color)).Render("●")
+	colorDot := projectMarker(proj.ID, proj.Color)
 	title := titleStyle.Render(fmt.Sprintf("%s %s — Tasks", colorDot, proj.Name))
+	if proj.Description != "" {
+		title = lipgloss.JoinVertical(lipgloss.Left, title, mutedStyle.Render(proj.Description))
+	}
 
 	if len(p.tasks) == 0 {
 		content := lipgloss.JoinVertical(lipgloss.Left,
@@ -355,7 +653,14 @@ func (p projectsModel) renderTaskView() string {
 	rows = append(rows, title)
 	rows = append(rows, "")
 
-	for i, task := range p.tasks {
+	// Border, padding, the blank lines and the hint take seven lines
+	// besides the title.
+	first, last := 0, len(p.tasks)
+	if p.height > 0 {
+		first, last = scrollWindow(p.taskCursor, last, max(p.height-7-lipgloss.Height(title), 1))
+	}
+	for i := first; i < last; i++ {
+		task := p.tasks[i]
 		cursor := "  "
 		style := normalItemStyle
 		if i == p.taskCursor {
@@ -366,11 +671,19 @@ func (p projectsModel) renderTaskView() string {
 		if task.Tags != "" {
 			tags = mutedStyle.Render(" [" + task.Tags + "]")
 		}
-		rows = append(rows, style.Render(fmt.Sprintf("%s%s", cursor, task.Name))+tags)
+		check := "☐ "
+		if task.CompletedAt != nil {
+			check = "☑ "
+			if i != p.taskCursor {
+				style = mutedStyle
+			}
+			style = style.Strikethrough(true)
+		}
+		rows = append(rows, style.Render(cursor)+check+style.Render(task.Name)+tags)
 	}
 
 	rows = append(rows, "")
-	rows = append(rows, mutedStyle.Render("  n: new task  d: archive  esc: back"))
+	rows = append(rows, mutedStyle.Render("  n: new task  space: done/reopen  d: archive  A: archive tasks  esc: back"))
 
 	return panelStyle.Width(w).Render(strings.Join(rows, "\n"))
 }
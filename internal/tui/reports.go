@@ -2,13 +2,18 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/NimbleMarkets/ntcharts/barchart"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/sadopc/trackr/internal/export"
 	"github.com/sadopc/trackr/internal/store"
 )
 
@@ -17,8 +22,17 @@ type reportMode int
 const (
 	reportDaily reportMode = iota
 	reportWeekly
+	reportTrend
+	reportHeatmap
+	reportTags
 )
 
+// reportModeNames are the mode tabs, indexed by reportMode.
+var reportModeNames = []string{"Daily", "Weekly", "Trend", "Heatmap", "Tags"}
+
+// trendWeeks is how many weeks the trend mode charts.
+const trendWeeks = 12
+
 type reportsModel struct {
 	store  *store.Store
 	width  int
@@ -26,16 +40,52 @@ type reportsModel struct {
 
 	mode      reportMode
 	summaries []store.DailySummary
-	offset    int // weeks or 7-day blocks offset from today (0 = current)
+	offset    int  // periods of the mode's length back from today (0 = current)
+	scroll    int  // first summary table row shown when the table is cut to fit
+	hideEmpty bool // leave days and rows without tracked time out of the chart and table
+	weekStart time.Weekday
+	dailyGoal int64 // seconds; 0 disables goal markers
+
+	billable    int64
+	nonBillable int64
+	revenue     []store.ProjectRevenue
+	records     reportRecords
+	tags        []store.TagSummary
+	weeks       []store.WeekSummary
+
+	// Project filter; 0 shows all projects.
+	projectID   int64
+	projectName string
+	formActive  bool
+	form        *huh.Form
+	formProject *int64
 
 	chart barchart.Model
 }
 
 func newReportsModel(s *store.Store) reportsModel {
-	return reportsModel{
-		store: s,
-		chart: barchart.New(60, 12),
+	var project int64
+	r := reportsModel{
+		store:       s,
+		chart:       barchart.New(60, 12),
+		formProject: &project,
+	}
+	r.weekStart = r.loadWeekStart()
+	r.dailyGoal = r.loadDailyGoal()
+	return r
+}
+
+func (r reportsModel) loadDailyGoal() int64 {
+	v, _ := r.store.GetSetting("daily_goal")
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || secs < 0 {
+		return 0
 	}
+	return secs
+}
+
+func (r reportsModel) loadWeekStart() time.Weekday {
+	return r.store.WeekStart()
 }
 
 func (r *reportsModel) setSize(w, h int) {
@@ -43,32 +93,185 @@ func (r *reportsModel) setSize(w, h int) {
 	r.height = h
 }
 
+// reportRecords are all-time personal bests, independent of the range.
+type reportRecords struct {
+	longest        *store.TimeEntry
+	longestProject string
+	bestDay        string // YYYY-MM-DD
+	bestDaySecs    int64
+	topProject     *store.Project
+	topSecs        int64
+	avgWorking     float64 // seconds per tracked day over the last averageDays
+	avgCalendar    float64 // seconds per calendar day over the same days
+	goalMetDays    int     // days over the same range reaching daily_goal
+	goalDays       int     // days in that range; 0 when no goal is set
+}
+
+// averageDays is how many days, up to today, the daily averages cover.
+const averageDays = 30
+
 type reportsDataMsg struct {
-	summaries []store.DailySummary
+	summaries   []store.DailySummary
+	weekStart   time.Weekday
+	dailyGoal   int64
+	billable    int64
+	nonBillable int64
+	revenue     []store.ProjectRevenue
+	records     reportRecords
+	tags        []store.TagSummary
+	weeks       []store.WeekSummary
 }
 
 func (r reportsModel) refresh() tea.Cmd {
 	return func() tea.Msg {
+		r.weekStart = r.loadWeekStart()
+		r.dailyGoal = r.loadDailyGoal()
 		from, to := r.dateRange()
-		summaries, _ := r.store.GetDailySummary(from, to)
-		return reportsDataMsg{summaries: summaries}
+		f := r.filter()
+		summaries, _ := r.store.GetFilteredDailySummary(from, to, f)
+		var tags []store.TagSummary
+		if r.mode == reportTags {
+			tags, _ = r.store.GetFilteredTagSummary(from, to, f)
+		}
+		var weeks []store.WeekSummary
+		if r.mode == reportTrend {
+			weeks, _ = r.store.GetFilteredWeeklySummary(from, to, r.weekStart, f)
+		}
+		billable, nonBillable, _ := r.store.GetBillableTotals(from, to)
+		revenue, _ := r.store.GetRevenueSummary(from, to)
+		if f.ProjectID != nil {
+			billable, nonBillable = r.projectBillable(from, to)
+			revenue = slices.DeleteFunc(revenue, func(p store.ProjectRevenue) bool { return p.ProjectID != r.projectID })
+		}
+		return reportsDataMsg{
+			summaries:   summaries,
+			weekStart:   r.weekStart,
+			dailyGoal:   r.dailyGoal,
+			billable:    billable,
+			nonBillable: nonBillable,
+			revenue:     revenue,
+			records:     r.loadRecords(),
+			tags:        tags,
+			weeks:       weeks,
+		}
+	}
+}
+
+func (r reportsModel) loadRecords() reportRecords {
+	var rec reportRecords
+	rec.longest, _ = r.store.GetLongestSession()
+	if rec.longest != nil {
+		if p, err := r.store.GetProject(rec.longest.ProjectID); err == nil {
+			rec.longestProject = p.Name
+		}
+	}
+	rec.bestDay, rec.bestDaySecs, _ = r.store.GetMostProductiveDay()
+	rec.topProject, rec.topSecs, _ = r.store.GetTopProject()
+	dayStart, dayEnd := r.store.Today()
+	from := dayStart.AddDate(0, 0, 1-averageDays)
+	rec.avgWorking, _ = r.store.GetAverageDaily(from, dayEnd, false)
+	rec.avgCalendar, _ = r.store.GetAverageDaily(from, dayEnd, true)
+	if goal := r.loadDailyGoal(); goal > 0 {
+		rec.goalMetDays, rec.goalDays, _ = r.store.CountGoalMetDays(from, dayEnd, goal)
+	}
+	return rec
+}
+
+// filter restricts report queries to the selected project, if any.
+func (r reportsModel) filter() store.EntryFilter {
+	var f store.EntryFilter
+	if r.projectID != 0 {
+		f.ProjectID = &r.projectID
+	}
+	return f
+}
+
+// projectBillable is GetBillableTotals for the filtered project.
+func (r reportsModel) projectBillable(from, to time.Time) (billable, nonBillable int64) {
+	entries, _ := r.store.ListEntries(store.EntryFilter{ProjectID: &r.projectID, From: &from, To: &to})
+	for _, e := range entries {
+		switch {
+		case e.EndTime == nil:
+		case e.Billable:
+			billable += e.Duration
+		default:
+			nonBillable += e.Duration
+		}
+	}
+	return billable, nonBillable
+}
+
+// exportSummaries writes the report on screen, for the current range and
+// project filter, to dir as CSV (format 0) or JSON.
+func (r reportsModel) exportSummaries(format int, dir string) tea.Cmd {
+	return func() tea.Msg {
+		from, to := r.dateRange()
+		f := r.filter()
+		summaries, err := r.store.GetFilteredDailySummary(from, to, f)
+		if err != nil {
+			return statusMsg{text: fmt.Sprintf("Export error: %v", err), isError: true}
+		}
+
+		name := fmt.Sprintf("trackr-report-%s_%s", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"))
+		var path string
+		if format == 0 {
+			path = filepath.Join(dir, name+".csv")
+			if err := export.SummariesToCSV(summaries, path, durationFormat); err != nil {
+				return statusMsg{text: fmt.Sprintf("CSV error: %v", err), isError: true}
+			}
+		} else {
+			path = filepath.Join(dir, name+".json")
+			if err := export.SummariesToJSON(summaries, path, durationFormat); err != nil {
+				return statusMsg{text: fmt.Sprintf("JSON error: %v", err), isError: true}
+			}
+		}
+		return exportDoneMsg{path: path}
+	}
+}
+
+// exportChart writes the chart on screen, with its legend, to dir as plain
+// text.
+func (r reportsModel) exportChart(dir string) tea.Cmd {
+	return func() tea.Msg {
+		from, to := r.dateRange()
+		header := r.rangeLabel()
+		if r.projectID != 0 {
+			header += " · " + r.projectName
+		}
+		text := strings.Join([]string{header, "", r.chartView(), "", r.legendView()}, "\n")
+
+		name := fmt.Sprintf("trackr-chart-%s_%s.txt", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"))
+		path := filepath.Join(dir, name)
+		if err := export.TextToFile(text, path); err != nil {
+			return statusMsg{text: fmt.Sprintf("Export error: %v", err), isError: true}
+		}
+		return exportDoneMsg{path: path}
 	}
 }
 
 func (r reportsModel) dateRange() (time.Time, time.Time) {
-	now := time.Now().UTC()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	switch r.mode {
 	case reportWeekly:
-		// Start of current week (Monday)
-		weekday := today.Weekday()
-		if weekday == time.Sunday {
-			weekday = 7
-		}
-		startOfWeek := today.AddDate(0, 0, -int(weekday-time.Monday))
+		// Start of current week per the week_start setting
+		startOfWeek := store.StartOfWeek(today, r.weekStart)
 		startOfWeek = startOfWeek.AddDate(0, 0, -7*r.offset)
 		return startOfWeek, startOfWeek.AddDate(0, 0, 7)
+	case reportTrend:
+		// The last trendWeeks weeks, ending with the current one
+		last := store.StartOfWeek(today, r.weekStart).AddDate(0, 0, -7*trendWeeks*r.offset)
+		return last.AddDate(0, 0, -7*(trendWeeks-1)), last.AddDate(0, 0, 7)
+	case reportHeatmap:
+		// As many whole weeks as fit, ending with the current one
+		weeks := r.heatmapWeeks()
+		last := store.StartOfWeek(today, r.weekStart).AddDate(0, 0, -7*weeks*r.offset)
+		return last.AddDate(0, 0, -7*(weeks-1)), last.AddDate(0, 0, 7)
+	case reportTags:
+		// Calendar month
+		month := time.Date(today.Year(), today.Month()-time.Month(r.offset), 1, 0, 0, 0, 0, today.Location())
+		return month, month.AddDate(0, 1, 0)
 	default:
 		// Daily: last 7 days
 		end := today.AddDate(0, 0, 1-7*r.offset)
@@ -78,9 +281,21 @@ func (r reportsModel) dateRange() (time.Time, time.Time) {
 }
 
 func (r reportsModel) update(msg tea.Msg) (reportsModel, tea.Cmd) {
+	if r.formActive && r.form != nil {
+		return r.updateForm(msg)
+	}
+
 	switch msg := msg.(type) {
 	case reportsDataMsg:
 		r.summaries = msg.summaries
+		r.weekStart = msg.weekStart
+		r.dailyGoal = msg.dailyGoal
+		r.billable = msg.billable
+		r.nonBillable = msg.nonBillable
+		r.revenue = msg.revenue
+		r.records = msg.records
+		r.tags = msg.tags
+		r.weeks = msg.weeks
 		r.buildChart()
 		return r, nil
 
@@ -88,25 +303,92 @@ func (r reportsModel) update(msg tea.Msg) (reportsModel, tea.Cmd) {
 		switch {
 		case key.Matches(msg, keys.Left):
 			r.offset++
+			r.scroll = 0
 			return r, r.refresh()
 		case key.Matches(msg, keys.Right):
 			if r.offset > 0 {
 				r.offset--
 			}
+			r.scroll = 0
 			return r, r.refresh()
-		case key.Matches(msg, keys.Tab):
-			if r.mode == reportDaily {
-				r.mode = reportWeekly
-			} else {
-				r.mode = reportDaily
+		case key.Matches(msg, keys.Up):
+			r.scroll = max(r.scroll-1, 0)
+		case key.Matches(msg, keys.Down):
+			if fit := r.tableFit(); fit > 0 {
+				r.scroll = min(r.scroll+1, len(r.tableRows())-fit)
 			}
+		case key.Matches(msg, keys.HideEmpty):
+			r.hideEmpty = !r.hideEmpty
+			r.scroll = 0
+			r.buildChart()
+		case key.Matches(msg, keys.Copy):
+			return r, copyText(r.summaryText())
+		case key.Matches(msg, keys.Filter):
+			return r.showFilterForm()
+		case key.Matches(msg, keys.Mode):
+			r.mode = (r.mode + 1) % reportMode(len(reportModeNames))
 			r.offset = 0
+			r.scroll = 0
 			return r, r.refresh()
 		}
 	}
 	return r, nil
 }
 
+// showFilterForm opens a project selector; "All projects" clears the filter.
+func (r reportsModel) showFilterForm() (reportsModel, tea.Cmd) {
+	projects, err := r.store.ListProjects(true)
+	if err != nil {
+		return r, errorStatus(err)
+	}
+	options := []huh.Option[int64]{huh.NewOption("All projects", int64(0))}
+	for _, p := range projects {
+		options = append(options, huh.NewOption(p.Name, p.ID))
+	}
+	*r.formProject = r.projectID
+
+	r.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int64]().Title("Show project").Options(options...).Value(r.formProject),
+		),
+	).WithShowHelp(true).WithShowErrors(true)
+	r.formActive = true
+	return r, r.form.Init()
+}
+
+func (r reportsModel) updateForm(msg tea.Msg) (reportsModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		r.formActive = false
+		r.form = nil
+		return r, nil
+	}
+
+	form, cmd := r.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		r.form = f
+	}
+	if r.form.State == huh.StateCompleted {
+		r.formActive = false
+		return r.setProject(*r.formProject)
+	}
+	return r, cmd
+}
+
+// setProject filters the reports to one project, or to all when id is 0.
+func (r reportsModel) setProject(id int64) (reportsModel, tea.Cmd) {
+	r.projectID = id
+	r.projectName = ""
+	if id != 0 {
+		p, err := r.store.GetProject(id)
+		if err != nil {
+			r.projectID = 0
+			return r, errorStatus(err)
+		}
+		r.projectName = p.Name
+	}
+	return r, r.refresh()
+}
+
 func (r *reportsModel) buildChart() {
 	chartWidth := r.width - 8
 	if chartWidth < 20 {
@@ -118,20 +400,34 @@ func (r *reportsModel) buildChart() {
 	}
 
 	r.chart = barchart.New(chartWidth, chartHeight)
+	switch r.mode {
+	case reportHeatmap, reportTags:
+		return
+	case reportTrend:
+		r.buildTrendChart()
+		return
+	}
 
 	from, to := r.dateRange()
 
 	// Build bars for each day in range
 	var bars []barchart.BarData
-	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+	for _, d := range chartDays(from, to, r.summaries, r.hideEmpty) {
 		dateStr := d.Format("2006-01-02")
 		label := d.Format("Mon 02")
 
 		var values []barchart.BarValue
+		var total int64
 		for _, s := range r.summaries {
 			if s.Date == dateStr {
+				total += s.TotalSeconds
 				hours := float64(s.TotalSeconds) / 3600.0
-				style := lipgloss.NewStyle().Foreground(lipgloss.Color(s.ProjectColor))
+				style := projectColor(s.ProjectColor)
+				if accessibleMode && (s.ProjectID-1)%2 == 1 {
+					// Alternate shading so stacked segments stay
+					// distinguishable without color.
+					style = style.Faint(true)
+				}
 				values = append(values, barchart.BarValue{
 					Name:  s.ProjectName,
 					Value: hours,
@@ -143,6 +439,10 @@ func (r *reportsModel) buildChart() {
 		if len(values) == 0 {
 			values = []barchart.BarValue{{Name: "", Value: 0, Style: lipgloss.NewStyle().Foreground(colorSubtle)}}
 		}
+		if r.goalMet(total) {
+			// ntcharts has no reference lines, so mark the label instead.
+			label = "✓" + label
+		}
 
 		bars = append(bars, barchart.BarData{
 			Label:  label,
@@ -154,37 +454,219 @@ func (r *reportsModel) buildChart() {
 	r.chart.Draw()
 }
 
+// chartDays lists the days in [from, to) to chart, leaving out those
+// without tracked time when hideEmpty is set.
+func chartDays(from, to time.Time, summaries []store.DailySummary, hideEmpty bool) []time.Time {
+	tracked := make(map[string]bool)
+	for _, s := range summaries {
+		if s.TotalSeconds > 0 {
+			tracked[s.Date] = true
+		}
+	}
+	var days []time.Time
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if hideEmpty && !tracked[d.Format("2006-01-02")] {
+			continue
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+// tableRows are the summaries the table lists: all of them, or only those
+// with tracked time when hideEmpty is set.
+func (r reportsModel) tableRows() []store.DailySummary {
+	if !r.hideEmpty {
+		return r.summaries
+	}
+	var rows []store.DailySummary
+	for _, s := range r.summaries {
+		if s.TotalSeconds > 0 {
+			rows = append(rows, s)
+		}
+	}
+	return rows
+}
+
+// buildTrendChart draws one bar per week with the week's total.
+func (r *reportsModel) buildTrendChart() {
+	style := lipgloss.NewStyle().Foreground(colorPrimary)
+	var bars []barchart.BarData
+	for _, w := range r.weeks {
+		bars = append(bars, barchart.BarData{
+			Label:  w.WeekStart.Format("Jan 02"),
+			Values: []barchart.BarValue{{Name: "Total", Value: float64(w.TotalSeconds) / 3600.0, Style: style}},
+		})
+	}
+	r.chart.PushAll(bars)
+	r.chart.Draw()
+}
+
+// renderTrendLegend sums up the weeks charted.
+func (r reportsModel) renderTrendLegend() string {
+	if len(r.weeks) == 0 {
+		return ""
+	}
+	var total, best int64
+	for _, w := range r.weeks {
+		total += w.TotalSeconds
+		if w.TotalSeconds > best {
+			best = w.TotalSeconds
+		}
+	}
+	avg := total / int64(len(r.weeks))
+	return mutedStyle.Render(fmt.Sprintf("  weekly average %s  ·  best week %s  ·  %s total",
+		formatShort(avg), formatShort(best), formatShort(total)))
+}
+
+// chartView is the bar chart, or what replaces it in the heatmap and tags
+// modes.
+func (r reportsModel) chartView() string {
+	switch r.mode {
+	case reportHeatmap:
+		return r.renderHeatmap()
+	case reportTags:
+		return r.renderTagTable()
+	case reportDaily, reportWeekly:
+		if r.hideEmpty && len(r.tableRows()) == 0 {
+			return mutedStyle.Render("  No tracked days in this period")
+		}
+	}
+	return r.chart.View()
+}
+
+// legendView is the legend for chartView.
+func (r reportsModel) legendView() string {
+	switch r.mode {
+	case reportTrend:
+		return r.renderTrendLegend()
+	case reportHeatmap:
+		return r.renderHeatmapLegend()
+	case reportTags:
+		return ""
+	}
+	return r.renderLegend()
+}
+
+// renderTagTable lists time per task tag with a bar scaled to the busiest.
+func (r reportsModel) renderTagTable() string {
+	if len(r.tags) == 0 {
+		return mutedStyle.Render("  No data for this period")
+	}
+	rows := []string{
+		mutedStyle.Render(fmt.Sprintf("  %-20s %10s %8s", "Tag", "Duration", "Entries")),
+		"  " + strings.Repeat("─", 54),
+	}
+	barWidth := max(min(r.width-50, 30), 5)
+	busiest := r.tags[0].TotalSeconds
+	for _, t := range r.tags {
+		n := 0
+		if busiest > 0 {
+			n = max(int(t.TotalSeconds*int64(barWidth)/busiest), 1)
+		}
+		name := t.Tag
+		if name != store.UntaggedLabel {
+			name = "#" + name
+		}
+		rows = append(rows, fmt.Sprintf("  %-20s %10s %8d  %s",
+			truncate(name, 20), formatShort(t.TotalSeconds), t.EntryCount, highlightStyle.Render(strings.Repeat("█", n))))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// goalMet reports whether a day's total reaches the daily goal.
+func (r reportsModel) goalMet(secs int64) bool {
+	return r.dailyGoal > 0 && secs >= r.dailyGoal
+}
+
 func (r reportsModel) view() string {
 	w := r.width - 4
 
+	if r.formActive && r.form != nil {
+		return panelStyle.Width(w).Render(
+			lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("Filter Reports"), "", r.form.View()),
+		)
+	}
+	return r.panel(r.tableFit())
+}
+
+// tableFit is how many summary table rows fit in the view's height, or 0
+// when the whole table fits or the height is unknown.
+func (r reportsModel) tableFit() int {
+	if r.height <= 0 || (r.mode != reportDaily && r.mode != reportWeekly) {
+		return 0
+	}
+	over := lipgloss.Height(r.panel(0)) - r.height
+	if over <= 0 {
+		return 0
+	}
+	// The cut table gains a line saying which rows it shows.
+	return max(len(r.tableRows())-over-1, 1)
+}
+
+// panel renders the report, cutting the summary table to fit rows when
+// fit is positive.
+func (r reportsModel) panel(fit int) string {
+	w := r.width - 4
+
 	// Mode tabs
-	dailyTab := inactiveTabStyle.Render("Daily")
-	weeklyTab := inactiveTabStyle.Render("Weekly")
-	if r.mode == reportDaily {
-		dailyTab = activeTabStyle.Render("Daily")
-	} else {
-		weeklyTab = activeTabStyle.Render("Weekly")
+	var tabs []string
+	for mode, name := range reportModeNames {
+		if reportMode(mode) == r.mode {
+			tabs = append(tabs, activeTabStyle.Render(name))
+		} else {
+			tabs = append(tabs, inactiveTabStyle.Render(name))
+		}
 	}
-	modeTabs := lipgloss.JoinHorizontal(lipgloss.Bottom, dailyTab, weeklyTab)
+	modeTabs := lipgloss.JoinHorizontal(lipgloss.Bottom, tabs...)
 
 	// Date range label
-	from, to := r.dateRange()
-	dateLabel := mutedStyle.Render(fmt.Sprintf("%s — %s", from.Format("Jan 02"), to.Add(-24*time.Hour).Format("Jan 02, 2006")))
+	dateLabel := mutedStyle.Render(r.rangeLabel())
+	if r.hideEmpty && (r.mode == reportDaily || r.mode == reportWeekly) {
+		dateLabel += mutedStyle.Render(" · empty days hidden")
+	}
+
+	filterLabel := mutedStyle.Render("All projects")
+	if r.projectID != 0 {
+		filterLabel = highlightStyle.Render(r.projectName)
+	}
 
 	header := lipgloss.JoinHorizontal(lipgloss.Bottom,
-		titleStyle.Render("Reports"), "  ", modeTabs, "  ", dateLabel,
+		titleStyle.Render("Reports"), "  ", modeTabs, "  ", dateLabel, "  ", filterLabel,
 	)
+	if lipgloss.Width(header) > w-6 {
+		// Too many mode tabs to fit the range beside them.
+		header = lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.JoinHorizontal(lipgloss.Bottom, titleStyle.Render("Reports"), "  ", modeTabs),
+			dateLabel+"  "+filterLabel,
+		)
+	}
 
 	// Chart
-	chartView := r.chart.View()
+	chartView := r.chartView()
 
-	// Summary table
-	tableView := r.renderSummaryTable(w)
+	// Summary table; trends and heatmaps span too long to list day by day
+	// and the tags mode has its own.
+	tableView := r.renderSummaryTable(w, fit)
+	if r.mode != reportDaily && r.mode != reportWeekly {
+		tableView = ""
+	}
+	if len(r.summaries) > 0 {
+		tableView += "\n\n" + r.billableLine()
+		if rev := r.renderRevenue(); rev != "" {
+			tableView += "\n" + rev
+		}
+	}
 
 	// Legend
-	legend := r.renderLegend()
+	legend := r.legendView()
 
-	nav := mutedStyle.Render("  ←/→: navigate  tab: switch mode")
+	if rec := r.renderRecords(); rec != "" {
+		tableView += "\n\n" + rec
+	}
+	tableView = strings.TrimPrefix(tableView, "\n\n")
+
+	nav := mutedStyle.Render("  ←/→: navigate  m: switch mode  f: filter project  z: hide empty  e: export  y: copy")
 
 	return panelStyle.Width(w).Render(
 		lipgloss.JoinVertical(lipgloss.Left,
@@ -193,23 +675,116 @@ func (r reportsModel) view() string {
 	)
 }
 
-func (r reportsModel) renderSummaryTable(w int) string {
-	if len(r.summaries) == 0 {
+func (r reportsModel) renderSummaryTable(w, fit int) string {
+	summaries := r.tableRows()
+	if len(summaries) == 0 {
 		return mutedStyle.Render("  No data for this period")
 	}
 
 	var rows []string
-	headerRow := mutedStyle.Render(fmt.Sprintf("  %-12s %-20s %10s %8s", "Date", "Project", "Duration", "Entries"))
-	rows = append(rows, headerRow)
+	rows = append(rows, mutedStyle.Render(reportTableHeader))
 	rows = append(rows, mutedStyle.Render("  "+strings.Repeat("─", min(w-6, 54))))
 
-	for _, s := range r.summaries {
-		colorDot := lipgloss.NewStyle().Foreground(lipgloss.Color(s.ProjectColor)).Render("●")
-		rows = append(rows, fmt.Sprintf("  %-12s %s %-18s %10s %8d",
-			s.Date, colorDot, s.ProjectName, formatSeconds(s.TotalSeconds), s.EntryCount,
-		))
+	first, last := 0, len(summaries)
+	if fit > 0 && fit < last {
+		first = min(r.scroll, last-fit)
+		last = first + fit
+	}
+	for _, s := range summaries[first:last] {
+		rows = append(rows, reportTableRow(projectMarker(s.ProjectID, s.ProjectColor), s))
+	}
+	if last-first < len(summaries) {
+		rows = append(rows, mutedStyle.Render(fmt.Sprintf("  rows %d–%d of %d · ↑/↓: scroll", first+1, last, len(summaries))))
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+var reportTableHeader = fmt.Sprintf("  %-12s %-20s %10s %8s", "Date", "Project", "Duration", "Entries")
+
+func reportTableRow(dot string, s store.DailySummary) string {
+	return fmt.Sprintf("  %-12s %s %-18s %10s %8d",
+		s.Date, dot, s.ProjectName, formatSeconds(s.TotalSeconds), s.EntryCount,
+	)
+}
+
+// billableLine splits the range total into billable and non-billable time.
+func (r reportsModel) billableLine() string {
+	return mutedStyle.Render(fmt.Sprintf("  Billable %s · Non-billable %s",
+		formatSeconds(r.billable), formatSeconds(r.nonBillable)))
+}
+
+// renderRevenue lists earnings for projects with an hourly rate, or is empty
+// when none of the range's billable time is rated.
+func (r reportsModel) renderRevenue() string {
+	var rows []string
+	var total float64
+	for _, p := range r.revenue {
+		if p.HourlyRate == 0 {
+			continue
+		}
+		total += p.Revenue
+		rows = append(rows, fmt.Sprintf("  %s %-18s %10s × %-8s %10.2f",
+			projectMarker(p.ProjectID, p.ProjectColor), p.ProjectName,
+			formatSeconds(p.BillableSeconds), formatRate(p.HourlyRate), p.Revenue))
 	}
+	if len(rows) == 0 {
+		return ""
+	}
+	rows = append(rows, mutedStyle.Render(fmt.Sprintf("  %-42s %10.2f", "Revenue", total)))
+	return strings.Join(rows, "\n")
+}
+
+// renderRecords shows all-time bests, or nothing before anything is tracked.
+func (r reportsModel) renderRecords() string {
+	rec := r.records
+	if rec.longest == nil {
+		return ""
+	}
+	row := func(label, value, detail string) string {
+		return fmt.Sprintf("  %-16s %s  %s", label, highlightStyle.Render(value), mutedStyle.Render(detail))
+	}
+	rows := []string{
+		titleStyle.Render("  Records"),
+		row("Longest session", formatSeconds(rec.longest.Duration),
+			rec.longestProject+" · "+rec.longest.StartTime.Local().Format("Jan 02, 2006")),
+	}
+	if day, err := time.Parse("2006-01-02", rec.bestDay); err == nil {
+		rows = append(rows, row("Biggest day", formatSeconds(rec.bestDaySecs), day.Format("Mon Jan 02, 2006")))
+	}
+	if rec.topProject != nil {
+		rows = append(rows, row("Most tracked", formatSeconds(rec.topSecs), rec.topProject.Name))
+	}
+	if rec.avgWorking > 0 {
+		rows = append(rows, row("Daily average", formatSeconds(int64(rec.avgWorking))+"/day",
+			fmt.Sprintf("per tracked day · %s per calendar day, last %d days", formatSeconds(int64(rec.avgCalendar)), averageDays)))
+	}
+	if rec.goalDays > 0 {
+		rows = append(rows, row("Goal met", fmt.Sprintf("%d of %d days", rec.goalMetDays, rec.goalDays),
+			fmt.Sprintf("last %d days, %s goal", averageDays, formatShort(r.dailyGoal))))
+	}
+	return strings.Join(rows, "\n")
+}
 
+func (r reportsModel) rangeLabel() string {
+	from, to := r.dateRange()
+	return fmt.Sprintf("%s — %s", from.Format("Jan 02"), to.AddDate(0, 0, -1).Format("Jan 02, 2006"))
+}
+
+// summaryText is the summary table for the selected range as plain text.
+func (r reportsModel) summaryText() string {
+	rows := []string{r.rangeLabel()}
+	if r.projectID != 0 {
+		rows[0] += " · " + r.projectName
+	}
+	summaries := r.tableRows()
+	if len(summaries) == 0 {
+		return strings.Join(append(rows, "  No data for this period"), "\n")
+	}
+	rows = append(rows, reportTableHeader, "  "+strings.Repeat("─", 54))
+	for _, s := range summaries {
+		rows = append(rows, reportTableRow(projectGlyph(s.ProjectID), s))
+	}
 	return strings.Join(rows, "\n")
 }
 
@@ -222,11 +797,14 @@ func (r reportsModel) renderLegend() string {
 			continue
 		}
 		seen[s.ProjectID] = true
-		dot := lipgloss.NewStyle().Foreground(lipgloss.Color(s.ProjectColor)).Render("●")
+		dot := projectMarker(s.ProjectID, s.ProjectColor)
 		items = append(items, fmt.Sprintf("%s %s", dot, s.ProjectName))
 	}
 	if len(items) == 0 {
 		return ""
 	}
+	if r.dailyGoal > 0 {
+		items = append(items, successStyle.Render("✓")+mutedStyle.Render(" goal met ("+formatShort(r.dailyGoal)+")"))
+	}
 	return "  " + strings.Join(items, "  ")
 }
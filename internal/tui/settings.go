@@ -1,13 +1,19 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/sadopc/trackr/internal/export"
 	"github.com/sadopc/trackr/internal/store"
 )
 
@@ -19,31 +25,76 @@ type settingsModel struct {
 	settings   []store.Setting
 	formActive bool
 	form       *huh.Form
+	formType   string // "settings", "category", "prune", "import", "snapshot" or "snapshot-replace"
 
 	// Form values as pointers (survive value copies)
 	pomodoroWork      *string
 	pomodoroBreak     *string
 	pomodoroLongBreak *string
 	pomodoroCount     *string
+	autoStartBreak    *string
+	autoStartWork     *string
 	idleTimeout       *string
 	idleAction        *string
+	idleSource        *string
 	dailyGoal         *string
+	weeklyGoal        *string
 	weekStart         *string
+	recentCount       *string
+	accessible        *string
+	sound             *string
+	terminalTitle     *string
+	multiTimer        *string
+	durationFormat    *string
+	exportTemplate    *string
+	categoryFrom      *string
+	categoryTo        *string
+	pruneMonths       *string
+	pruneConfirm      *bool
+	importPath        *string
+	snapshotPath      *string
+	snapshotReplace   *bool
 }
 
+// settingsFileName is where settings are exported, in the home directory.
+const settingsFileName = "trackr-settings.json"
+
 func newSettingsModel(s *store.Store) settingsModel {
-	pw, pb, plb, pc := "", "", "", ""
-	it, ia, dg, ws := "", "", "", ""
+	pw, pb, plb, pc, asb, asw := "", "", "", "", "", ""
+	it, ia, dg, wg, ws, am := "", "", "", "", "", ""
+	cf, ct, rc, pm, snd, isrc := "", "", "", "12", "", ""
+	tt, et, mt, df := "", "", "", ""
+	confirm := false
+	importPath, snapshotPath := "", ""
+	replace := false
 	return settingsModel{
 		store:             s,
 		pomodoroWork:      &pw,
 		pomodoroBreak:     &pb,
 		pomodoroLongBreak: &plb,
 		pomodoroCount:     &pc,
+		autoStartBreak:    &asb,
+		autoStartWork:     &asw,
 		idleTimeout:       &it,
 		idleAction:        &ia,
+		idleSource:        &isrc,
 		dailyGoal:         &dg,
+		weeklyGoal:        &wg,
 		weekStart:         &ws,
+		recentCount:       &rc,
+		accessible:        &am,
+		sound:             &snd,
+		terminalTitle:     &tt,
+		multiTimer:        &mt,
+		durationFormat:    &df,
+		exportTemplate:    &et,
+		categoryFrom:      &cf,
+		categoryTo:        &ct,
+		pruneMonths:       &pm,
+		pruneConfirm:      &confirm,
+		importPath:        &importPath,
+		snapshotPath:      &snapshotPath,
+		snapshotReplace:   &replace,
 	}
 }
 
@@ -77,6 +128,19 @@ func (s settingsModel) update(msg tea.Msg) (settingsModel, tea.Cmd) {
 		switch {
 		case key.Matches(msg, keys.Enter), key.Matches(msg, keys.New):
 			return s.showForm()
+		case key.Matches(msg, keys.Categories):
+			return s.showCategoryForm()
+		case key.Matches(msg, keys.Prune):
+			return s.showPruneForm()
+		case key.Matches(msg, keys.Compact):
+			return s, s.compact()
+		case key.Matches(msg, keys.SaveConfig):
+			home, _ := os.UserHomeDir()
+			return s, s.exportSettings(filepath.Join(home, settingsFileName))
+		case key.Matches(msg, keys.LoadConfig):
+			return s.showImportForm()
+		case key.Matches(msg, keys.Snapshot):
+			return s.showSnapshotForm()
 		}
 	}
 	return s, nil
@@ -88,38 +152,305 @@ func (s settingsModel) showForm() (settingsModel, tea.Cmd) {
 	*s.pomodoroBreak = secsToMin(s.getVal("pomodoro_break", "300"))
 	*s.pomodoroLongBreak = secsToMin(s.getVal("pomodoro_long_break", "900"))
 	*s.pomodoroCount = s.getVal("pomodoro_count", "4")
+	*s.autoStartBreak = s.getVal("pomodoro_auto_start_break", "true")
+	*s.autoStartWork = s.getVal("pomodoro_auto_start_work", "true")
 	*s.idleTimeout = secsToMin(s.getVal("idle_timeout", "300"))
 	*s.idleAction = s.getVal("idle_action", "pause")
+	*s.idleSource = s.getVal("idle_source", "trackr")
 	*s.dailyGoal = secsToHours(s.getVal("daily_goal", "28800"))
+	*s.weeklyGoal = secsToHours(strconv.FormatInt(loadWeeklyGoal(s.store), 10))
 	*s.weekStart = s.getVal("week_start", "monday")
+	*s.recentCount = s.getVal("dashboard_recent_count", "5")
+	*s.accessible = s.getVal("accessible_mode", "false")
+	*s.sound = s.getVal("sound", "true")
+	*s.terminalTitle = s.getVal("set_terminal_title", "false")
+	*s.multiTimer = s.getVal("multi_timer", "false")
+	*s.durationFormat = s.getVal("duration_format", export.DurationHMS)
+	*s.exportTemplate = s.getVal("export_filename_template", export.DefaultFileNameTemplate)
 
 	s.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().Title("Pomodoro work (min)").Value(s.pomodoroWork),
-			huh.NewInput().Title("Pomodoro break (min)").Value(s.pomodoroBreak),
-			huh.NewInput().Title("Long break (min)").Value(s.pomodoroLongBreak),
-			huh.NewInput().Title("Pomodoros before long break").Value(s.pomodoroCount),
+			huh.NewInput().Title("Pomodoro work (min)").Value(s.pomodoroWork).Validate(validatePositiveInt),
+			huh.NewInput().Title("Pomodoro break (min)").Value(s.pomodoroBreak).Validate(validatePositiveInt),
+			huh.NewInput().Title("Long break (min)").Value(s.pomodoroLongBreak).Validate(validatePositiveInt),
+			huh.NewInput().Title("Pomodoros before long break").Value(s.pomodoroCount).Validate(validatePositiveInt),
+			huh.NewSelect[string]().Title("Auto-start breaks").
+				Options(
+					huh.NewOption("Yes", "true"),
+					huh.NewOption("No", "false"),
+				).Value(s.autoStartBreak),
+			huh.NewSelect[string]().Title("Auto-start work after breaks").
+				Options(
+					huh.NewOption("Yes", "true"),
+					huh.NewOption("No", "false"),
+				).Value(s.autoStartWork),
 		).Title("Pomodoro"),
 		huh.NewGroup(
-			huh.NewInput().Title("Idle timeout (min)").Value(s.idleTimeout),
+			huh.NewInput().Title("Idle timeout (min)").Value(s.idleTimeout).Validate(validatePositiveInt),
 			huh.NewSelect[string]().Title("Idle action").
 				Options(
 					huh.NewOption("Pause", "pause"),
 					huh.NewOption("Stop", "stop"),
 				).Value(s.idleAction),
-			huh.NewInput().Title("Daily goal (hours)").Value(s.dailyGoal),
+			huh.NewSelect[string]().Title("Idle means").
+				Options(
+					huh.NewOption("No keys pressed in trackr", "trackr"),
+					huh.NewOption("No input anywhere (X11 with xprintidle, macOS, Windows)", "system"),
+				).Value(s.idleSource),
+			huh.NewInput().Title("Daily goal (hours)").Value(s.dailyGoal).Validate(validatePositiveFloat),
+			huh.NewInput().Title("Weekly goal (hours, 0 to hide)").Value(s.weeklyGoal).Validate(validateNonNegativeFloat),
 			huh.NewSelect[string]().Title("Week starts on").
 				Options(
 					huh.NewOption("Monday", "monday"),
 					huh.NewOption("Sunday", "sunday"),
 				).Value(s.weekStart),
+			huh.NewInput().Title("Recent entries on dashboard (max 50)").Value(s.recentCount).Validate(validatePositiveInt),
+			huh.NewSelect[string]().Title("Project markers").
+				Options(
+					huh.NewOption("Color dots", "false"),
+					huh.NewOption("Distinct glyphs (color-blind friendly)", "true"),
+				).Value(s.accessible),
+			huh.NewSelect[string]().Title("Sound (bell when a pomodoro phase ends)").
+				Options(
+					huh.NewOption("On", "true"),
+					huh.NewOption("Off", "false"),
+				).Value(s.sound),
+			huh.NewSelect[string]().Title("Show the running timer in the terminal title").
+				Options(
+					huh.NewOption("Off", "false"),
+					huh.NewOption("On", "true"),
+				).Value(s.terminalTitle),
+			huh.NewSelect[string]().Title("Running timers").
+				Options(
+					huh.NewOption("One at a time", "false"),
+					huh.NewOption("Several at once (s starts another)", "true"),
+				).Value(s.multiTimer),
+			huh.NewSelect[string]().Title("Show durations as").
+				Options(
+					huh.NewOption("01:30:00", export.DurationHMS),
+					huh.NewOption("1h30m", export.DurationCompact),
+					huh.NewOption("1.5h", export.DurationDecimal),
+				).Value(s.durationFormat),
+			huh.NewInput().Title("Export file name ({date}, {range}, {format})").
+				Value(s.exportTemplate).Validate(validateFileTemplate),
 		).Title("General"),
 	).WithShowHelp(true).WithShowErrors(true)
 
 	s.formActive = true
+	s.formType = "settings"
+	return s, s.form.Init()
+}
+
+// showCategoryForm opens a form to rename a project category. Renaming to a
+// category that is already in use merges the two.
+func (s settingsModel) showCategoryForm() (settingsModel, tea.Cmd) {
+	cats, err := s.store.ListCategories()
+	if err != nil {
+		return s, errorStatus(err)
+	}
+	if len(cats) == 0 {
+		return s, func() tea.Msg { return statusMsg{text: "No categories in use"} }
+	}
+	options := make([]huh.Option[string], len(cats))
+	for i, c := range cats {
+		options[i] = huh.NewOption(c, c)
+	}
+	*s.categoryFrom = cats[0]
+	*s.categoryTo = ""
+
+	s.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Category").Options(options...).Value(s.categoryFrom),
+			huh.NewInput().Title("Rename to (an existing name merges)").Value(s.categoryTo).
+				Validate(func(v string) error {
+					if strings.TrimSpace(v) == "" {
+						return errors.New("enter a category name")
+					}
+					return nil
+				}),
+		).Title("Categories"),
+	).WithShowHelp(true).WithShowErrors(true)
+
+	s.formActive = true
+	s.formType = "category"
+	return s, s.form.Init()
+}
+
+// renameCategory applies the category form.
+func (s settingsModel) renameCategory() tea.Cmd {
+	to := strings.TrimSpace(*s.categoryTo)
+	n, err := s.store.RenameCategory(*s.categoryFrom, to)
+	if err != nil {
+		return errorStatus(err)
+	}
+	return func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("Moved %d project(s) from %q to %q", n, *s.categoryFrom, to)}
+	}
+}
+
+// showPruneForm asks how many months of history to keep before deleting
+// older entries for good.
+func (s settingsModel) showPruneForm() (settingsModel, tea.Cmd) {
+	*s.pruneConfirm = false
+
+	s.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("Delete entries older than (months)").Value(s.pruneMonths).Validate(validatePositiveInt),
+			huh.NewConfirm().Title("Delete them permanently? This can't be undone.").
+				Affirmative("Delete").Negative("Cancel").Value(s.pruneConfirm),
+		).Title("Delete old entries"),
+	).WithShowHelp(true).WithShowErrors(true)
+
+	s.formActive = true
+	s.formType = "prune"
 	return s, s.form.Init()
 }
 
+// pruneEntries applies the prune form.
+func (s settingsModel) pruneEntries() tea.Cmd {
+	if !*s.pruneConfirm {
+		return func() tea.Msg { return statusMsg{text: "No entries deleted"} }
+	}
+	months, _ := strconv.Atoi(strings.TrimSpace(*s.pruneMonths))
+	n, err := s.store.PruneOldEntries(time.Now().AddDate(0, -months, 0))
+	if err != nil {
+		return errorStatus(err)
+	}
+	return func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("Deleted %d entries older than %d months", n, months)}
+	}
+}
+
+// exportSettings writes every setting to path as JSON.
+func (s settingsModel) exportSettings(path string) tea.Cmd {
+	return func() tea.Msg {
+		settings, err := s.store.ExportSettings()
+		if err == nil {
+			err = export.SettingsToFile(settings, path)
+		}
+		if err != nil {
+			return errorStatus(err)()
+		}
+		return statusMsg{text: fmt.Sprintf("Exported %d settings to %s", len(settings), path)}
+	}
+}
+
+// showImportForm asks for a settings file to import, defaulting to the
+// one exportSettings writes.
+func (s settingsModel) showImportForm() (settingsModel, tea.Cmd) {
+	home, _ := os.UserHomeDir()
+	*s.importPath = filepath.Join(home, settingsFileName)
+
+	s.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("Settings file").Value(s.importPath).
+				Validate(func(v string) error {
+					if strings.TrimSpace(v) == "" {
+						return errors.New("enter a file path")
+					}
+					return nil
+				}),
+		).Title("Import settings"),
+	).WithShowHelp(true).WithShowErrors(true)
+
+	s.formActive = true
+	s.formType = "import"
+	return s, s.form.Init()
+}
+
+// importSettings applies the settings in the file at path over the
+// current ones.
+func (s settingsModel) importSettings(path string) tea.Cmd {
+	settings, err := export.SettingsFromFile(path)
+	if err == nil {
+		err = s.store.ImportSettings(settings)
+	}
+	if err != nil {
+		return errorStatus(err)
+	}
+	loadAccessibleMode(s.store)
+	loadTerminalTitle(s.store)
+	loadMultiTimer(s.store)
+	loadDurationFormat(s.store)
+	return tea.Batch(s.refresh(), func() tea.Msg {
+		return statusMsg{text: fmt.Sprintf("Imported %d settings from %s", len(settings), path)}
+	})
+}
+
+// showSnapshotForm asks where to write a copy of the database, defaulting
+// to a dated file in the home directory.
+func (s settingsModel) showSnapshotForm() (settingsModel, tea.Cmd) {
+	home, _ := os.UserHomeDir()
+	*s.snapshotPath = filepath.Join(home, "trackr-"+time.Now().Format("2006-01-02")+".db")
+
+	s.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("Database file").Value(s.snapshotPath).
+				Validate(func(v string) error {
+					if strings.TrimSpace(v) == "" {
+						return errors.New("enter a file path")
+					}
+					return nil
+				}),
+		).Title("Export database"),
+	).WithShowHelp(true).WithShowErrors(true)
+
+	s.formActive = true
+	s.formType = "snapshot"
+	return s, s.form.Init()
+}
+
+// showSnapshotReplaceForm asks before overwriting an existing file.
+func (s settingsModel) showSnapshotReplaceForm(path string) (settingsModel, tea.Cmd) {
+	*s.snapshotReplace = false
+
+	s.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().Title(path + " already exists. Replace it?").
+				Affirmative("Replace").Negative("Cancel").Value(s.snapshotReplace),
+		).Title("Export database"),
+	).WithShowHelp(true).WithShowErrors(true)
+
+	s.formActive = true
+	s.formType = "snapshot-replace"
+	return s, s.form.Init()
+}
+
+// snapshot writes a standalone copy of the database to path, first
+// removing the file there when replace is set.
+func (s settingsModel) snapshot(path string, replace bool) tea.Cmd {
+	return func() tea.Msg {
+		if replace {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return errorStatus(err)()
+			}
+		}
+		if err := s.store.SnapshotTo(path); err != nil {
+			return errorStatus(err)()
+		}
+		size := ""
+		if fi, err := os.Stat(path); err == nil {
+			size = " (" + formatBytes(fi.Size()) + ")"
+		}
+		return statusMsg{text: "Exported database to " + path + size}
+	}
+}
+
+// compact reclaims unused space in the database and reports the sizes.
+func (s settingsModel) compact() tea.Cmd {
+	return func() tea.Msg {
+		before, err := s.store.Size()
+		if err == nil {
+			err = s.store.Compact()
+		}
+		if err != nil {
+			return errorStatus(err)()
+		}
+		after, _ := s.store.Size()
+		return statusMsg{text: fmt.Sprintf("Compacted database: %s → %s", formatBytes(before), formatBytes(after))}
+	}
+}
+
 func (s settingsModel) updateForm(msg tea.Msg) (settingsModel, tea.Cmd) {
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		if msg.String() == "esc" {
@@ -136,22 +467,66 @@ func (s settingsModel) updateForm(msg tea.Msg) (settingsModel, tea.Cmd) {
 
 	if s.form.State == huh.StateCompleted {
 		s.formActive = false
-		s.saveSettings()
+		switch s.formType {
+		case "category":
+			return s, s.renameCategory()
+		case "prune":
+			return s, s.pruneEntries()
+		case "import":
+			return s, s.importSettings(strings.TrimSpace(*s.importPath))
+		case "snapshot":
+			path := strings.TrimSpace(*s.snapshotPath)
+			if _, err := os.Stat(path); err == nil {
+				return s.showSnapshotReplaceForm(path)
+			}
+			return s, s.snapshot(path, false)
+		case "snapshot-replace":
+			if !*s.snapshotReplace {
+				return s, func() tea.Msg { return statusMsg{text: "Database not exported"} }
+			}
+			return s, s.snapshot(strings.TrimSpace(*s.snapshotPath), true)
+		}
+		if err := s.saveSettings(); err != nil {
+			return s, tea.Batch(s.refresh(), errorStatus(err))
+		}
 		return s, s.refresh()
 	}
 
 	return s, cmd
 }
 
-func (s settingsModel) saveSettings() {
-	s.store.SetSetting("pomodoro_work", minToSecs(*s.pomodoroWork))
-	s.store.SetSetting("pomodoro_break", minToSecs(*s.pomodoroBreak))
-	s.store.SetSetting("pomodoro_long_break", minToSecs(*s.pomodoroLongBreak))
-	s.store.SetSetting("pomodoro_count", *s.pomodoroCount)
-	s.store.SetSetting("idle_timeout", minToSecs(*s.idleTimeout))
-	s.store.SetSetting("idle_action", *s.idleAction)
-	s.store.SetSetting("daily_goal", hoursToSecs(*s.dailyGoal))
-	s.store.SetSetting("week_start", *s.weekStart)
+// saveSettings writes the form values in one batch, so a failure leaves
+// the previous settings intact.
+func (s settingsModel) saveSettings() error {
+	err := s.store.SetSettings(map[string]string{
+		"pomodoro_work":             minToSecs(*s.pomodoroWork),
+		"pomodoro_break":            minToSecs(*s.pomodoroBreak),
+		"pomodoro_long_break":       minToSecs(*s.pomodoroLongBreak),
+		"pomodoro_count":            strings.TrimSpace(*s.pomodoroCount),
+		"pomodoro_auto_start_break": *s.autoStartBreak,
+		"pomodoro_auto_start_work":  *s.autoStartWork,
+		"idle_timeout":              minToSecs(*s.idleTimeout),
+		"idle_action":               *s.idleAction,
+		"idle_source":               *s.idleSource,
+		"daily_goal":                hoursToSecs(*s.dailyGoal),
+		"weekly_goal":               hoursToSecs(*s.weeklyGoal),
+		"week_start":                *s.weekStart,
+		"dashboard_recent_count":    strings.TrimSpace(*s.recentCount),
+		"accessible_mode":           *s.accessible,
+		"sound":                     *s.sound,
+		"set_terminal_title":        *s.terminalTitle,
+		"multi_timer":               *s.multiTimer,
+		"duration_format":           *s.durationFormat,
+		"export_filename_template":  strings.TrimSpace(*s.exportTemplate),
+	})
+	if err != nil {
+		return err
+	}
+	loadAccessibleMode(s.store)
+	loadTerminalTitle(s.store)
+	loadMultiTimer(s.store)
+	loadDurationFormat(s.store)
+	return nil
 }
 
 func (s settingsModel) getVal(k, fallback string) string {
@@ -174,7 +549,7 @@ func (s settingsModel) view() string {
 	}
 
 	title := titleStyle.Render("Settings")
-	hint := mutedStyle.Render("Press enter to edit settings")
+	hint := mutedStyle.Render("Press enter to edit settings · c: manage categories · D: delete old entries · C: compact database · E/I: export/import settings · B: export database")
 
 	var rows []string
 	rows = append(rows, title)
@@ -198,7 +573,7 @@ func formatSettingValue(k, v string) string {
 		if secs, err := strconv.Atoi(v); err == nil {
 			return fmt.Sprintf("%d min", secs/60)
 		}
-	case "daily_goal":
+	case "daily_goal", "weekly_goal":
 		if secs, err := strconv.Atoi(v); err == nil {
 			return fmt.Sprintf("%.1f hours", float64(secs)/3600)
 		}
@@ -206,6 +581,51 @@ func formatSettingValue(k, v string) string {
 	return v
 }
 
+func validatePositiveInt(s string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 1 {
+		return errors.New("enter a whole number of at least 1")
+	}
+	return nil
+}
+
+func validatePositiveFloat(s string) error {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f <= 0 {
+		return errors.New("enter a number greater than 0")
+	}
+	return nil
+}
+
+func validateNonNegativeFloat(s string) error {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 {
+		return errors.New("enter a number, 0 or more")
+	}
+	return nil
+}
+
+// validateFileTemplate checks that an export file name template expands to
+// a usable name.
+func validateFileTemplate(s string) error {
+	_, err := export.FileName(s, "2006-01-02", "2006-01-02_2006-01-07", "csv")
+	if err != nil {
+		return errors.New("the name would be empty")
+	}
+	return nil
+}
+
+// formatBytes renders a size in B, KB or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
 func secsToMin(s string) string {
 	if secs, err := strconv.Atoi(s); err == nil {
 		return strconv.Itoa(secs / 60)
@@ -214,7 +634,7 @@ func secsToMin(s string) string {
 }
 
 func minToSecs(s string) string {
-	if mins, err := strconv.Atoi(s); err == nil {
+	if mins, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
 		return strconv.Itoa(mins * 60)
 	}
 	return s
@@ -228,7 +648,7 @@ func secsToHours(s string) string {
 }
 
 func hoursToSecs(s string) string {
-	if hours, err := strconv.ParseFloat(s, 64); err == nil {
+	if hours, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
 		return strconv.Itoa(int(hours * 3600))
 	}
 	return s
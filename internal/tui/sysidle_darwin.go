@@ -0,0 +1,37 @@
+//go:build darwin
+
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// systemIdleSeconds reads HIDIdleTime, in nanoseconds, from the IOKit
+// registry.
+func systemIdleSeconds() (int, bool) {
+	out, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4").Output()
+	if err != nil {
+		return 0, false
+	}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.Contains(line, `"HIDIdleTime"`) {
+			continue
+		}
+		_, v, ok := strings.Cut(line, "=")
+		if !ok {
+			break
+		}
+		ns, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			break
+		}
+		return int(ns / 1e9), true
+	}
+	return 0, false
+}
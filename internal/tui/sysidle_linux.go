@@ -0,0 +1,27 @@
+//go:build linux
+
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// systemIdleSeconds asks xprintidle how long the X11 session has had no
+// input. It reports false without a display or when xprintidle is missing.
+func systemIdleSeconds() (int, bool) {
+	if os.Getenv("DISPLAY") == "" {
+		return 0, false
+	}
+	out, err := exec.Command("xprintidle").Output()
+	if err != nil {
+		return 0, false
+	}
+	ms, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, false
+	}
+	return ms / 1000, true
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package tui
+
+// systemIdleSeconds is unsupported here; idle detection falls back to
+// keys pressed in trackr.
+func systemIdleSeconds() (int, bool) {
+	return 0, false
+}
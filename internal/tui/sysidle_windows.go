@@ -0,0 +1,32 @@
+//go:build windows
+
+package tui
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32           = syscall.NewLazyDLL("user32.dll")
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	getLastInputInfo = user32.NewProc("GetLastInputInfo")
+	getTickCount     = kernel32.NewProc("GetTickCount")
+)
+
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// systemIdleSeconds compares the tick count of the last input event with
+// the current one.
+func systemIdleSeconds() (int, bool) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if r, _, _ := getLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 0, false
+	}
+	now, _, _ := getTickCount.Call()
+	// Both wrap every 49.7 days; uint32 subtraction handles that.
+	return int((uint32(now) - info.dwTime) / 1000), true
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeHint lists example inputs accepted by parseFuzzyTime.
+const timeHint = "e.g. 14:30, 2:30pm, yesterday 9am, now-1h, 45m"
+
+// parseFuzzyTime reads a point in time the way a person would type it,
+// relative to now:
+//
+//	14:30, 2:30pm, 9am         today at that time
+//	yesterday 9am, today 14:00 that day at that time
+//	now, now-1h, now+15m       now, shifted by a duration
+//	45m, 1h30m, 45m ago        that long before now
+//	2006-01-02 15:04, RFC3339  an absolute time
+func parseFuzzyTime(s string, now time.Time) (time.Time, error) {
+	in := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	now = now.Local()
+	fail := fmt.Errorf("can't read %q (%s)", strings.TrimSpace(s), timeHint)
+	if in == "" {
+		return time.Time{}, fail
+	}
+
+	if in == "now" {
+		return now, nil
+	}
+	if rest, ok := strings.CutPrefix(in, "now"); ok {
+		rest = strings.ReplaceAll(rest, " ", "")
+		sign := time.Duration(1)
+		switch {
+		case strings.HasPrefix(rest, "-"):
+			sign = -1
+		case !strings.HasPrefix(rest, "+"):
+			return time.Time{}, fail
+		}
+		d, err := time.ParseDuration(rest[1:])
+		if err != nil || d < 0 {
+			return time.Time{}, fail
+		}
+		return now.Add(sign * d), nil
+	}
+
+	if d, err := time.ParseDuration(strings.TrimSuffix(strings.TrimSuffix(in, "ago"), " ")); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	day, clock := now, in
+	if rest, ok := strings.CutPrefix(in, "yesterday "); ok {
+		day, clock = now.AddDate(0, 0, -1), rest
+	} else if rest, ok := strings.CutPrefix(in, "today "); ok {
+		clock = rest
+	}
+	if h, m, ok := parseTimeOfDay(clock); ok {
+		return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, time.Local), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", in, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(s)); err == nil {
+		return t.Local(), nil
+	}
+	return time.Time{}, fail
+}
+
+// parseTimeOfDay reads a clock time in 24-hour ("14:30", "9") or 12-hour
+// ("2:30pm", "9 am") form.
+func parseTimeOfDay(s string) (hour, minute int, ok bool) {
+	s = strings.ReplaceAll(strings.ToLower(s), " ", "")
+	suffix := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		s, suffix = s[:len(s)-2], s[len(s)-2:]
+	}
+	hs, ms, hasMin := strings.Cut(s, ":")
+	hour, err := strconv.Atoi(hs)
+	if err != nil || hour < 0 || len(hs) > 2 {
+		return 0, 0, false
+	}
+	if hasMin {
+		if minute, err = strconv.Atoi(ms); err != nil || len(ms) != 2 || minute < 0 || minute > 59 {
+			return 0, 0, false
+		}
+	}
+	if suffix == "" {
+		return hour, minute, hour <= 23
+	}
+	if hour < 1 || hour > 12 {
+		return 0, 0, false
+	}
+	hour %= 12
+	if suffix == "pm" {
+		hour += 12
+	}
+	return hour, minute, true
+}
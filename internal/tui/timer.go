@@ -3,6 +3,8 @@ package tui
 import (
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/sadopc/trackr/internal/store"
 )
 
@@ -35,41 +37,161 @@ type timerModel struct {
 	lastActivity time.Time
 	idleTimeout  time.Duration
 	isIdle       bool
+	manualPause  bool // paused by the user; activity must not auto-resume
+
+	// idleProbe reports OS-wide idle seconds when the idle_source setting
+	// is "system"; nil watches keys in trackr only.
+	idleProbe       func() (int, bool)
+	lastSystemCheck time.Time
 }
 
+// systemIdleInterval spaces out OS idle queries, which may run a command.
+const systemIdleInterval = 5 * time.Second
+
 func newTimerModel(s *store.Store) timerModel {
-	return timerModel{
+	t := timerModel{
 		store:        s,
 		state:        timerStopped,
 		lastActivity: time.Now(),
-		idleTimeout:  5 * time.Minute,
+		idleTimeout:  s.GetSettingDuration("idle_timeout", 5*time.Minute),
+	}
+	if v, _ := s.GetSetting("idle_source"); v == "system" {
+		t.idleProbe = systemIdleSeconds
 	}
+	return t
 }
 
 func (t *timerModel) start(projectID int64, projectName string, taskID *int64, taskName string) error {
-	entry, err := t.store.StartEntry(projectID, taskID)
+	return t.startAt(projectID, projectName, taskID, taskName, time.Now())
+}
+
+// startAt starts the timer as if it had been running since start.
+func (t *timerModel) startAt(projectID int64, projectName string, taskID *int64, taskName string, start time.Time) error {
+	entry, err := t.store.StartEntryAt(projectID, taskID, start)
 	if err != nil {
 		return err
 	}
+	t.adopt(entry, projectName, taskName)
+	return nil
+}
+
+// adopt makes entry the running timer. The start time always comes from the
+// stored row, so elapsed agrees with what StopEntry will record.
+func (t *timerModel) adopt(entry *store.TimeEntry, projectName, taskName string) {
 	t.state = timerRunning
-	t.startTime = time.Now()
-	t.elapsed = 0
+	t.startTime = entry.StartTime
 	t.pauseGap = 0
-	t.projectID = projectID
+	t.projectID = entry.ProjectID
 	t.projectName = projectName
-	t.taskID = taskID
+	t.taskID = entry.TaskID
 	t.taskName = taskName
 	t.entryID = entry.ID
 	t.lastActivity = time.Now()
 	t.isIdle = false
+	t.manualPause = false
+	t.elapsed = t.currentElapsed()
+}
+
+// restore adopts the store's running entry, if any, so a timer survives a
+// restart. A timer that was paused comes back paused with the elapsed it
+// had then; time spent closed while running still counts.
+func (t *timerModel) restore() error {
+	entry, err := t.store.GetRunningEntry()
+	if err != nil || entry == nil {
+		return err
+	}
+	return t.restoreEntry(entry)
+}
+
+// restoreEntry adopts a running entry along with its saved pause state.
+func (t *timerModel) restoreEntry(entry *store.TimeEntry) error {
+	project, err := t.store.GetProject(entry.ProjectID)
+	if err != nil {
+		return err
+	}
+	var taskName string
+	if entry.TaskID != nil {
+		if task, err := t.store.GetTask(*entry.TaskID); err == nil {
+			taskName = task.Name
+		}
+	}
+	st, err := t.store.GetTimerState(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	t.adopt(entry, project.Name, taskName)
+	if st != nil {
+		t.pauseGap = time.Duration(st.PauseGap) * time.Second
+		if st.PausedAt != nil {
+			t.state = timerPaused
+			t.pausedAt = *st.PausedAt
+			t.manualPause = true
+		}
+	}
+	t.elapsed = t.currentElapsed()
 	return nil
 }
 
+// reconcile brings the timer in line with the store after the running entry
+// may have changed elsewhere, e.g. a `trackr stop` from the command line. A
+// timer whose entry was stopped stops too; a different running entry is
+// adopted.
+func (t *timerModel) reconcile() error {
+	entry, err := t.store.GetRunningEntry()
+	if err != nil {
+		return err
+	}
+	switch {
+	case entry == nil:
+		t.state = timerStopped
+		t.elapsed = 0
+	case !t.running() || entry.ID != t.entryID:
+		return t.restore()
+	}
+	return nil
+}
+
+// refreshLabels re-reads the project and task names of the running entry,
+// after it was reassigned.
+func (t *timerModel) refreshLabels() {
+	entry, err := t.store.GetEntry(t.entryID)
+	if err != nil {
+		return
+	}
+	t.projectID = entry.ProjectID
+	if p, err := t.store.GetProject(entry.ProjectID); err == nil {
+		t.projectName = p.Name
+	}
+	t.taskID = entry.TaskID
+	t.taskName = ""
+	if entry.TaskID != nil {
+		if task, err := t.store.GetTask(*entry.TaskID); err == nil {
+			t.taskName = task.Name
+		}
+	}
+}
+
+// saveState persists the pause bookkeeping so restore can pick it up.
+func (t *timerModel) saveState() {
+	st := store.TimerState{EntryID: t.entryID, PauseGap: int64(t.pauseGap / time.Second)}
+	if t.state == timerPaused {
+		pausedAt := t.pausedAt
+		st.PausedAt = &pausedAt
+	}
+	t.store.SaveTimerState(st)
+}
+
 func (t *timerModel) stop() (*store.TimeEntry, error) {
+	return t.stopAt(time.Now())
+}
+
+// stopAt stops the timer as if it had been stopped at end.
+func (t *timerModel) stopAt(end time.Time) (*store.TimeEntry, error) {
 	if t.state == timerStopped {
 		return nil, nil
 	}
-	entry, err := t.store.StopEntry(t.entryID)
+	entry, err := t.store.StopEntryAt(t.entryID, end)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +206,7 @@ func (t *timerModel) pause() {
 	}
 	t.state = timerPaused
 	t.pausedAt = time.Now()
+	t.saveState()
 }
 
 func (t *timerModel) resume() {
@@ -93,13 +216,18 @@ func (t *timerModel) resume() {
 	t.pauseGap += time.Since(t.pausedAt)
 	t.state = timerRunning
 	t.isIdle = false
+	t.manualPause = false
 	t.lastActivity = time.Now()
+	t.saveState()
 }
 
+// toggle is the user-initiated pause/resume. A pause made here stays paused
+// until toggled again, unlike an idle auto-pause.
 func (t *timerModel) toggle() {
 	switch t.state {
 	case timerRunning:
 		t.pause()
+		t.manualPause = true
 	case timerPaused:
 		t.resume()
 	}
@@ -107,7 +235,7 @@ func (t *timerModel) toggle() {
 
 func (t *timerModel) tick() {
 	if t.state == timerRunning {
-		t.elapsed = time.Since(t.startTime) - t.pauseGap
+		t.elapsed = t.currentElapsed()
 
 		// Idle detection
 		if time.Since(t.lastActivity) > t.idleTimeout && !t.isIdle {
@@ -117,9 +245,40 @@ func (t *timerModel) tick() {
 	}
 }
 
+// checkSystemIdle asks the OS how long the machine has been idle, at most
+// every systemIdleInterval. The probe may run a command, so it runs as a
+// tea.Cmd and its answer comes back as a systemIdleMsg.
+func (t *timerModel) checkSystemIdle() tea.Cmd {
+	if t.idleProbe == nil || !t.running() || time.Since(t.lastSystemCheck) < systemIdleInterval {
+		return nil
+	}
+	t.lastSystemCheck = time.Now()
+	probe, entryID := t.idleProbe, t.entryID
+	return func() tea.Msg {
+		secs, ok := probe()
+		return systemIdleMsg{entryID: entryID, secs: secs, ok: ok}
+	}
+}
+
+// applySystemIdle counts input anywhere on the machine as activity, so
+// working in another window does not pause the timer. When the OS can't
+// say, only keys in trackr count. Answers for another entry are dropped.
+func (t *timerModel) applySystemIdle(msg systemIdleMsg) {
+	if !msg.ok || !t.running() || msg.entryID != t.entryID {
+		return
+	}
+	secs := msg.secs
+	// Keys in trackr are input too, so the OS figure covers them.
+	last := time.Now().Add(-time.Duration(secs) * time.Second)
+	if last.After(t.lastActivity) {
+		t.recordActivity()
+	}
+	t.lastActivity = last
+}
+
 func (t *timerModel) recordActivity() {
 	t.lastActivity = time.Now()
-	if t.isIdle && t.state == timerPaused {
+	if t.isIdle && !t.manualPause && t.state == timerPaused {
 		t.resume()
 		t.isIdle = false
 	}
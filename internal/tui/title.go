@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sadopc/trackr/internal/store"
+)
+
+// terminalTitle mirrors the set_terminal_title setting. It is off by
+// default, since some terminals and multiplexers mishandle the escape.
+var terminalTitle bool
+
+// loadTerminalTitle reads the set_terminal_title setting.
+func loadTerminalTitle(s *store.Store) {
+	v, _ := s.GetSetting("set_terminal_title")
+	terminalTitle = v == "true"
+}
+
+// windowTitle is the terminal title for the timer, such as
+// "trackr — Dev 01:23", or "" when no timer runs or titles are off.
+func (d dashboardModel) windowTitle() string {
+	if !terminalTitle || !d.timer.running() {
+		return ""
+	}
+	el := d.timer.currentElapsed()
+	title := fmt.Sprintf("trackr — %s %02d:%02d", d.timer.projectName, int(el.Hours()), int(el.Minutes())%60)
+	if d.timer.paused() {
+		title += " (paused)"
+	}
+	return title
+}
+
+// syncTitle sets the terminal title when it differs from the last one set.
+// An empty title clears it.
+func (a App) syncTitle() (App, tea.Cmd) {
+	want := a.dashboard.windowTitle()
+	if want == a.title {
+		return a, nil
+	}
+	a.title = want
+	return a, tea.SetWindowTitle(want)
+}
+
+// quit exits, first clearing the terminal title if trackr set one.
+func (a App) quit() (tea.Model, tea.Cmd) {
+	if a.title == "" {
+		return a, tea.Quit
+	}
+	a.title = ""
+	return a, tea.Sequence(tea.SetWindowTitle(""), tea.Quit)
+}
@@ -1,9 +1,18 @@
 package tui
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sadopc/trackr/internal/export"
 	"github.com/sadopc/trackr/internal/store"
 )
 
@@ -23,7 +32,7 @@ func newTestStore(t *testing.T) *store.Store {
 
 func TestTimerStartStop(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	if tm.running() {
@@ -75,7 +84,7 @@ func TestTimerStopWhenStopped(t *testing.T) {
 
 func TestTimerPauseResume(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -112,7 +121,7 @@ func TestTimerPauseWhenNotRunning(t *testing.T) {
 
 func TestTimerResumeWhenNotPaused(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -128,7 +137,7 @@ func TestTimerResumeWhenNotPaused(t *testing.T) {
 
 func TestTimerToggle(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -159,7 +168,7 @@ func TestTimerToggleWhenStopped(t *testing.T) {
 
 func TestTimerElapsed(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 
@@ -181,7 +190,7 @@ func TestTimerElapsed(t *testing.T) {
 
 func TestTimerElapsedWhilePaused(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -203,7 +212,7 @@ func TestTimerElapsedWhilePaused(t *testing.T) {
 
 func TestTimerTick(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -231,7 +240,7 @@ func TestTimerTickWhenStopped(t *testing.T) {
 
 func TestTimerIdleDetection(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.idleTimeout = 50 * time.Millisecond // very short for testing
@@ -252,7 +261,7 @@ func TestTimerIdleDetection(t *testing.T) {
 
 func TestTimerIdleRecovery(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.idleTimeout = 50 * time.Millisecond
@@ -277,9 +286,127 @@ func TestTimerIdleRecovery(t *testing.T) {
 	tm.stop()
 }
 
+func TestTimerSystemIdle(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	idle, supported := 0, true
+	tm := newTimerModel(s)
+	tm.idleProbe = func() (int, bool) { return idle, supported }
+	tm.idleTimeout = time.Minute
+	tm.start(p.ID, "Dev", nil, "")
+	tick := func() {
+		tm.lastSystemCheck = time.Time{} // skip the query interval
+		cmd := tm.checkSystemIdle()
+		if cmd == nil {
+			t.Fatal("a running timer should probe the OS")
+		}
+		tm.applySystemIdle(cmd().(systemIdleMsg))
+		tm.tick()
+	}
+
+	// No keys in trackr for ten minutes, but input elsewhere just now.
+	tm.lastActivity = time.Now().Add(-10 * time.Minute)
+	tick()
+	if tm.paused() {
+		t.Fatal("input in another window should keep the timer running")
+	}
+
+	// The probe only runs from the returned command, never in Update.
+	probed := false
+	tm.idleProbe = func() (int, bool) { probed = true; return idle, supported }
+	tm.lastSystemCheck = time.Time{}
+	cmd := tm.checkSystemIdle()
+	if probed {
+		t.Fatal("the OS probe should not run synchronously")
+	}
+	cmd()
+	if !probed {
+		t.Fatal("the returned command should run the probe")
+	}
+	if tm.checkSystemIdle() != nil {
+		t.Fatal("the OS should be asked at most every systemIdleInterval")
+	}
+
+	// An answer for another entry is ignored.
+	tm.lastActivity = time.Now()
+	tm.applySystemIdle(systemIdleMsg{entryID: tm.entryID + 1, secs: 600, ok: true})
+	if time.Since(tm.lastActivity) > time.Minute {
+		t.Fatal("an answer for another entry should be dropped")
+	}
+
+	idle = 120
+	tick()
+	if !tm.isIdle || !tm.paused() {
+		t.Fatal("system idle past the timeout should pause")
+	}
+
+	idle = 0
+	tick()
+	if tm.paused() {
+		t.Fatal("input anywhere should resume an idle pause")
+	}
+
+	// Unsupported: only keys in trackr count.
+	supported = false
+	tm.lastActivity = time.Now().Add(-10 * time.Minute)
+	tick()
+	if !tm.paused() {
+		t.Fatal("without OS support, idle should fall back to keys in trackr")
+	}
+
+	tm.stop()
+}
+
+func TestTimerManualPauseIgnoresActivity(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	tm := newTimerModel(s)
+	tm.start(p.ID, "Dev", nil, "")
+	tm.toggle()
+	if !tm.manualPause {
+		t.Fatal("toggle should mark the pause as manual")
+	}
+
+	tm.recordActivity()
+	if !tm.paused() {
+		t.Fatal("activity should not resume a manual pause")
+	}
+
+	tm.toggle()
+	if tm.paused() || tm.manualPause {
+		t.Fatal("toggle should resume and clear the manual pause")
+	}
+
+	tm.stop()
+}
+
+func TestTimerIdlePauseResumesOnActivity(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	tm := newTimerModel(s)
+	tm.idleTimeout = 50 * time.Millisecond
+	tm.start(p.ID, "Dev", nil, "")
+
+	time.Sleep(100 * time.Millisecond)
+	tm.tick()
+	if tm.manualPause {
+		t.Fatal("idle auto-pause should not be marked manual")
+	}
+
+	tm.recordActivity()
+	if tm.paused() {
+		t.Fatal("activity should resume an idle pause")
+	}
+
+	tm.stop()
+}
+
 func TestTimerRecordActivityWhenNotIdle(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -295,9 +422,32 @@ func TestTimerRecordActivityWhenNotIdle(t *testing.T) {
 	tm.stop()
 }
 
+func TestDashboardBackdateStart(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+
+	d := newDashboardModel(s)
+	d.projects = []store.Project{*p}
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if !d.isRunning() {
+		t.Fatal("S should start the timer")
+	}
+	if d.elapsed() < backdateStep-time.Second {
+		t.Fatalf("elapsed should start at the backdate step, got %v", d.elapsed())
+	}
+	running, _ := s.GetRunningEntry()
+	if time.Since(running.StartTime) < backdateStep-time.Second {
+		t.Fatalf("entry should be backdated, started %v", running.StartTime)
+	}
+	if d.startOffset != 0 {
+		t.Fatal("the offset should only apply to one start")
+	}
+	d.stopTimer()
+}
+
 func TestTimerStartWithTask(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 	task, _ := s.CreateTask(p.ID, "Feature", "")
 
 	tm := newTimerModel(s)
@@ -318,7 +468,7 @@ func TestTimerStartWithTask(t *testing.T) {
 
 func TestTimerStartCreatesDBEntry(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -336,7 +486,7 @@ func TestTimerStartCreatesDBEntry(t *testing.T) {
 
 func TestTimerStopPersistsTooDB(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	tm := newTimerModel(s)
 	tm.start(p.ID, "Dev", nil, "")
@@ -354,6 +504,188 @@ func TestTimerStopPersistsTooDB(t *testing.T) {
 	}
 }
 
+func TestTimerRestorePaused(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	s.StartEntryAt(p.ID, nil, time.Now().Add(-10*time.Minute))
+
+	tm := newTimerModel(s)
+	if err := tm.restore(); err != nil {
+		t.Fatal(err)
+	}
+	if !tm.running() || tm.projectName != "Dev" {
+		t.Fatal("restore should adopt the running entry")
+	}
+	tm.pause()
+
+	// Pretend the app was closed for 5 minutes after pausing, having
+	// already been paused for one minute earlier.
+	pausedAt := time.Now().Add(-5 * time.Minute)
+	s.SaveTimerState(store.TimerState{EntryID: tm.entryID, PauseGap: 60, PausedAt: &pausedAt})
+
+	fresh := newTimerModel(s)
+	fresh.restore()
+	if !fresh.paused() {
+		t.Fatal("a paused timer should come back paused")
+	}
+	got := fresh.currentElapsed()
+	if got < 4*time.Minute-2*time.Second || got > 4*time.Minute+2*time.Second {
+		t.Fatalf("elapsed should be frozen at ~4m, got %v", got)
+	}
+	fresh.recordActivity()
+	if !fresh.paused() {
+		t.Fatal("activity should not resume a restored pause")
+	}
+
+	fresh.resume()
+	entry, _ := fresh.stop()
+	if entry.Duration < 235 || entry.Duration > 245 {
+		t.Fatalf("stored duration should match the elapsed, got %d", entry.Duration)
+	}
+}
+
+func TestDashboardQuickNote(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	app := NewApp(s)
+	app.dashboard.setSize(100, 40)
+
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if app.dashboard.noting {
+		t.Fatal("quick note needs a running timer")
+	}
+
+	app.dashboard.timer.start(p.ID, "Dev", nil, "")
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if !app.dashboard.noting || !app.isFormActive() {
+		t.Fatal("a should open the quick note input and capture keys")
+	}
+	// "q" is Quit elsewhere but must type into the note.
+	model, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("quick fix")})
+	app = model.(App)
+	if !containsString(app.dashboard.view(), "quick fix") {
+		t.Fatal("note input should echo typed text")
+	}
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.dashboard.noting {
+		t.Fatal("enter should close the note input")
+	}
+	e, _ := s.GetEntry(app.dashboard.timer.entryID)
+	if !strings.HasSuffix(e.Notes, "] quick fix\n") {
+		t.Fatalf("note should be appended to the running entry, got %q", e.Notes)
+	}
+}
+
+func TestDashboardStopAt(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	app := NewApp(s)
+	app.dashboard.setSize(100, 40)
+	app.dashboard.timer.startAt(p.ID, "Dev", nil, "", time.Now().Add(-2*time.Hour))
+
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	if !app.dashboard.stoppingAt || !app.isFormActive() {
+		t.Fatal("X should open the stop-at input and capture keys")
+	}
+	app.dashboard = typeKeys(app.dashboard, "nope")
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.dashboard.stoppingAt || !app.dashboard.timer.running() {
+		t.Fatal("an invalid time should keep the input open")
+	}
+
+	end := time.Now().Add(-20 * time.Minute)
+	app.dashboard.stopAtInput = ""
+	app.dashboard = typeKeys(app.dashboard, end.Format("15:04"))
+	entryID := app.dashboard.timer.entryID
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.dashboard.stoppingAt || app.dashboard.timer.running() {
+		t.Fatal("enter should stop the timer")
+	}
+	e, _ := s.GetEntry(entryID)
+	if e.EndTime == nil || e.EndTime.Local().Format("15:04") != end.Format("15:04") {
+		t.Fatalf("entry should end at %s, got %v", end.Format("15:04"), e.EndTime)
+	}
+	if e.Duration < 99*60 || e.Duration > 101*60 {
+		t.Fatalf("duration should run to the stop time, got %d", e.Duration)
+	}
+
+	// Relative times work too: "should have stopped 20 minutes ago".
+	app.dashboard.timer.startAt(p.ID, "Dev", nil, "", time.Now().Add(-time.Hour))
+	entryID = app.dashboard.timer.entryID
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	app.dashboard = typeKeys(app.dashboard, "20m ago")
+	if app.dashboard.stopAtInput != "20m ago" {
+		t.Fatalf("typed input should keep its space, got %q", app.dashboard.stopAtInput)
+	}
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.dashboard.timer.running() {
+		t.Fatal("20m ago should stop the timer")
+	}
+	if e, _ := s.GetEntry(entryID); e.Duration < 39*60 || e.Duration > 41*60 {
+		t.Fatalf("20m ago should leave 40 minutes, got %d", e.Duration)
+	}
+}
+
+// typeKeys sends s to the dashboard one key at a time, spaces as
+// tea.KeySpace the way Bubble Tea delivers them.
+func typeKeys(d dashboardModel, s string) dashboardModel {
+	for _, r := range s {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg.Type = tea.KeySpace
+		}
+		d, _ = d.update(msg)
+	}
+	return d
+}
+
+func TestDashboardReconcileTimer(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	app := NewApp(s)
+	app.dashboard.timer.start(p.ID, "Dev", nil, "")
+	entryID := app.dashboard.timer.entryID
+
+	// `trackr stop` runs while the user looks at Reports.
+	app.activeView = viewReports
+	s.StopEntry(entryID)
+
+	model, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	app = model.(App)
+	app.dashboard, cmd = app.dashboard.update(cmd())
+	if app.dashboard.isRunning() {
+		t.Fatal("timer should stop when its entry was stopped elsewhere")
+	}
+	if m, ok := cmd().(statusMsg); !ok || !containsString(m.text, "stopped outside") {
+		t.Fatalf("expected a status about the external stop, got %#v", m)
+	}
+
+	// A timer started elsewhere is adopted.
+	other, _ := s.StartEntry(p.ID, nil)
+	app.dashboard, _ = app.dashboard.update(app.dashboard.loadData()())
+	if !app.dashboard.isRunning() || app.dashboard.timer.entryID != other.ID {
+		t.Fatalf("timer should adopt running entry %d, got %d", other.ID, app.dashboard.timer.entryID)
+	}
+}
+
+func TestTimerElapsedFromEntryStart(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	s.StartEntryAt(p.ID, nil, time.Now().Add(-10*time.Minute))
+
+	tm := newTimerModel(s)
+	tm.restore()
+	tm.tick()
+	if got := tm.elapsed; got < 10*time.Minute-2*time.Second || got > 10*time.Minute+2*time.Second {
+		t.Fatalf("elapsed should be ~10m from the entry's start, got %v", got)
+	}
+
+	tm.pauseGap = time.Minute
+	if got := tm.currentElapsed(); got < 9*time.Minute-2*time.Second || got > 9*time.Minute+2*time.Second {
+		t.Fatalf("pause gap should be subtracted, got %v", got)
+	}
+}
+
 // ============================================================
 // Helper functions
 // ============================================================
@@ -369,6 +701,7 @@ func TestFormatDuration(t *testing.T) {
 		{time.Hour, "01:00:00"},
 		{time.Hour + time.Minute + time.Second, "01:01:01"},
 		{25 * time.Hour, "25:00:00"},
+		{-time.Minute, "00:00:00"},
 	}
 	for _, tt := range tests {
 		got := formatDuration(tt.d)
@@ -396,24 +729,6 @@ func TestFormatSeconds(t *testing.T) {
 	}
 }
 
-func TestFormatHours(t *testing.T) {
-	tests := []struct {
-		secs int64
-		want string
-	}{
-		{0, "0.0h"},
-		{3600, "1.0h"},
-		{5400, "1.5h"},
-		{7200, "2.0h"},
-	}
-	for _, tt := range tests {
-		got := formatHours(tt.secs)
-		if got != tt.want {
-			t.Errorf("formatHours(%d) = %q, want %q", tt.secs, got, tt.want)
-		}
-	}
-}
-
 func TestMinMax(t *testing.T) {
 	if min(3, 5) != 3 {
 		t.Fatal("min(3,5) should be 3")
@@ -478,7 +793,7 @@ func TestDashboardInit(t *testing.T) {
 
 func TestDashboardStartStop(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Dev", "#000", "work")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
 
 	d := newDashboardModel(s)
 	d.projects = []store.Project{*p}
@@ -494,17 +809,1778 @@ func TestDashboardStartStop(t *testing.T) {
 	}
 }
 
-func TestDashboardPickerWithOneProject(t *testing.T) {
+func TestDashboardPickerWithOneProject(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Solo", "#000", "work", "")
+
+	d := newDashboardModel(s)
+	d.projects = []store.Project{*p}
+
+	// With only one project, pressing start should auto-select it
+	// (no picker shown)
+	if d.picking {
+		t.Fatal("should not be in picker mode initially")
+	}
+}
+
+func TestDashboardPickerFilter(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#000", "work", "")
+	b, _ := s.CreateProject("Beta", "#000", "work", "")
+	g, _ := s.CreateProject("Gamma", "#000", "work", "")
+
+	d := newDashboardModel(s)
+	d.projects = []store.Project{*a, *b, *g}
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if !d.picking {
+		t.Fatal("expected picker to open with multiple projects")
+	}
+
+	// "s" and "j" are regular keys elsewhere but must type into the filter.
+	for _, r := range "AM" {
+		d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	matches := d.pickerMatches()
+	if len(matches) != 1 || matches[0].Name != "Gamma" {
+		t.Fatalf("expected only Gamma to match 'AM', got %+v", matches)
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if d.pickerQuery != "A" || len(d.pickerMatches()) != 3 {
+		t.Fatalf("backspace should widen the filter: query=%q matches=%d", d.pickerQuery, len(d.pickerMatches()))
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyBackspace})
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("be")})
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if d.picking {
+		t.Fatal("enter should close the picker")
+	}
+	if !d.isRunning() || d.timer.projectID != b.ID {
+		t.Fatalf("expected timer running on Beta, got project %d", d.timer.projectID)
+	}
+	d.stopTimer()
+}
+
+func TestDashboardSwitchProject(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#000", "work", "")
+	b, _ := s.CreateProject("Beta", "#000", "work", "")
+
+	d := newDashboardModel(s)
+	d.projects = []store.Project{*a, *b}
+	d, _ = d.startTimer(a.ID, "Alpha", nil, "")
+	first := d.timer.entryID
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if d.isRunning() || !d.picking {
+		t.Fatal("switch should stop the timer and open the picker")
+	}
+	if e, _ := s.GetEntry(first); e.EndTime == nil {
+		t.Fatal("the previous entry should be stopped in the DB")
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyDown})
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !d.isRunning() || d.timer.projectID != b.ID || d.timer.entryID == first {
+		t.Fatalf("expected a fresh timer on Beta, got project %d", d.timer.projectID)
+	}
+	d.stopTimer()
+}
+
+func TestDashboardPickerTodayTotals(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#000", "work", "")
+	b, _ := s.CreateProject("Beta", "#000", "work", "")
+	e, _ := s.StartEntryAt(a.ID, nil, time.Now().Add(-150*time.Second))
+	s.StopEntry(e.ID)
+
+	d := newDashboardModel(s)
+	d.setSize(100, 40)
+	d.projects = []store.Project{*a, *b}
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	view := d.view()
+	if !containsString(view, "(2m today)") {
+		t.Fatal("picker should show Alpha's time today")
+	}
+	if strings.Count(view, "today)") != 1 {
+		t.Fatal("projects without time today should show no total")
+	}
+}
+
+func TestDashboardPickerNewTask(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#000", "work", "")
+	b, _ := s.CreateProject("Beta", "#000", "work", "")
+	s.CreateTask(b.ID, "Review", "")
+
+	d := newDashboardModel(s)
+	d.setSize(100, 40)
+	d.projects = []store.Project{*a, *b}
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyDown})
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyTab})
+	if d.newTaskProject == nil || d.newTaskProject.ID != b.ID {
+		t.Fatal("tab should open the new task input for the selected project")
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Review")})
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if d.newTaskProject == nil || d.taskErr == "" {
+		t.Fatal("a duplicate task name should keep the input open with an error")
+	}
+	if !containsString(d.view(), "already has a task") {
+		t.Fatal("the duplicate error should be visible")
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if d.picking || d.newTaskProject != nil {
+		t.Fatal("creating the task should close the picker")
+	}
+	if !d.isRunning() || d.timer.taskName != "Reviews" || d.timer.projectID != b.ID {
+		t.Fatalf("expected timer on Beta / Reviews, got %d / %q", d.timer.projectID, d.timer.taskName)
+	}
+	d.stopTimer()
+}
+
+func TestAppPickerCapturesKeys(t *testing.T) {
+	s := newTestStore(t)
+	app := NewApp(s)
+	app.dashboard.picking = true
+
+	if !app.isFormActive() {
+		t.Fatal("open picker should capture input")
+	}
+	m, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	app = m.(App)
+	if app.dashboard.pickerQuery != "q" {
+		t.Fatalf("q should type into the picker, got %q", app.dashboard.pickerQuery)
+	}
+}
+
+func TestSplitTime(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	end := start.Add(3 * time.Hour)
+	e := store.TimeEntry{StartTime: start, EndTime: &end}
+
+	at, err := splitTime(e, "10:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !at.Equal(start.Add(90 * time.Minute)) {
+		t.Fatalf("expected 10:30, got %v", at)
+	}
+	for _, bad := range []string{"09:00", "12:00", "13:00", "nope"} {
+		if _, err := splitTime(e, bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+
+	// Overnight entry: 23:00 -> 02:00, split at 01:00 the next day.
+	start = time.Date(2024, 1, 15, 23, 0, 0, 0, time.Local)
+	end = start.Add(3 * time.Hour)
+	e = store.TimeEntry{StartTime: start, EndTime: &end}
+	at, err = splitTime(e, "01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !at.Equal(start.Add(2 * time.Hour)) {
+		t.Fatalf("expected next-day 01:00, got %v", at)
+	}
+}
+
+func TestParseFuzzyTime(t *testing.T) {
+	now := time.Date(2024, 1, 15, 16, 45, 30, 0, time.Local)
+	today := func(h, m int) time.Time { return time.Date(2024, 1, 15, h, m, 0, 0, time.Local) }
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"14:30", today(14, 30)},
+		{" 09:05 ", today(9, 5)},
+		{"9", today(9, 0)},
+		{"2:30pm", today(14, 30)},
+		{"2:30 PM", today(14, 30)},
+		{"9am", today(9, 0)},
+		{"12am", today(0, 0)},
+		{"12pm", today(12, 0)},
+		{"today 8:15", today(8, 15)},
+		{"yesterday 9am", time.Date(2024, 1, 14, 9, 0, 0, 0, time.Local)},
+		{"Yesterday 23:00", time.Date(2024, 1, 14, 23, 0, 0, 0, time.Local)},
+		{"now", now},
+		{"now-1h", now.Add(-time.Hour)},
+		{"now - 90m", now.Add(-90 * time.Minute)},
+		{"now+15m", now.Add(15 * time.Minute)},
+		{"45m", now.Add(-45 * time.Minute)},
+		{"1h30m", now.Add(-90 * time.Minute)},
+		{"45m ago", now.Add(-45 * time.Minute)},
+		{"2024-01-10 07:20", time.Date(2024, 1, 10, 7, 20, 0, 0, time.Local)},
+		{"2024-01-10T07:20:00Z", time.Date(2024, 1, 10, 7, 20, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseFuzzyTime(tt.in, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "nope", "25:00", "13pm", "0am", "9:7", "now*2", "now-", "yesterday", "tomorrow 9am", "-1h"} {
+		if _, err := parseFuzzyTime(bad, now); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		} else if !containsString(err.Error(), "e.g.") {
+			t.Errorf("%q: error should show accepted formats, got %v", bad, err)
+		}
+	}
+}
+
+func TestDashboardEntryForm(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e.ID)
+
+	app := NewApp(s)
+	app.dashboard, _ = app.dashboard.update(app.dashboard.loadData()())
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.dashboard.formActive {
+		t.Fatal("enter on a recent entry should open the edit form")
+	}
+	if !app.isFormActive() {
+		t.Fatal("dashboard form should capture input")
+	}
+
+	d := app.dashboard
+	*d.formNotes = "edited"
+	if cmd := d.saveEntryForm(); cmd == nil {
+		t.Fatal("save should reload the dashboard")
+	}
+	got, _ := s.GetEntry(e.ID)
+	if got.Notes != "edited" {
+		t.Fatalf("notes should be saved, got %q", got.Notes)
+	}
+	if !*d.formBillable {
+		t.Fatal("form should start from the entry's billable flag")
+	}
+	*d.formBillable = false
+	d.saveEntryForm()
+	if got, _ = s.GetEntry(e.ID); got.Billable {
+		t.Fatal("billable toggle should be saved")
+	}
+	*d.formBillable = true
+
+	// The entry is zero-length, so any split time is out of range.
+	*d.formSplitAt = "12:00"
+	msgs := d.saveEntryForm()
+	if msgs == nil {
+		t.Fatal("expected a command")
+	}
+	if m, ok := msgs().(statusMsg); !ok || !m.isError {
+		t.Fatalf("expected an error status for an invalid split, got %#v", m)
+	}
+}
+
+func TestDashboardEntryFormProject(t *testing.T) {
+	s := newTestStore(t)
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
+	task, _ := s.CreateTask(dev.ID, "Review", "")
+	e, _ := s.StartEntry(dev.ID, &task.ID)
+	s.StopEntry(e.ID)
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if *d.formProject != dev.ID || *d.formTask != task.ID {
+		t.Fatalf("form should start from the entry's project and task, got %d/%d", *d.formProject, *d.formTask)
+	}
+
+	// Switching project drops a task that doesn't belong to it.
+	*d.formProject = ops.ID
+	d.entryTaskOptions()
+	if *d.formTask != 0 {
+		t.Fatalf("task should be cleared for another project, got %d", *d.formTask)
+	}
+	d.saveEntryForm()
+	got, _ := s.GetEntry(e.ID)
+	if got.ProjectID != ops.ID || got.TaskID != nil {
+		t.Fatalf("entry should move to Ops without a task, got %d/%v", got.ProjectID, got.TaskID)
+	}
+}
+
+func TestDashboardDuplicateEntry(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	cmd := d.duplicateEntry(d.recentEntries[0])
+	if m, ok := cmd().(statusMsg); !ok || !m.isError {
+		t.Fatal("duplicating a running entry should report an error")
+	}
+
+	s.StopEntry(e.ID)
+	d, _ = d.update(d.loadData()())
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	entries, _ := s.ListEntries(store.EntryFilter{})
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries after duplicate, got %d", len(entries))
+	}
+
+	// A copy ending now lands on top of a block that also just ended.
+	recent, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-30*time.Minute))
+	s.StopEntry(recent.ID)
+	d, _ = d.update(d.loadData()())
+	var orig store.DetailedEntry
+	for _, r := range d.recentEntries {
+		if r.ID == recent.ID {
+			orig = r
+		}
+	}
+	st, _ := batchStatus(d.duplicateEntry(orig))
+	want := "overlaps with a " + recent.StartTime.Local().Format("15:04") + "–"
+	if st.isError || !containsString(st.text, want) {
+		t.Fatalf("expected an overlap warning %q, got %q", want, st.text)
+	}
+}
+
+func TestDashboardResumeLast(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	task, _ := s.CreateTask(p.ID, "Review", "")
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if d.isRunning() {
+		t.Fatal("nothing should resume without history")
+	}
+
+	e, _ := s.StartEntry(p.ID, &task.ID)
+	s.StopEntry(e.ID)
+	d, _ = d.update(d.loadData()())
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if !d.isRunning() || d.timer.projectID != p.ID || d.timer.taskID == nil || *d.timer.taskID != task.ID {
+		t.Fatalf("r should resume Dev / Review, got project %d task %v", d.timer.projectID, d.timer.taskID)
+	}
+	if d.timer.taskName != "Review" {
+		t.Fatalf("task name = %q, want Review", d.timer.taskName)
+	}
+}
+
+func TestDashboardMiniLayout(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("A project with a rather long name", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+	s.StopEntry(e.ID)
+
+	d := newDashboardModel(s)
+	d.setSize(60, 20)
+	d, _ = d.update(d.loadData()())
+	d, _ = d.startTimer(p.ID, p.Name, nil, "")
+
+	view := d.view()
+	if view == "" || !containsString(view, "Today") || !containsString(view, "Recent") {
+		t.Fatalf("mini layout should show the timer, today and recent entries:\n%s", view)
+	}
+	if containsString(view, "╭") {
+		t.Fatal("mini layout should not draw panel borders")
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if lipgloss.Width(line) > 60 {
+			t.Fatalf("line wider than the terminal: %q", line)
+		}
+	}
+
+	// The picker still opens in place of the recent entries.
+	d.stopTimer()
+	d, _ = d.openPicker()
+	if view := d.view(); !containsString(view, "Select Project") {
+		t.Fatalf("picker should render in the mini layout:\n%s", view)
+	}
+
+	d.setSize(100, 30)
+	if !containsString(d.view(), "╭") {
+		t.Fatal("wide terminals keep the panel layout")
+	}
+}
+
+func TestFocusMode(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e.ID)
+
+	var m tea.Model = NewApp(s)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	app := m.(App)
+	app.dashboard, _ = app.dashboard.update(app.dashboard.loadData()())
+	app.dashboard, _ = app.dashboard.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+
+	m, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	view := m.View()
+	if containsString(view, "Reports") || !containsString(view, "Dev") || !containsString(view, "███") {
+		t.Fatalf("focus mode should show only the big clock and project:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if !m.(App).dashboard.timer.paused() {
+		t.Fatal("space should still pause the timer in focus mode")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.(App).dashboard.picking || m.(App).dashboard.formActive {
+		t.Fatal("other keys should be ignored in focus mode")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(App).focusMode || !containsString(m.View(), "Reports") {
+		t.Fatal("esc should leave focus mode")
+	}
+}
+
+func TestTimerKeysFromOtherViews(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("Dev", "#000", "work", "")
+
+	app := NewApp(s)
+	app.dashboard, _ = app.dashboard.update(app.dashboard.loadData()())
+	app.activeView = viewReports
+	press := func(k string) {
+		t.Helper()
+		m, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		app = m.(App)
+	}
+
+	press("s")
+	if !app.dashboard.isRunning() || app.activeView != viewReports {
+		t.Fatal("s in reports should start the only project without leaving the view")
+	}
+	press("x")
+	if app.dashboard.isRunning() {
+		t.Fatal("x in reports should stop the timer")
+	}
+
+	app.activeView = viewPomodoro
+	press("s")
+	if app.dashboard.isRunning() {
+		t.Fatal("the pomodoro view keeps s for itself")
+	}
+
+	s.CreateProject("Ops", "#111", "work", "")
+	app.dashboard, _ = app.dashboard.update(app.dashboard.loadData()())
+	app.activeView = viewSettings
+	press("s")
+	if !app.dashboard.picking || app.activeView != viewDashboard {
+		t.Fatal("with several projects, s should open the picker on the dashboard")
+	}
+}
+
+func TestOrphanPrompt(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	s.StartEntryAt(p.ID, nil, time.Now().Add(-18*time.Hour))
+
+	var m tea.Model = NewApp(s)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	if view := m.View(); !containsString(view, "running for 18h") || !containsString(view, "Discard") {
+		t.Fatalf("expected the orphan prompt on launch:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app := m.(App)
+	if len(app.orphans) != 0 || app.dashboard.isRunning() {
+		t.Fatal("stop now should close the prompt and stop the timer")
+	}
+	if running, _ := s.ListRunningEntries(); len(running) != 0 {
+		t.Fatalf("entry should be stopped, %d still running", len(running))
+	}
+}
+
+func TestStartedLabel(t *testing.T) {
+	now := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC), "today 08:00"},
+		{time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), "yesterday 09:00"},
+		{time.Date(2024, 2, 28, 9, 0, 0, 0, time.UTC), "Wed Feb 28 09:00"},
+	} {
+		if got := startedLabel(tc.t, now); got != tc.want {
+			t.Errorf("startedLabel(%s) = %q, want %q", tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestDashboardTrash(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e.ID)
+
+	d := newDashboardModel(s)
+	d.setSize(100, 40)
+	d, _ = d.update(d.loadData()())
+	cmd := d.archiveEntry(d.recentEntries[0])
+	if cmd == nil {
+		t.Fatal("archive should return a command")
+	}
+	d, _ = d.update(d.loadData()())
+	if len(d.recentEntries) != 0 || len(d.trash) != 1 {
+		t.Fatalf("entry should move to the trash: recent=%d trash=%d", len(d.recentEntries), len(d.trash))
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if !d.showTrash || !containsString(d.view(), "Trash") {
+		t.Fatal("T should show the trash panel")
+	}
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyEnter})
+	d, _ = d.update(d.loadData()())
+	if len(d.trash) != 0 || len(d.recentEntries) != 1 {
+		t.Fatal("enter in the trash should restore the entry")
+	}
+	if d.formActive {
+		t.Fatal("enter in the trash must not open the entry form")
+	}
+}
+
+func TestDashboardRecentCount(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	for i := 0; i < 10; i++ {
+		e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Duration(10-i)*time.Minute))
+		s.StopEntry(e.ID)
+	}
+
+	d := newDashboardModel(s)
+	if d.loadRecentCount() != 5 {
+		t.Fatal("recent count should default to 5")
+	}
+	s.SetSetting("dashboard_recent_count", "500")
+	if d.loadRecentCount() != maxRecentCount {
+		t.Fatal("recent count should be clamped")
+	}
+
+	s.SetSetting("dashboard_recent_count", "8")
+	d, _ = d.update(d.loadData()())
+	if len(d.recentEntries) != 8 {
+		t.Fatalf("expected 8 recent entries, got %d", len(d.recentEntries))
+	}
+
+	// Only three rows fit; the selection stays visible.
+	d.height = 40
+	d.recentCursor = 6
+	panel := d.renderRecentPanel(80, 8)
+	if h := lipgloss.Height(panel); h > 8 {
+		t.Fatalf("panel should fit in 8 lines, got %d", h)
+	}
+	if !containsString(panel, "> ") {
+		t.Fatal("the selected entry should stay visible")
+	}
+}
+
+func TestDashboardWeeklyProgress(t *testing.T) {
+	s := newTestStore(t)
+	d := newDashboardModel(s)
+	d.weekTotal = 36000
+	d.weeklyGoal = 72000
+
+	if got := d.renderWeeklyProgress(); !containsString(got, "10:00:00 / 20:00:00") {
+		t.Fatalf("expected weekly progress, got %q", got)
+	}
+
+	d.weeklyGoal = 0
+	if got := d.renderWeeklyProgress(); got != "" {
+		t.Fatalf("weekly progress should be hidden with no goal, got %q", got)
+	}
+}
+
+func TestDashboardDailyGoalReached(t *testing.T) {
+	var out bytes.Buffer
+	bellOut = &out
+	t.Cleanup(func() { bellOut = os.Stdout })
+	s := newTestStore(t)
+	s.SetSetting("daily_goal", "3600")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-30*time.Minute))
+	s.StopEntry(e.ID)
+
+	d := newDashboardModel(s)
+	d, cmd := d.update(d.loadData()())
+	if cmd != nil {
+		t.Fatal("half the goal should not be announced")
+	}
+	if !containsString(d.renderSummaryPanel(100), "00:30:00 / 01:00:00") {
+		t.Fatal("the summary should show progress toward the daily goal")
+	}
+
+	// A timer started 40 minutes ago takes today past the goal.
+	s.StartEntryAt(p.ID, nil, time.Now().Add(-40*time.Minute))
+	d, cmd = d.update(d.loadData()())
+	if st, ok := batchStatus(cmd); !ok || !containsString(st.text, "Daily goal reached") {
+		t.Fatalf("crossing the goal should be announced, got %+v", st)
+	}
+	if _, cmd = d.update(tickMsg(time.Now())); cmd != nil {
+		t.Fatal("the goal should be announced only once a day")
+	}
+
+	// Already met when trackr opens: nothing to announce.
+	d = newDashboardModel(s)
+	if _, cmd = d.update(d.loadData()()); cmd != nil {
+		t.Fatal("a goal met before launch should not be announced")
+	}
+}
+
+func TestDashboardWeekStats(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	for range 3 {
+		e, _ := s.StartEntry(p.ID, nil)
+		s.StopEntry(e.ID)
+	}
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	if d.weekEntries != 3 {
+		t.Fatalf("expected 3 entries this week, got %d", d.weekEntries)
+	}
+
+	d.weekEntries = 23
+	d.weekTotal = 32 * 3600
+	d.weeklyGoal = 40 * 3600
+	if got := d.weekStatsLine(); got != "This week: 23 entries · 32:00:00 tracked · ~08:00:00 untracked vs goal" {
+		t.Fatalf("unexpected week stats %q", got)
+	}
+
+	d.weeklyGoal = 0
+	if got := d.weekStatsLine(); containsString(got, "untracked") {
+		t.Fatalf("untracked estimate should be hidden with no goal, got %q", got)
+	}
+	d.weeklyGoal = 30 * 3600
+	if got := d.weekStatsLine(); containsString(got, "untracked") {
+		t.Fatalf("untracked estimate should be hidden once the goal is met, got %q", got)
+	}
+}
+
+func TestDashboardWeeklyGoalFallback(t *testing.T) {
+	s := newTestStore(t)
+	if got := loadWeeklyGoal(s); got != 5*28800 {
+		t.Fatalf("expected 5x daily goal, got %d", got)
+	}
+
+	// Settings offers the same fallback, so saving it keeps the goal.
+	s.SetSetting("daily_goal", "21600")
+	st := newSettingsModel(s)
+	st, _ = st.showForm()
+	if *st.weeklyGoal != "30.0" {
+		t.Fatalf("settings should show 5x a 6h daily goal, got %q", *st.weeklyGoal)
+	}
+
+	s.SetSetting("weekly_goal", "0")
+	if got := loadWeeklyGoal(s); got != 0 {
+		t.Fatalf("expected 0, got %d", got)
+	}
+}
+
+func TestDashboardTimeline(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	s := newTestStore(t)
+	s.SetClock(func() time.Time { return at(16, 0) })
+	projects := map[string]int64{}
+	for _, name := range []string{"Dev", "Docs", "Ops", "Live"} {
+		p, _ := s.CreateProject(name, "#000", "work", "")
+		projects[name] = p.ID
+	}
+	add := func(name string, from, to time.Time) {
+		e, _ := s.StartEntryAt(projects[name], nil, from)
+		s.StopEntryAt(e.ID, to)
+	}
+	add("Ops", at(11, 15), at(12, 0))
+	add("Dev", at(9, 0), at(10, 0))
+	add("Docs", at(10, 30), at(11, 30))
+	add("Dev", day.Add(-time.Hour), day.Add(-30*time.Minute)) // yesterday
+	s.StartEntryAt(projects["Live"], nil, at(15, 0))
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	if len(d.timeline) != 3 || d.timeline[0].ProjectName != "Dev" || d.timeline[2].ProjectName != "Ops" {
+		t.Fatalf("timeline should hold today's completed entries in start order: %+v", d.timeline)
+	}
+
+	out := d.renderTimelinePanel(100)
+	for _, want := range []string{"09:00–10:00  Dev (1h)", "10:00–10:30  untracked (30m)", "overlaps previous entry by 15m", "11:15–12:00  Ops (45m)"} {
+		if !containsString(out, want) {
+			t.Errorf("timeline missing %q:\n%s", want, out)
+		}
+	}
+	if containsString(out, "Live") {
+		t.Error("running entries should not appear on the timeline")
+	}
+}
+
+func TestDashboardTimelineToggle(t *testing.T) {
+	d := newDashboardModel(newTestStore(t))
+	d.setSize(100, 40)
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if !d.showTimeline || !containsString(d.view(), "Today's Timeline") {
+		t.Fatal("t should show the timeline")
+	}
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if d.showTimeline {
+		t.Fatal("t should toggle the timeline off")
+	}
+}
+
+func TestProjectGlyph(t *testing.T) {
+	t.Cleanup(func() { accessibleMode = false })
+
+	accessibleMode = false
+	if projectGlyph(1) != "●" || projectGlyph(2) != "●" {
+		t.Fatal("without accessible mode every project gets a dot")
+	}
+
+	accessibleMode = true
+	seen := map[string]bool{}
+	for id := int64(1); id <= int64(len(projectGlyphs)); id++ {
+		seen[projectGlyph(id)] = true
+	}
+	if len(seen) != len(projectGlyphs) {
+		t.Fatalf("expected %d distinct glyphs, got %d", len(projectGlyphs), len(seen))
+	}
+	if projectGlyph(1) != projectGlyph(1+int64(len(projectGlyphs))) || projectGlyph(0) != "●" {
+		t.Fatal("glyphs should be derived from the ID")
+	}
+}
+
+func TestAccessibleModeSetting(t *testing.T) {
+	t.Cleanup(func() { accessibleMode = false })
+	s := newTestStore(t)
+	a, _ := s.CreateProject("A", "#111", "work", "")
+	b, _ := s.CreateProject("B", "#222", "work", "")
+	for _, id := range []int64{a.ID, b.ID} {
+		e, _ := s.StartEntryAt(id, nil, time.Now().Add(-time.Minute))
+		s.StopEntry(e.ID)
+	}
+
+	s.SetSetting("accessible_mode", "true")
+	NewApp(s)
+	if !accessibleMode {
+		t.Fatal("NewApp should load accessible_mode")
+	}
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	text := d.summaryText()
+	if !containsString(text, projectGlyph(a.ID)+" A") || !containsString(text, projectGlyph(b.ID)+" B") {
+		t.Fatalf("summary should mark projects with their glyphs: %q", text)
+	}
+}
+
+func TestFormatShort(t *testing.T) {
+	tests := map[int64]string{0: "0m", 59: "0m", 2700: "45m", 3600: "1h", 5400: "1h30m", 37800: "10h30m"}
+	for secs, want := range tests {
+		if got := formatShort(secs); got != want {
+			t.Errorf("formatShort(%d) = %q, want %q", secs, got, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a longer note", 6, "a lon…"},
+		{"日本語のメモ", 5, "日本…"},
+		{"anything", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.in, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+	if got := singleLine("line one\n\tline  two "); got != "line one line two" {
+		t.Fatalf("singleLine = %q", got)
+	}
+}
+
+func TestDashboardRecentNotes(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e.ID)
+	s.UpdateEntryNotes(e.ID, "fixed the flaky\nlogin test and more details that will not fit")
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	panel := d.renderRecentPanel(70, 0)
+	if !containsString(panel, "fixed the flaky login") || !containsString(panel, "…") {
+		t.Fatalf("recent row should show a truncated one-line notes snippet:\n%s", panel)
+	}
+}
+
+func TestDashboardRecentTags(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e.ID)
+	s.UpdateEntryTags(e.ID, "interrupt")
+	s.UpdateEntryNotes(e.ID, "pager")
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	if panel := d.renderRecentPanel(90, 0); !containsString(panel, "#interrupt pager") {
+		t.Fatalf("recent row should show the entry's tags before its notes:\n%s", panel)
+	}
+}
+
+func TestDashboardNoteMarker(t *testing.T) {
+	s := newTestStore(t)
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
+	for _, id := range []int64{dev.ID, ops.ID} {
+		e, _ := s.StartEntryAt(id, nil, time.Now().Add(-time.Hour))
+		s.StopEntry(e.ID)
+		if id == dev.ID {
+			s.UpdateEntryNotes(e.ID, "wrote the spec")
+		}
+	}
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	for name, panel := range map[string]string{
+		"recent":   d.renderRecentPanel(90, 0),
+		"timeline": d.renderTimelinePanel(90),
+	} {
+		var devRow, opsRow string
+		for _, line := range strings.Split(panel, "\n") {
+			switch {
+			case containsString(line, "Dev"):
+				devRow = line
+			case containsString(line, "Ops"):
+				opsRow = line
+			}
+		}
+		if !containsString(devRow, "✎") || containsString(opsRow, "✎") {
+			t.Fatalf("%s: only the entry with notes should be marked:\n%s", name, panel)
+		}
+		if lipgloss.Width(devRow[:strings.Index(devRow, "Dev")]) != lipgloss.Width(opsRow[:strings.Index(opsRow, "Ops")]) {
+			t.Fatalf("%s: the marker should not shift the columns:\n%s", name, panel)
+		}
+	}
+}
+
+func TestDashboardProjectGoalBar(t *testing.T) {
+	s := newTestStore(t)
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
+	s.SetProjectDailyGoal(dev.ID, 7200)
+	for _, id := range []int64{dev.ID, ops.ID} {
+		e, _ := s.StartEntry(id, nil)
+		s.StopEntry(e.ID)
+	}
+
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	var devRow, opsRow string
+	for _, line := range strings.Split(d.renderSummaryPanel(90), "\n") {
+		switch {
+		case containsString(line, "Dev"):
+			devRow = line
+		case containsString(line, "Ops"):
+			opsRow = line
+		}
+	}
+	if !containsString(devRow, "░") || !containsString(devRow, "2h") {
+		t.Fatalf("Dev should show a bar against its 2h goal: %q", devRow)
+	}
+	if containsString(opsRow, "░") {
+		t.Fatalf("Ops has no goal and should show no bar: %q", opsRow)
+	}
+}
+
+func TestDashboardSummaryText(t *testing.T) {
+	s := newTestStore(t)
+	d := newDashboardModel(s)
+	if d.summaryText() != "Today  00:00:00\nNo entries today" {
+		t.Fatalf("unexpected empty summary: %q", d.summaryText())
+	}
+
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e.ID)
+	d, _ = d.update(d.loadData()())
+
+	text := d.summaryText()
+	if !containsString(text, "Dev") || !containsString(text, "(1 entries)") {
+		t.Fatalf("summary should list projects like the panel: %q", text)
+	}
+	if containsString(text, "\x1b") {
+		t.Fatal("summary text should be unstyled")
+	}
+}
+
+func TestDashboardWeekSummary(t *testing.T) {
+	s := newTestStore(t)
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
+	for _, id := range []int64{dev.ID, dev.ID, ops.ID} {
+		e, _ := s.StartEntryAt(id, nil, time.Now().Add(-time.Minute))
+		s.StopEntry(e.ID)
+	}
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	if len(d.weekSummary) != 2 || d.weekSummary[0].ProjectName != "Dev" || d.weekSummary[0].EntryCount != 2 {
+		t.Fatalf("expected per-project week totals, got %+v", d.weekSummary)
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if !d.showWeek {
+		t.Fatal("m should switch the summary to this week")
+	}
+	if text := d.summaryText(); !strings.HasPrefix(text, "This week") || !containsString(text, "(2 entries)") {
+		t.Fatalf("summary should show this week: %q", text)
+	}
+	if !containsString(d.renderSummaryPanel(100), "This week") {
+		t.Fatal("panel title should name the period")
+	}
+
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if !strings.HasPrefix(d.summaryText(), "Today") {
+		t.Fatal("m again should switch back to today")
+	}
+}
+
+func TestFoldByProject(t *testing.T) {
+	got := foldByProject([]store.DailySummary{
+		{Date: "2024-01-01", ProjectID: 2, ProjectName: "B", TotalSeconds: 60, EntryCount: 1},
+		{Date: "2024-01-01", ProjectID: 1, ProjectName: "A", TotalSeconds: 30, EntryCount: 1},
+		{Date: "2024-01-02", ProjectID: 2, ProjectName: "B", TotalSeconds: 90, EntryCount: 2},
+	})
+	if len(got) != 2 || got[0].ProjectName != "A" || got[1].TotalSeconds != 150 || got[1].EntryCount != 3 {
+		t.Fatalf("unexpected fold %+v", got)
+	}
+	if got[1].Date != "" {
+		t.Fatal("folded rows span days and should have no date")
+	}
+}
+
+// ============================================================
+// Projects model
+// ============================================================
+
+func TestScrollWindow(t *testing.T) {
+	tests := []struct{ cursor, n, fit, first, last int }{
+		{0, 3, 5, 0, 3},
+		{0, 10, 4, 0, 4},
+		{5, 10, 4, 2, 6},
+		{9, 10, 4, 6, 10},
+	}
+	for _, tt := range tests {
+		if first, last := scrollWindow(tt.cursor, tt.n, tt.fit); first != tt.first || last != tt.last {
+			t.Errorf("scrollWindow(%d, %d, %d) = %d, %d; want %d, %d",
+				tt.cursor, tt.n, tt.fit, first, last, tt.first, tt.last)
+		}
+	}
+}
+
+func TestProjectsListScrolls(t *testing.T) {
+	s := newTestStore(t)
+	for i := range 30 {
+		s.CreateProject(fmt.Sprintf("Project %02d", i), "#000", "work", "")
+	}
+	pm := newProjectsModel(s)
+	pm.setSize(100, 20)
+	pm, _ = pm.update(pm.refresh()())
+	for range 25 {
+		pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+
+	view := pm.view()
+	if h := lipgloss.Height(view); h > 20 {
+		t.Fatalf("project list should fit in 20 lines, got %d", h)
+	}
+	if !containsString(view, "Project 25") || containsString(view, "Project 00") {
+		t.Fatalf("the list should scroll to keep the selection in view:\n%s", view)
+	}
+}
+
+func TestProjectsTogglePin(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("A", "#111", "work", "")
+	s.CreateProject("B", "#222", "work", "")
+
+	pm := newProjectsModel(s)
+	pm, _ = pm.update(pm.refresh()())
+	pm.cursor = 1 // B
+
+	pm, cmd := pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	pm, _ = pm.update(cmd())
+	if pm.projects[0].Name != "B" || !pm.projects[0].Pinned {
+		t.Fatalf("pinned project should sort first: %+v", pm.projects)
+	}
+	if !containsString(pm.view(), "★") {
+		t.Fatal("pinned project should render a star")
+	}
+}
+
+func TestProjectsCycleCategory(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#111", "work", "")
+	pm := newProjectsModel(s)
+	pm, _ = pm.update(pm.refresh()())
+
+	pm, cmd := pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if st, ok := batchStatus(cmd); !ok || !containsString(st.text, "personal") {
+		t.Fatalf("expected a status naming the new category, got %+v", st)
+	}
+	if got, _ := s.GetProject(p.ID); got.Category != "personal" {
+		t.Fatalf("c should move the project to the next category, got %q", got.Category)
+	}
+
+	// Past the last category, cycling wraps to the first.
+	s.SetProjectCategory(p.ID, "other")
+	pm, _ = pm.update(pm.refresh()())
+	pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if got, _ := s.GetProject(p.ID); got.Category != "work" {
+		t.Fatalf("expected to wrap to work, got %q", got.Category)
+	}
+}
+
+func TestProjectsArchiveTasks(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#111", "work", "")
+	done, _ := s.CreateTask(p.ID, "Done", "")
+	s.CreateTask(p.ID, "Open", "")
+	s.CompleteTask(done.ID)
+	pm := newProjectsModel(s)
+	pm, _ = pm.update(pm.refresh()())
+	pm, cmd := pm.update(tea.KeyMsg{Type: tea.KeyEnter})
+	pm, _ = pm.update(cmd())
+
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if !pm.formActive || pm.formType != "archive_tasks" {
+		t.Fatalf("A should open the archive form, got %q", pm.formType)
+	}
+
+	// Declining archives nothing.
+	pm.archiveTasks()
+	if tasks, _ := s.ListTasks(p.ID, false); len(tasks) != 2 {
+		t.Fatalf("cancel should keep both tasks, got %d", len(tasks))
+	}
+
+	*pm.formConfirm = true
+	pm, cmd = pm.archiveTasks()
+	if st, ok := batchStatus(cmd); !ok || st.text != "Archived 1 task" {
+		t.Fatalf("expected the archived count, got %+v", st)
+	}
+	if tasks, _ := s.ListTasks(p.ID, false); len(tasks) != 1 || tasks[0].Name != "Open" {
+		t.Fatalf("only the completed task should be archived: %+v", tasks)
+	}
+}
+
+func TestProjectsDetails(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#111", "work", "")
+	s.CreateProject("Empty", "#222", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-90*time.Minute))
+	s.StopEntry(e.ID)
+	pm := newProjectsModel(s)
+	pm.setSize(100, 30)
+	pm, _ = pm.update(pm.refresh()())
+
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if pm.stats == nil || pm.stats.EntryCount != 1 {
+		t.Fatalf("i should load the project's stats, got %+v", pm.stats)
+	}
+	if v := pm.view(); !containsString(v, "Dev — Details") || !containsString(v, "1h30m") {
+		t.Fatalf("details should show the average session:\n%s", v)
+	}
+
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyEsc})
+	if pm.stats != nil {
+		t.Fatal("esc should close the details")
+	}
+
+	// A project without entries has no dates.
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyDown})
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if !containsString(pm.view(), "never") {
+		t.Fatal("an untracked project should show never for its dates")
+	}
+}
+
+func TestProjectsSortToggle(t *testing.T) {
+	s := newTestStore(t)
+	a, _ := s.CreateProject("Alpha", "#111", "work", "")
+	b, _ := s.CreateProject("Beta", "#222", "work", "")
+	old, _ := s.StartEntryAt(a.ID, nil, time.Now().Add(-2*time.Hour))
+	s.StopEntry(old.ID)
+	recent, _ := s.StartEntryAt(b.ID, nil, time.Now().Add(-time.Minute))
+	s.StopEntry(recent.ID)
+
+	pm := newProjectsModel(s)
+	pm.setSize(100, 30)
+	pm, _ = pm.update(pm.refresh()())
+	if pm.projects[0].Name != "Alpha" {
+		t.Fatal("projects should sort by name by default")
+	}
+
+	pm, cmd := pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	pm, _ = pm.update(cmd())
+	if pm.sortOrder != store.SortByRecent || pm.projects[0].Name != "Beta" {
+		t.Fatalf("o should sort by recent use, got %s: %+v", pm.sortOrder, pm.projects)
+	}
+	if !containsString(pm.view(), "sorted by recent use") {
+		t.Fatal("header should show the sort order")
+	}
+
+	// The choice is saved, so the start picker follows it.
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	if d.projects[0].Name != "Beta" {
+		t.Fatalf("dashboard projects should follow the saved sort, got %+v", d.projects)
+	}
+}
+
+func TestProjectsTaskViewDescription(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("Client", "#111", "freelance", "Quarterly goals")
+
+	pm := newProjectsModel(s)
+	pm.setSize(100, 30)
+	pm, _ = pm.update(pm.refresh()())
+	pm.viewingTasks = true
+	if !containsString(pm.view(), "Quarterly goals") {
+		t.Fatal("task view header should show the project description")
+	}
+}
+
+func TestProjectsCompleteTask(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Client", "#111", "work", "")
+	task, _ := s.CreateTask(p.ID, "Invoice", "")
+
+	pm := newProjectsModel(s)
+	pm.setSize(100, 30)
+	pm, _ = pm.update(pm.refresh()())
+	pm.viewingTasks = true
+	pm, _ = pm.update(pm.refreshTasks()())
+	if !containsString(pm.view(), "☐") {
+		t.Fatal("open task should render an empty checkbox")
+	}
+
+	pm, cmd := pm.update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	pm, _ = pm.update(cmd())
+	if got, _ := s.GetTask(task.ID); got.CompletedAt == nil {
+		t.Fatal("space should complete the task")
+	}
+	if len(pm.tasks) != 1 || !containsString(pm.view(), "☑") {
+		t.Fatal("completed task should stay listed with a checked box")
+	}
+
+	pm, cmd = pm.update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	pm, _ = pm.update(cmd())
+	if got, _ := s.GetTask(task.ID); got.CompletedAt != nil {
+		t.Fatal("space should reopen a completed task")
+	}
+}
+
+func TestProjectsRenameCollision(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("A", "#111", "work", "")
+	s.CreateProject("B", "", "personal", "")
+
+	pm := newProjectsModel(s)
+	pm.setSize(100, 30)
+	pm, _ = pm.update(pm.refresh()())
+	pm.cursor = 1 // B
+	pm, _ = pm.showEditProjectForm()
+
+	*pm.formName = "A"
+	pm, _ = pm.saveProjectForm()
+	if !pm.formActive || pm.formErr == "" {
+		t.Fatal("a name collision should keep the form open with an error")
+	}
+	if *pm.formName != "A" {
+		t.Fatal("the form should keep the user's input")
+	}
+	if !containsString(pm.view(), "already exists") {
+		t.Fatal("the error should be visible")
+	}
+
+	*pm.formName = "C"
+	pm, _ = pm.saveProjectForm()
+	if pm.formErr != "" {
+		t.Fatal("a successful save should clear the error")
+	}
+	if got, _ := s.GetOrCreateProject("C"); got.Category != "personal" {
+		t.Fatal("rename should apply after fixing the name")
+	}
+}
+
+// batchStatus runs a command, batched or not, and returns the status
+// message it produces, if any.
+func batchStatus(cmd tea.Cmd) (statusMsg, bool) {
+	if cmd == nil {
+		return statusMsg{}, false
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if st, ok := batchStatus(c); ok {
+				return st, true
+			}
+		}
+		return statusMsg{}, false
+	}
+	st, ok := msg.(statusMsg)
+	return st, ok
+}
+
+func TestProjectsReportStoreErrors(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	s.CreateTask(p.ID, "Review", "")
+
+	pm := newProjectsModel(s)
+	pm.setSize(100, 30)
+	pm, _ = pm.update(pm.refresh()())
+	pm, _ = pm.showNewTaskForm()
+	*pm.formName = "Review"
+	_, cmd := pm.saveTaskForm()
+	if st, ok := batchStatus(cmd); !ok || !st.isError || !containsString(st.text, "already exists") {
+		t.Fatalf("a duplicate task should report an error, got %#v", st)
+	}
+
+	pm = newProjectsModel(s)
+	pm, _ = pm.update(pm.refresh()())
+	s.Close()
+	_, cmd = pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if st, ok := batchStatus(cmd); !ok || !st.isError {
+		t.Fatalf("a failed archive should report an error, got %#v", st)
+	}
+}
+
+func TestSettingsExportImport(t *testing.T) {
+	s := newTestStore(t)
+	s.SetSetting("week_start", "sunday")
+	path := filepath.Join(t.TempDir(), settingsFileName)
+	sm := newSettingsModel(s)
+	if st, ok := sm.exportSettings(path)().(statusMsg); !ok || st.isError {
+		t.Fatalf("export failed: %#v", st)
+	}
+
+	other := newTestStore(t)
+	om := newSettingsModel(other)
+	if st, ok := batchStatus(om.importSettings(path)); !ok || st.isError || !containsString(st.text, "Imported") {
+		t.Fatalf("import failed: %#v", st)
+	}
+	if v, _ := other.GetSetting("week_start"); v != "sunday" {
+		t.Fatalf("imported week_start = %q, want sunday", v)
+	}
+	if st, _ := batchStatus(om.importSettings(path + ".missing")); !st.isError {
+		t.Fatal("a missing file should report an error")
+	}
+}
+
+func TestSettingsSnapshot(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("Dev", "#000", "work", "")
+	sm := newSettingsModel(s)
+	sm, _ = sm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	if !sm.formActive || sm.formType != "snapshot" {
+		t.Fatal("B should ask where to export the database")
+	}
+
+	path := filepath.Join(t.TempDir(), "trackr.db")
+	if st, ok := sm.snapshot(path, false)().(statusMsg); !ok || st.isError || !containsString(st.text, path) {
+		t.Fatalf("snapshot failed: %#v", st)
+	}
+	if st, _ := sm.snapshot(path, false)().(statusMsg); !st.isError {
+		t.Fatal("an existing file should not be replaced without confirmation")
+	}
+	if st, _ := sm.snapshot(path, true)().(statusMsg); st.isError {
+		t.Fatalf("confirmed replace failed: %#v", st)
+	}
+	snap, err := store.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Close()
+	if projects, _ := snap.ListProjects(false); len(projects) != 1 {
+		t.Fatalf("the copy should hold the project, got %+v", projects)
+	}
+}
+
+func TestSettingsSaveReportsError(t *testing.T) {
+	s := newTestStore(t)
+	sm := newSettingsModel(s)
+	sm, _ = sm.update(sm.refresh()())
+	if err := sm.saveSettings(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	if err := sm.saveSettings(); err == nil || !containsString(err.Error(), "settings") {
+		t.Fatalf("expected a settings error, got %v", err)
+	}
+}
+
+func TestDashboardLoadReportsError(t *testing.T) {
+	s := newTestStore(t)
+	d := newDashboardModel(s)
+	s.Close()
+	_, cmd := d.update(d.loadData()())
+	if st, ok := batchStatus(cmd); !ok || !st.isError {
+		t.Fatalf("a failed load should report an error, got %#v", st)
+	}
+}
+
+func TestProjectsHourlyRate(t *testing.T) {
+	s := newTestStore(t)
+	pm := newProjectsModel(s)
+	pm, _ = pm.showNewProjectForm()
+	*pm.formName = "Client"
+	*pm.formRate = "75.5"
+	pm.saveProjectForm()
+
+	p, _ := s.GetOrCreateProject("Client")
+	if p.HourlyRate != 75.5 {
+		t.Fatalf("HourlyRate = %v, want 75.5", p.HourlyRate)
+	}
+	if formatRate(p.HourlyRate) != "75.5" || formatRate(0) != "" {
+		t.Fatal("formatRate should round-trip and leave unset rates blank")
+	}
+	if validateRate("") != nil || validateRate("-1") == nil {
+		t.Fatal("blank rates are allowed, negative ones are not")
+	}
+}
+
+// ============================================================
+// Reports model
+// ============================================================
+
+func TestReportsWeekStart(t *testing.T) {
+	s := newTestStore(t)
+	r := newReportsModel(s)
+	r.mode = reportWeekly
+	if from, _ := r.dateRange(); from.Weekday() != time.Monday {
+		t.Fatalf("default week should start on Monday, got %v", from.Weekday())
+	}
+
+	s.SetSetting("week_start", "sunday")
+	r, _ = r.update(r.refresh()())
+	from, to := r.dateRange()
+	if from.Weekday() != time.Sunday {
+		t.Fatalf("week should start on Sunday, got %v", from.Weekday())
+	}
+	if to.Sub(from) < 6*24*time.Hour || from.After(time.Now()) || !to.After(time.Now()) {
+		t.Fatalf("range %v - %v should be the current week", from, to)
+	}
+}
+
+func TestReportsSummaryText(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntry(p.ID, nil)
+	s.StopEntry(e.ID)
+
+	r := newReportsModel(s)
+	r, _ = r.update(r.refresh()())
+
+	text := r.summaryText()
+	if !containsString(text, r.rangeLabel()) || !containsString(text, "Project") || !containsString(text, "Dev") {
+		t.Fatalf("summary should include range, header and rows: %q", text)
+	}
+}
+
+func TestReportsGoalMarker(t *testing.T) {
+	s := newTestStore(t)
+	s.SetSetting("daily_goal", "3600")
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-90*time.Minute))
+	s.StopEntry(e.ID)
+
+	r := newReportsModel(s)
+	r.setSize(120, 40)
+	r, _ = r.update(r.refresh()())
+	if !r.goalMet(3600) || r.goalMet(3599) {
+		t.Fatal("goalMet should compare against daily_goal")
+	}
+	if !containsString(r.chart.View(), "✓") || !containsString(r.view(), "goal met") {
+		t.Fatal("today's bar should be marked as meeting the goal")
+	}
+
+	s.SetSetting("daily_goal", "0")
+	r, _ = r.update(r.refresh()())
+	if containsString(r.chart.View(), "✓") || r.goalMet(1<<40) {
+		t.Fatal("a zero goal should disable the markers")
+	}
+}
+
+func TestReportsBillableTotals(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e1, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-2*time.Hour))
+	s.StopEntry(e1.ID)
+	e2, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+	s.StopEntry(e2.ID)
+	s.SetEntryBillable(e2.ID, false)
+
+	r := newReportsModel(s)
+	r.setSize(100, 40)
+	r, _ = r.update(r.refresh()())
+	if r.billable < 3500 || r.nonBillable < 3500 {
+		t.Fatalf("expected ~1h each, got billable=%d non-billable=%d", r.billable, r.nonBillable)
+	}
+	if !containsString(r.view(), "Non-billable") {
+		t.Fatal("reports should show the billable split")
+	}
+}
+
+func TestReportsRevenue(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Client", "#000", "freelance", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+	s.StopEntry(e.ID)
+
+	r := newReportsModel(s)
+	r, _ = r.update(r.refresh()())
+	if r.renderRevenue() != "" {
+		t.Fatal("revenue should be hidden when no project has a rate")
+	}
+
+	s.SetProjectRate(p.ID, 100)
+	r, _ = r.update(r.refresh()())
+	if got := r.renderRevenue(); !containsString(got, "Client") || !containsString(got, "Revenue") {
+		t.Fatalf("expected a revenue line for the rated project, got %q", got)
+	}
+}
+
+func TestReportsExportSummaries(t *testing.T) {
+	s := newTestStore(t)
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
+	for _, id := range []int64{dev.ID, ops.ID} {
+		e, _ := s.StartEntryAt(id, nil, time.Now().Add(-time.Minute))
+		s.StopEntry(e.ID)
+	}
+
+	r := newReportsModel(s)
+	r, _ = r.setProject(ops.ID)
+	dir := t.TempDir()
+	msg, ok := r.exportSummaries(0, dir)().(exportDoneMsg)
+	if !ok {
+		t.Fatal("expected the report to be exported")
+	}
+	from, _ := r.dateRange()
+	if !containsString(msg.path, "trackr-report-"+from.Format("2006-01-02")) {
+		t.Fatalf("file name should carry the range, got %s", msg.path)
+	}
+	data, _ := os.ReadFile(msg.path)
+	if !containsString(string(data), "Ops") || containsString(string(data), "Dev") {
+		t.Fatalf("export should follow the project filter:\n%s", data)
+	}
+}
+
+func TestReportsTableScrolls(t *testing.T) {
+	s := newTestStore(t)
+	for i := range 20 {
+		p, _ := s.CreateProject(fmt.Sprintf("Project %02d", i), "#000", "work", "")
+		e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Minute))
+		s.StopEntry(e.ID)
+	}
+	r := newReportsModel(s)
+	r.setSize(100, 60)
+	r, _ = r.update(r.refresh()())
+
+	if h := lipgloss.Height(r.view()); h > 60 {
+		t.Fatalf("report should fit in 60 lines, got %d", h)
+	}
+	fit := r.tableFit()
+	if fit <= 0 || fit >= 20 {
+		t.Fatalf("expected the table to be cut, fit = %d", fit)
+	}
+	table := r.renderSummaryTable(96, fit)
+	if !containsString(table, "Project 00") || containsString(table, "Project 19") || !containsString(table, "of 20") {
+		t.Fatalf("the table should start at the top with a position line:\n%s", table)
+	}
+
+	for range 30 {
+		r, _ = r.update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if r.scroll != 20-fit {
+		t.Fatalf("scrolling should stop at the last row, scroll = %d", r.scroll)
+	}
+	table = r.renderSummaryTable(96, fit)
+	if !containsString(table, "Project 19") || containsString(table, "Project 00") {
+		t.Fatalf("scrolling down should reach the last row:\n%s", table)
+	}
+	r, _ = r.update(tea.KeyMsg{Type: tea.KeyUp})
+	if r.scroll != 19-fit {
+		t.Fatalf("one step up should scroll back one row, scroll = %d", r.scroll)
+	}
+
+	r.setSize(100, 0)
+	if r.tableFit() != 0 {
+		t.Fatal("with no known height the whole table should show")
+	}
+}
+
+func TestChartDays(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 7)
+	summaries := []store.DailySummary{
+		{Date: "2024-01-02", TotalSeconds: 3600},
+		{Date: "2024-01-05", TotalSeconds: 60},
+		{Date: "2024-01-06", TotalSeconds: 0},
+	}
+	if got := chartDays(from, to, summaries, false); len(got) != 7 {
+		t.Fatalf("all days should be charted by default, got %d", len(got))
+	}
+	got := chartDays(from, to, summaries, true)
+	if len(got) != 2 || got[0].Day() != 2 || got[1].Day() != 5 {
+		t.Fatalf("only tracked days should be charted, got %v", got)
+	}
+}
+
+func TestReportsHideEmpty(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Minute))
+	s.StopEntry(e.ID)
+	r := newReportsModel(s)
+	r.setSize(100, 0)
+	r, _ = r.update(r.refresh()())
+
+	r, _ = r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if !r.hideEmpty || !containsString(r.view(), "empty days hidden") {
+		t.Fatal("z should hide empty days")
+	}
+	if len(r.tableRows()) != 1 {
+		t.Fatalf("the tracked day should stay listed, got %d rows", len(r.tableRows()))
+	}
+
+	// A week with nothing tracked says so instead of drawing no bars.
+	r, _ = r.update(tea.KeyMsg{Type: tea.KeyLeft})
+	r, _ = r.update(r.refresh()())
+	if !containsString(r.view(), "No tracked days in this period") {
+		t.Fatal("an empty period should be explained when empty days are hidden")
+	}
+}
+
+func TestReportsExportChart(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-2*time.Hour))
+	s.StopEntry(e.ID)
+
+	app := NewApp(s)
+	app.activeView = viewReports
+	app.reports.setSize(100, 40)
+	app.reports, _ = app.reports.update(app.reports.refresh()())
+	if !slices.Contains(app.exportFormats(), "Chart (text)") {
+		t.Fatal("reports should offer a chart export")
+	}
+
+	msg, ok := app.reports.exportChart(t.TempDir())().(exportDoneMsg)
+	if !ok {
+		t.Fatal("expected the chart to be exported")
+	}
+	data, _ := os.ReadFile(msg.path)
+	text := string(data)
+	if !containsString(text, "█") || !containsString(text, "Dev") {
+		t.Fatalf("export should contain the bars and legend:\n%s", text)
+	}
+	if containsString(text, "\x1b[") {
+		t.Fatal("export should not contain ANSI codes")
+	}
+}
+
+func TestExportFileNameTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Minute))
+	s.StopEntry(e.ID)
+	app := NewApp(s)
+
+	today := time.Now().Format("2006-01-02")
+	msg, ok := app.doExport(0)().(exportDoneMsg)
+	if !ok || msg.path != filepath.Join(home, "trackr-export-"+today+".csv") {
+		t.Fatalf("expected the default name, got %+v", msg)
+	}
+
+	s.SetSetting("export_filename_template", "timesheet-{range}.csv")
+	msg, ok = app.doExport(1)().(exportDoneMsg)
+	if want := filepath.Join(home, "timesheet-"+today+"_"+today+".csv.json"); !ok || msg.path != want {
+		t.Fatalf("got %+v, want %s", msg, want)
+	}
+	if _, err := os.Stat(msg.path); err != nil {
+		t.Fatal(err)
+	}
+
+	s.SetSetting("export_filename_template", "..")
+	st, ok := app.doExport(0)().(statusMsg)
+	if !ok || !st.isError {
+		t.Fatalf("a template giving an empty name should be reported, got %+v", st)
+	}
+	if validateFileTemplate("..") == nil || validateFileTemplate("{date}") != nil {
+		t.Fatal("the settings form should reject only empty names")
+	}
+}
+
+func TestReportsHeatmap(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-2*time.Hour))
+	s.StopEntry(e.ID)
+
+	r := newReportsModel(s)
+	r.setSize(40, 30)
+	for r.mode != reportHeatmap {
+		r, _ = r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	}
+	r, _ = r.update(r.refresh()())
+
+	from, to := r.dateRange()
+	if weeks := int(to.Sub(from).Hours()/24+0.5) / 7; weeks != r.heatmapWeeks() || weeks >= maxHeatmapWeeks {
+		t.Fatalf("a narrow terminal should show fewer weeks, got %d", weeks)
+	}
+	view := r.view()
+	if !containsString(view, "█") || !containsString(view, "active days: 1") {
+		t.Fatalf("today's time should be shaded in the heatmap:\n%s", view)
+	}
+	if containsString(view, "Project") {
+		t.Fatal("the heatmap should not list the summary table")
+	}
+
+	r, _ = r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	r, _ = r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if r.mode != reportDaily {
+		t.Fatal("switching mode should cycle back to daily")
+	}
+}
+
+func TestReportsTrend(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-2*time.Hour))
+	s.StopEntry(e.ID)
+
+	r := newReportsModel(s)
+	r.setSize(100, 40)
+	for r.mode != reportTrend {
+		r, _ = r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	}
+	r, _ = r.update(r.refresh()())
+
+	if len(r.weeks) != trendWeeks {
+		t.Fatalf("trend should chart %d weeks, got %d", trendWeeks, len(r.weeks))
+	}
+	view := r.view()
+	if !containsString(view, "█") || !containsString(view, "best week 2h") {
+		t.Fatalf("trend should draw the week and sum it up:\n%s", view)
+	}
+}
+
+func TestReportsTags(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	task, _ := s.CreateTask(p.ID, "Standup", "meetings")
+	e, _ := s.StartEntryAt(p.ID, &task.ID, time.Now().Add(-time.Minute))
+	s.StopEntry(e.ID)
+	e, _ = s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Minute))
+	s.StopEntry(e.ID)
+
+	r := newReportsModel(s)
+	r.setSize(100, 40)
+	for r.mode != reportTags {
+		r, _ = r.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	}
+	r, _ = r.update(r.refresh()())
+
+	from, to := r.dateRange()
+	if from.Day() != 1 || to.Day() != 1 || to.Month() == from.Month() {
+		t.Fatalf("tags should cover a calendar month, got %s — %s", from, to)
+	}
+	view := r.view()
+	if !containsString(view, "#meetings") || !containsString(view, "(untagged)") {
+		t.Fatalf("tags and untagged time should be listed:\n%s", view)
+	}
+}
+
+func TestReportsRecords(t *testing.T) {
+	s := newTestStore(t)
+	r := newReportsModel(s)
+	r, _ = r.update(r.refresh()())
+	if r.renderRecords() != "" {
+		t.Fatal("records should be hidden with no history")
+	}
+
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-time.Hour))
+	s.StopEntry(e.ID)
+	r, _ = r.update(r.refresh()())
+	got := r.renderRecords()
+	for _, want := range []string{"Longest session", "Biggest day", "Most tracked", "Dev", "Daily average"} {
+		if !containsString(got, want) {
+			t.Fatalf("records should mention %q:\n%s", want, got)
+		}
+	}
+
+	// An hour today meets a one-hour goal on one of the last 30 days.
+	s.SetSetting("daily_goal", "3600")
+	r, _ = r.update(r.refresh()())
+	if got := r.renderRecords(); !containsString(got, "1 of 30 days") {
+		t.Fatalf("records should count the days meeting the goal:\n%s", got)
+	}
+}
+
+func TestReportsProjectFilter(t *testing.T) {
 	s := newTestStore(t)
-	p, _ := s.CreateProject("Solo", "#000", "work")
+	dev, _ := s.CreateProject("Dev", "#000", "work", "")
+	ops, _ := s.CreateProject("Ops", "#111", "work", "")
+	for _, id := range []int64{dev.ID, ops.ID} {
+		e, _ := s.StartEntryAt(id, nil, time.Now().Add(-time.Minute))
+		s.StopEntry(e.ID)
+	}
 
-	d := newDashboardModel(s)
-	d.projects = []store.Project{*p}
+	app := NewApp(s)
+	app.activeView = viewReports
+	app.reports.setSize(100, 40)
+	app.reports, _ = app.reports.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if !app.reports.formActive || !app.isFormActive() {
+		t.Fatal("f should open the project filter and capture input")
+	}
+
+	// Picking Ops in the form ends up here.
+	app.reports.formActive = false
+	r, cmd := app.reports.setProject(ops.ID)
+	r, _ = r.update(cmd())
+	for _, sum := range r.summaries {
+		if sum.ProjectID != ops.ID {
+			t.Fatalf("filtered reports should only show Ops, got %+v", r.summaries)
+		}
+	}
+	if len(r.summaries) == 0 || !containsString(r.view(), "Ops") || containsString(r.renderLegend(), "Dev") {
+		t.Fatal("header and legend should reflect the Ops filter")
+	}
 
-	// With only one project, pressing start should auto-select it
-	// (no picker shown)
-	if d.picking {
-		t.Fatal("should not be in picker mode initially")
+	r, cmd = r.setProject(0)
+	r, _ = r.update(cmd())
+	if !containsString(r.renderLegend(), "Dev") || !containsString(r.view(), "All projects") {
+		t.Fatal("clearing the filter should show all projects again")
 	}
 }
 
@@ -601,6 +2677,77 @@ func TestFormatSettingValue(t *testing.T) {
 	}
 }
 
+func TestSettingsRenameCategory(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("A", "#111", "work", "")
+	s.CreateProject("B", "#222", "Work", "")
+
+	sm := newSettingsModel(s)
+	sm, _ = sm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if !sm.formActive || sm.formType != "category" {
+		t.Fatal("c should open the category form")
+	}
+	*sm.categoryFrom = "Work"
+	*sm.categoryTo = " work "
+	if m, ok := sm.renameCategory()().(statusMsg); !ok || m.isError {
+		t.Fatalf("rename should succeed, got %#v", m)
+	}
+	if cats, _ := s.ListCategories(); len(cats) != 1 || cats[0] != "work" {
+		t.Fatalf("categories should be merged, got %v", cats)
+	}
+
+	// Renamed categories stay selectable in the project form.
+	s.RenameCategory("work", "clients")
+	pm := newProjectsModel(s)
+	if !slices.Contains(pm.categories(), "clients") {
+		t.Fatal("in-use categories should be offered in the project form")
+	}
+}
+
+func TestSettingsPruneEntries(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().AddDate(-2, 0, 0))
+	s.StopEntry(e.ID)
+
+	sm := newSettingsModel(s)
+	sm, _ = sm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	if !sm.formActive || sm.formType != "prune" {
+		t.Fatal("D should open the prune form")
+	}
+	if m, ok := sm.pruneEntries()().(statusMsg); !ok || m.text != "No entries deleted" {
+		t.Fatalf("nothing should be deleted without confirmation, got %#v", m)
+	}
+	if _, err := s.GetEntry(e.ID); err != nil {
+		t.Fatal("entry should still exist")
+	}
+
+	*sm.pruneConfirm = true
+	if m, ok := sm.pruneEntries()().(statusMsg); !ok || !containsString(m.text, "Deleted 1") {
+		t.Fatalf("expected one entry deleted, got %#v", m)
+	}
+	if _, err := s.GetEntry(e.ID); err == nil {
+		t.Fatal("entry should be deleted")
+	}
+}
+
+func TestSettingsCompact(t *testing.T) {
+	sm := newSettingsModel(newTestStore(t))
+	_, cmd := sm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if cmd == nil {
+		t.Fatal("C should compact the database")
+	}
+	if m, ok := cmd().(statusMsg); !ok || m.isError || !containsString(m.text, "Compacted database") {
+		t.Fatalf("expected a size report, got %#v", m)
+	}
+
+	for n, want := range map[int64]string{512: "512 B", 2048: "2.0 KB", 3 << 20: "3.0 MB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
 // ============================================================
 // Pomodoro model
 // ============================================================
@@ -626,6 +2773,41 @@ func TestPomodoroInit(t *testing.T) {
 	}
 }
 
+func TestPomodoroInvalidSettings(t *testing.T) {
+	for _, v := range []string{"0", "-5"} {
+		s := newTestStore(t)
+		s.SetSetting("pomodoro_work", v)
+		s.SetSetting("pomodoro_break", v)
+		s.SetSetting("pomodoro_long_break", v)
+		s.SetSetting("pomodoro_count", v)
+		pm := newPomodoroModel(s)
+
+		if pm.workDuration != 25*time.Minute || pm.breakDuration != 5*time.Minute || pm.longBreakDuration != 15*time.Minute {
+			t.Fatalf("%s: expected default durations, got %v/%v/%v", v, pm.workDuration, pm.breakDuration, pm.longBreakDuration)
+		}
+		if pm.targetCount != 4 {
+			t.Fatalf("%s: expected default target count, got %d", v, pm.targetCount)
+		}
+	}
+}
+
+func TestSettingsValidators(t *testing.T) {
+	for _, v := range []string{"0", "-5", "abc", ""} {
+		if validatePositiveInt(v) == nil {
+			t.Errorf("validatePositiveInt(%q) should fail", v)
+		}
+		if validatePositiveFloat(v) == nil {
+			t.Errorf("validatePositiveFloat(%q) should fail", v)
+		}
+	}
+	if validatePositiveInt("25") != nil || validatePositiveFloat("7.5") != nil {
+		t.Error("valid values should pass")
+	}
+	if validateNonNegativeFloat("0") != nil || validateNonNegativeFloat("-1") == nil {
+		t.Error("validateNonNegativeFloat should allow 0 and reject negatives")
+	}
+}
+
 func TestPomodoroStartSession(t *testing.T) {
 	s := newTestStore(t)
 	pm := newPomodoroModel(s)
@@ -662,6 +2844,83 @@ func TestPomodoroCancelSession(t *testing.T) {
 	}
 }
 
+func TestPomodoroPause(t *testing.T) {
+	s := newTestStore(t)
+	pm := newPomodoroModel(s)
+	pm.setSize(100, 40)
+	pm, _ = pm.startSession()
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	pm, _ = pm.update(space)
+	if !pm.paused || pm.timedPhase() {
+		t.Fatal("space should pause the work countdown")
+	}
+	if !containsString(pm.view(), "PAUSED") {
+		t.Fatal("the view should show the pause")
+	}
+	before := pm.remaining
+
+	// Ten minutes pass while paused; ticks leave the countdown alone.
+	pm.pausedAt = pm.pausedAt.Add(-10 * time.Minute)
+	pm.phaseEnd = pm.phaseEnd.Add(-10 * time.Minute)
+	pm, _ = pm.update(tickMsg(time.Now()))
+	if pm.remaining != before || pm.phase != pomodoroWork {
+		t.Fatalf("a tick while paused changed remaining from %v to %v", before, pm.remaining)
+	}
+
+	pm, _ = pm.update(space)
+	if pm.paused {
+		t.Fatal("space should resume")
+	}
+	if pm.remaining < before-time.Second {
+		t.Fatalf("remaining shrank across the pause: %v -> %v", before, pm.remaining)
+	}
+	pm, _ = pm.update(tickMsg(time.Now()))
+	if pm.remaining < before-time.Second || pm.phase != pomodoroWork {
+		t.Fatalf("the countdown should carry on from %v, got %v in phase %v", before, pm.remaining, pm.phase)
+	}
+}
+
+func TestPomodoroHistory(t *testing.T) {
+	s := newTestStore(t)
+	pm := newPomodoroModel(s)
+	pm.setSize(100, 40)
+	pm, _ = pm.startSession()
+	pm, _ = pm.cancelSession()
+	pm, _ = pm.startSession()
+	pm, _ = pm.update(pm.refresh()())
+
+	if len(pm.history) != 2 {
+		t.Fatalf("expected 2 sessions in the history, got %d", len(pm.history))
+	}
+	view := pm.view()
+	for _, want := range []string{"History", "in progress", "cancelled", "0/4"} {
+		if !containsString(view, want) {
+			t.Fatalf("history should show %q:\n%s", want, view)
+		}
+	}
+
+	// The running session is first and can't be deleted.
+	_, cmd := pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if st, _ := batchStatus(cmd); !st.isError {
+		t.Fatal("deleting the session in progress should be refused")
+	}
+
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyDown})
+	cancelled := pm.history[1].ID
+	pm, cmd = pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if st, _ := batchStatus(cmd); st.isError {
+		t.Fatalf("delete failed: %s", st.text)
+	}
+	if _, err := s.GetPomodoro(cancelled); err == nil {
+		t.Fatal("the cancelled session should be deleted")
+	}
+	pm, _ = pm.update(pm.refresh()())
+	if len(pm.history) != 1 || pm.historyCursor != 0 {
+		t.Fatalf("history should shrink and keep the cursor in range, got %d / %d", len(pm.history), pm.historyCursor)
+	}
+}
+
 func TestPomodoroAdvanceWorkToBreak(t *testing.T) {
 	s := newTestStore(t)
 	pm := newPomodoroModel(s)
@@ -696,6 +2955,130 @@ func TestPomodoroAdvanceBreakToWork(t *testing.T) {
 	}
 }
 
+func TestPomodoroBell(t *testing.T) {
+	var out bytes.Buffer
+	bellOut = &out
+	t.Cleanup(func() { bellOut = os.Stdout })
+
+	s := newTestStore(t)
+	pm := newPomodoroModel(s)
+	pm, _ = pm.startSession()
+
+	// advance runs the phase change's batched commands and returns the
+	// status text.
+	advance := func() string {
+		var cmd tea.Cmd
+		pm, cmd = pm.advancePhase()
+		text := ""
+		for _, c := range cmd().(tea.BatchMsg) {
+			if st, ok := c().(statusMsg); ok {
+				text = st.text
+			}
+		}
+		return text
+	}
+
+	if text := advance(); text != "Break time!" {
+		t.Fatalf("status should be plain text, got %q", text)
+	}
+	if out.String() != "\a" {
+		t.Fatalf("bell should ring by default, got %q", out.String())
+	}
+
+	out.Reset()
+	s.SetSetting("sound", "false")
+	pm, _ = pm.advancePhase() // back to work, which starts silently
+	advance()
+	if out.Len() != 0 {
+		t.Fatal("bell should stay quiet with sound off")
+	}
+}
+
+func TestPomodoroCatchUpAfterSleep(t *testing.T) {
+	s := newTestStore(t)
+	s.SetSetting("pomodoro_count", "4")
+	pm := newPomodoroModel(s)
+	pm, _ = pm.startSession()
+
+	// Work (25m) + break (5m) + work (25m) elapsed, 2 minutes into the
+	// second break.
+	pm.phaseEnd = time.Now().Add(-(5 + 25 + 2) * time.Minute)
+	pm, _ = pm.update(tickMsg(time.Now()))
+
+	if pm.phase != pomodoroShortBreak || pm.completedCount != 2 {
+		t.Fatalf("expected second short break, got phase %d with %d completed", pm.phase, pm.completedCount)
+	}
+	if pm.remaining <= 2*time.Minute || pm.remaining > 3*time.Minute {
+		t.Fatalf("expected ~3m remaining, got %v", pm.remaining)
+	}
+}
+
+func TestPomodoroCatchUpToCompletion(t *testing.T) {
+	s := newTestStore(t)
+	s.SetSetting("pomodoro_count", "2")
+	pm := newPomodoroModel(s)
+	pm, _ = pm.startSession()
+
+	pm.phaseEnd = time.Now().Add(-24 * time.Hour)
+	pm, _ = pm.update(tickMsg(time.Now()))
+
+	if pm.phase != pomodoroCompleted || pm.completedCount != 2 {
+		t.Fatalf("expected completed session, got phase %d with %d completed", pm.phase, pm.completedCount)
+	}
+}
+
+func TestPomodoroAutoStartDefaults(t *testing.T) {
+	s := newTestStore(t)
+	pm := newPomodoroModel(s)
+	if !pm.autoStartBreak || !pm.autoStartWork {
+		t.Fatal("auto-start should default to on")
+	}
+}
+
+func TestPomodoroWaitForBreak(t *testing.T) {
+	s := newTestStore(t)
+	s.SetSetting("pomodoro_auto_start_break", "false")
+	pm := newPomodoroModel(s)
+	pm, _ = pm.startSession()
+
+	pm, _ = pm.advancePhase()
+	if pm.phase != pomodoroShortBreak || !pm.waiting {
+		t.Fatalf("expected to wait before short break, got phase %d waiting=%v", pm.phase, pm.waiting)
+	}
+
+	// Ticks must not advance a waiting phase
+	pm, _ = pm.update(tickMsg(time.Now().Add(time.Hour)))
+	if pm.phase != pomodoroShortBreak || pm.remaining != 5*time.Minute {
+		t.Fatal("waiting phase should not count down")
+	}
+
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if pm.waiting || !pm.phaseEnd.After(time.Now()) {
+		t.Fatal("start key should begin the break countdown")
+	}
+}
+
+func TestPomodoroWaitForWork(t *testing.T) {
+	s := newTestStore(t)
+	s.SetSetting("pomodoro_auto_start_work", "false")
+	pm := newPomodoroModel(s)
+	pm, _ = pm.startSession()
+
+	pm, _ = pm.advancePhase() // work -> break (auto)
+	if pm.waiting {
+		t.Fatal("break should auto-start")
+	}
+	pm, _ = pm.advancePhase() // break -> waiting for work
+	if pm.phase != pomodoroWork || !pm.waiting {
+		t.Fatalf("expected to wait before work, got phase %d waiting=%v", pm.phase, pm.waiting)
+	}
+
+	pm, _ = pm.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if pm.waiting || pm.phase != pomodoroWork {
+		t.Fatal("start key should begin the work phase")
+	}
+}
+
 func TestPomodoroFullCycle(t *testing.T) {
 	s := newTestStore(t)
 	s.SetSetting("pomodoro_count", "2") // shorter cycle for test
@@ -807,6 +3190,61 @@ func TestAppIsFormActiveDefault(t *testing.T) {
 	}
 }
 
+func TestAppGoToProjectTasks(t *testing.T) {
+	s := newTestStore(t)
+	s.CreateProject("Alpha", "#000", "work", "")
+	b, _ := s.CreateProject("Beta", "#000", "work", "")
+	s.CreateTask(b.ID, "Follow-up", "")
+
+	app := NewApp(s)
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")}); cmd != nil {
+		t.Fatal("g should do nothing without a running or recent project")
+	}
+
+	app.dashboard, _ = app.dashboard.startTimer(b.ID, "Beta", nil, "")
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m, cmd := app.Update(cmd())
+	app = m.(App)
+	if app.activeView != viewProjects {
+		t.Fatal("g should switch to the projects view")
+	}
+	m, cmd = app.Update(cmd())
+	m, _ = m.(App).Update(cmd())
+	app = m.(App)
+	if !app.projects.viewingTasks || app.projects.projects[app.projects.cursor].ID != b.ID {
+		t.Fatal("the running project's tasks should be open")
+	}
+	if len(app.projects.tasks) != 1 {
+		t.Fatalf("expected Beta's task to load, got %d", len(app.projects.tasks))
+	}
+	app.dashboard.stopTimer()
+}
+
+func TestAppTickRate(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	app := NewApp(s)
+
+	if app.tickRate() != slowTick {
+		t.Fatal("idle app should use the slow tick")
+	}
+
+	app.dashboard.timer.start(p.ID, "Dev", nil, "")
+	if app.tickRate() != fastTick {
+		t.Fatal("running timer should use the fast tick")
+	}
+	app.dashboard.timer.pause()
+	if app.tickRate() != slowTick {
+		t.Fatal("paused timer should use the slow tick")
+	}
+	app.dashboard.timer.stop()
+
+	app.pomodoro, _ = app.pomodoro.startSession()
+	if app.tickRate() != fastTick {
+		t.Fatal("active pomodoro phase should use the fast tick")
+	}
+}
+
 func TestAppViewStates(t *testing.T) {
 	s := newTestStore(t)
 	app := NewApp(s)
@@ -873,6 +3311,136 @@ func TestAppStatusMessage(t *testing.T) {
 	}
 }
 
+func TestAppQuitWhenStopped(t *testing.T) {
+	s := newTestStore(t)
+	app := NewApp(s)
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("q with no running timer should quit immediately")
+	}
+}
+
+func TestAppQuitConfirmWhileRunning(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	app := NewApp(s)
+	app.dashboard, _ = app.dashboard.startTimer(p.ID, "Dev", nil, "")
+
+	m, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	app = m.(App)
+	if cmd != nil {
+		t.Fatal("q with a running timer should not quit immediately")
+	}
+	if !app.quitConfirm {
+		t.Fatal("expected quit confirmation")
+	}
+
+	// Default option is "Stop and quit"
+	m, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app = m.(App)
+	if app.dashboard.isRunning() {
+		t.Fatal("timer should be stopped")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("stop and quit should quit")
+	}
+	running, _ := s.GetRunningEntry()
+	if running != nil {
+		t.Fatal("entry should be stopped in the DB")
+	}
+}
+
+func TestAppQuitConfirmStopsAllTimers(t *testing.T) {
+	t.Cleanup(func() { multiTimer = false })
+	s := newTestStore(t)
+	build, _ := s.CreateProject("Build", "#111", "work", "")
+	code, _ := s.CreateProject("Code", "#222", "work", "")
+	s.SetSetting("multi_timer", "true")
+	app := NewApp(s)
+	app.dashboard, _ = app.dashboard.startTimer(build.ID, "Build", nil, "")
+	app.dashboard, _ = app.dashboard.startTimer(code.ID, "Code", nil, "")
+	if app.dashboard.activeTimers() != 2 {
+		t.Fatalf("expected two timers, got %d", app.dashboard.activeTimers())
+	}
+
+	m, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app = m.(App)
+	if cmd == nil {
+		t.Fatal("stop and quit should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("stop and quit should quit")
+	}
+	if app.dashboard.isRunning() || app.dashboard.activeTimers() != 0 {
+		t.Fatal("every timer should be stopped")
+	}
+	if running, _ := s.ListRunningEntries(); len(running) != 0 {
+		t.Fatalf("no entry should be left running, got %d", len(running))
+	}
+}
+
+func TestTerminalTitle(t *testing.T) {
+	t.Cleanup(func() { terminalTitle = false })
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	e, _ := s.StartEntryAt(p.ID, nil, time.Now().Add(-83*time.Minute))
+
+	// Off by default: ticks leave the title alone.
+	app := NewApp(s)
+	app.dashboard.timer.reconcile()
+	m, _ := app.Update(tickMsg(time.Now()))
+	app = m.(App)
+	if app.title != "" {
+		t.Fatalf("title should stay unset by default, got %q", app.title)
+	}
+
+	s.SetSetting("set_terminal_title", "true")
+	app = NewApp(s)
+	app.dashboard.timer.reconcile()
+	m, _ = app.Update(tickMsg(time.Now()))
+	app = m.(App)
+	if app.title != "trackr — Dev 01:23" {
+		t.Fatalf("unexpected title %q", app.title)
+	}
+
+	// Quitting clears the title before exiting.
+	_, cmd := app.quit()
+	if _, ok := cmd().(tea.QuitMsg); ok {
+		t.Fatal("quit should clear the title first")
+	}
+
+	// Stopping the timer clears it on the next tick.
+	s.StopEntry(e.ID)
+	app.dashboard.timer.reconcile()
+	m, cmd = app.Update(tickMsg(time.Now()))
+	app = m.(App)
+	if app.title != "" || cmd == nil {
+		t.Fatalf("title should be cleared once the timer stops, got %q", app.title)
+	}
+}
+
+func TestAppQuitConfirmCancel(t *testing.T) {
+	s := newTestStore(t)
+	p, _ := s.CreateProject("Dev", "#000", "work", "")
+	app := NewApp(s)
+	app.dashboard, _ = app.dashboard.startTimer(p.ID, "Dev", nil, "")
+
+	m, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app = m.(App)
+	if app.quitConfirm {
+		t.Fatal("esc should close the confirmation")
+	}
+	if !app.dashboard.isRunning() {
+		t.Fatal("cancel should leave the timer running")
+	}
+}
+
 // containsString checks if s contains substr, ignoring ANSI escape codes.
 func containsString(s, substr string) bool {
 	// Simple check — ANSI codes don't affect the raw string contains
@@ -911,6 +3479,36 @@ func TestKeyMapFullHelp(t *testing.T) {
 	}
 }
 
+func TestViewHelp(t *testing.T) {
+	descs := func(v viewState) []string {
+		var out []string
+		for _, g := range (viewHelp{view: v}).FullHelp() {
+			for _, b := range g {
+				out = append(out, b.Help().Desc)
+			}
+		}
+		return out
+	}
+	for v := range viewNames {
+		got := descs(viewState(v))
+		if !slices.Contains(got, "quit") || !slices.Contains(got, "projects") {
+			t.Errorf("%s help should keep the global bindings, got %v", viewNames[v], got)
+		}
+	}
+	if !slices.Contains(descs(viewReports), "previous period") {
+		t.Error("reports help should explain ←/→")
+	}
+	if slices.Contains(descs(viewDashboard), "previous period") {
+		t.Error("dashboard help should not list report navigation")
+	}
+	if !slices.Contains(descs(viewPomodoro), "pause/skip break") {
+		t.Error("pomodoro help should describe space as pausing or skipping a break")
+	}
+	if keys.Pause.Help().Desc != "pause/resume" {
+		t.Error("relabeling must not change the shared binding")
+	}
+}
+
 // ============================================================
 // Styles (smoke test — just verify they don't panic)
 // ============================================================
@@ -948,3 +3546,94 @@ func TestStylesRender(t *testing.T) {
 		}
 	}
 }
+
+func TestMultiTimer(t *testing.T) {
+	t.Cleanup(func() { multiTimer = false })
+	s := newTestStore(t)
+	build, _ := s.CreateProject("Build", "#111", "work", "")
+	code, _ := s.CreateProject("Code", "#222", "work", "")
+
+	// Off by default: s does nothing while a timer runs.
+	d := newDashboardModel(s)
+	d, _ = d.update(d.loadData()())
+	d, _ = d.startTimer(build.ID, "Build", nil, "")
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if d.picking {
+		t.Fatal("s should not offer a second timer by default")
+	}
+	d, _ = d.stopTimer()
+
+	s.SetSetting("multi_timer", "true")
+	loadMultiTimer(s)
+	d = newDashboardModel(s)
+	d.setSize(100, 40)
+	d, _ = d.update(d.loadData()())
+	d, _ = d.startTimer(build.ID, "Build", nil, "")
+	d, _ = d.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if !d.picking {
+		t.Fatal("s should open the picker while a timer runs")
+	}
+	d.picking = false
+	d, _ = d.startTimer(code.ID, "Code", nil, "")
+	if d.activeTimers() != 2 || d.timer.projectName != "Code" {
+		t.Fatalf("the new timer should be selected beside the first: %d, %q", d.activeTimers(), d.timer.projectName)
+	}
+	if running, _ := s.ListRunningEntries(); len(running) != 2 {
+		t.Fatalf("both entries should run, got %d", len(running))
+	}
+	if !containsString(d.view(), "Also running (1)") {
+		t.Fatal("the other timer should be stacked on the dashboard")
+	}
+
+	d.nextTimer()
+	if d.timer.projectName != "Build" || d.timers[0].projectName != "Code" {
+		t.Fatalf("v should select the other timer, got %q", d.timer.projectName)
+	}
+
+	// Stopping the selected timer selects the remaining one.
+	d, _ = d.stopTimer()
+	if d.activeTimers() != 1 || d.timer.projectName != "Code" {
+		t.Fatalf("expected Code to be selected after stopping Build, got %q", d.timer.projectName)
+	}
+
+	// A restart picks up every running timer, and one stopped from the
+	// command line drops out.
+	d, _ = d.startTimer(build.ID, "Build", nil, "")
+	d = newDashboardModel(s)
+	if d.activeTimers() != 2 {
+		t.Fatalf("restart should restore both timers, got %d", d.activeTimers())
+	}
+	s.StopEntry(d.timers[0].entryID)
+	if err := d.reconcileTimers(); err != nil {
+		t.Fatal(err)
+	}
+	if d.activeTimers() != 1 || len(d.timers) != 0 {
+		t.Fatalf("stopped timer should drop out, got %d", d.activeTimers())
+	}
+}
+
+func TestDurationFormatSetting(t *testing.T) {
+	t.Cleanup(func() { durationFormat = export.DurationHMS })
+	s := newTestStore(t)
+	tests := []struct {
+		setting string
+		want    string
+	}{
+		{"", "01:30:00"},
+		{"hms", "01:30:00"},
+		{"compact", "1h30m"},
+		{"decimal", "1.5h"},
+		{"bogus", "01:30:00"},
+	}
+	for _, tt := range tests {
+		s.SetSetting("duration_format", tt.setting)
+		loadDurationFormat(s)
+		if got := formatSeconds(5400); got != tt.want {
+			t.Errorf("duration_format %q: formatSeconds(5400) = %q, want %q", tt.setting, got, tt.want)
+		}
+	}
+	// Running clocks always tick in HH:MM:SS.
+	if got := formatDuration(90 * time.Minute); got != "01:30:00" {
+		t.Errorf("formatDuration should ignore the setting, got %q", got)
+	}
+}
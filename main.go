@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,19 +13,45 @@ import (
 )
 
 func main() {
-	dbPath, err := store.DefaultDBPath()
+	fs := flag.NewFlagSet("trackr", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	dbFlag := fs.String("db", "", "")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Println(usage)
+			return
+		}
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	dbPath, err := store.ResolveDBPath(*dbFlag)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
 	s, err := store.New(dbPath)
+	if errors.Is(err, store.ErrSchemaTooNew) {
+		fmt.Fprintf(os.Stderr, "error: %s: %v\n", dbPath, err)
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error opening database: %v\n", err)
 		os.Exit(1)
 	}
 	defer s.Close()
 
+	// Any arguments select a non-interactive subcommand instead of the TUI.
+	if args := fs.Args(); len(args) > 0 {
+		if err := runCommand(s, args, os.Stdout); err != nil {
+			s.Close()
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app := tui.NewApp(s)
 	p := tea.NewProgram(app, tea.WithAltScreen())
 